@@ -15,6 +15,8 @@
 // Package backend implements Zero Knowledge Proof systems: it consumes circuit compiled with gnark/frontend.
 package backend
 
+import "fmt"
+
 // ID represent a unique ID for a proving scheme
 type ID uint16
 
@@ -23,3 +25,34 @@ const (
 	GROTH16
 	PLONK
 )
+
+// UnsatisfiedConstraintError is returned by the solvers of the constraint systems
+// (groth16.IsSolved, plonk.IsSolved, and the provers) when a constraint doesn't hold for the witness
+//
+// it wraps the ErrUnsatisfiedConstraint error of the curve backend
+type UnsatisfiedConstraintError struct {
+	// ConstraintID is the index of the failing constraint, in the constraints then the assertions of the constraint system
+	ConstraintID int
+
+	// DebugInfo describes the failure: the debug message of the constraint with the values of its wires,
+	// as set by the circuit (e.g. cs.AssertIsEqual), or the reason the solver failed
+	DebugInfo string
+
+	// Wires are the IDs of the wires of the constraint, in the wire values of the constraint system:
+	// [public | secret | internal] for PLONK, [ONE_WIRE | public | secret | internal] for Groth16
+	Wires []int
+
+	// Err is the ErrUnsatisfiedConstraint of the curve backend
+	Err error
+}
+
+func (e *UnsatisfiedConstraintError) Error() string {
+	if e.DebugInfo == "" {
+		return fmt.Sprintf("%s: constraint #%d", e.Err, e.ConstraintID)
+	}
+	return fmt.Sprintf("%s: constraint #%d: %s", e.Err, e.ConstraintID, e.DebugInfo)
+}
+
+func (e *UnsatisfiedConstraintError) Unwrap() error {
+	return e.Err
+}
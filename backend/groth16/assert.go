@@ -175,3 +175,40 @@ func IsSolved(r1cs frontend.CompiledConstraintSystem, witness frontend.Circuit)
 		panic("unrecognized R1CS curve type")
 	}
 }
+
+// ReadAndIsSolved behaves like IsSolved, except witness is read from a io.Reader
+// witness must be the binary encoded witness, see gnark/backend/witness package.
+// If the witness doesn't solve the constraint system, the returned error is a *backend.UnsatisfiedConstraintError
+func ReadAndIsSolved(r1cs frontend.CompiledConstraintSystem, witness io.Reader) error {
+	_, nbSecret, nbPublic := r1cs.GetNbVariables()
+	expectedSize := nbSecret + nbPublic - 1
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls12377.R1CS:
+		w := witness_bls12377.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _r1cs.IsSolved(w)
+	case *backend_bls12381.R1CS:
+		w := witness_bls12381.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _r1cs.IsSolved(w)
+	case *backend_bn254.R1CS:
+		w := witness_bn254.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _r1cs.IsSolved(w)
+	case *backend_bw6761.R1CS:
+		w := witness_bw6761.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _r1cs.IsSolved(w)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
@@ -20,6 +20,7 @@
 package groth16
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -144,6 +145,88 @@ func ReadAndVerify(proof Proof, vk VerifyingKey, publicWitness io.Reader) error
 	}
 }
 
+// ReadAndBatchVerify verifies the proofs with given VerifyingKey, publicWitnesses[i] being the public witness of proofs[i]
+// the witnesses are encoded following the binary serialization protocol described in gnark/backend/witness package
+//
+// the pairing checks are batched with a random linear combination, which is cheaper than
+// calling ReadAndVerify for each proof. It returns the error of each proof, nil if the proof is valid
+func ReadAndBatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []io.Reader) ([]error, error) {
+	if len(proofs) != len(publicWitnesses) {
+		return nil, fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	errs := make([]error, len(proofs))
+
+	// proofs whose witness can't be read are left out of the batch
+	var (
+		batch     []int
+		batchErrs []error
+		err       error
+	)
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		var _proofs []*groth16_bls12377.Proof
+		var _witnesses []witness_bls12377.Witness
+		for i := range proofs {
+			w := witness_bls12377.Witness{}
+			if _, errs[i] = w.LimitReadFrom(publicWitnesses[i], vk.SizePublicWitness()); errs[i] != nil {
+				continue
+			}
+			_proofs = append(_proofs, proofs[i].(*groth16_bls12377.Proof))
+			_witnesses = append(_witnesses, w)
+			batch = append(batch, i)
+		}
+		batchErrs, err = groth16_bls12377.BatchVerify(_proofs, _vk, _witnesses)
+	case *groth16_bls12381.VerifyingKey:
+		var _proofs []*groth16_bls12381.Proof
+		var _witnesses []witness_bls12381.Witness
+		for i := range proofs {
+			w := witness_bls12381.Witness{}
+			if _, errs[i] = w.LimitReadFrom(publicWitnesses[i], vk.SizePublicWitness()); errs[i] != nil {
+				continue
+			}
+			_proofs = append(_proofs, proofs[i].(*groth16_bls12381.Proof))
+			_witnesses = append(_witnesses, w)
+			batch = append(batch, i)
+		}
+		batchErrs, err = groth16_bls12381.BatchVerify(_proofs, _vk, _witnesses)
+	case *groth16_bn254.VerifyingKey:
+		var _proofs []*groth16_bn254.Proof
+		var _witnesses []witness_bn254.Witness
+		for i := range proofs {
+			w := witness_bn254.Witness{}
+			if _, errs[i] = w.LimitReadFrom(publicWitnesses[i], vk.SizePublicWitness()); errs[i] != nil {
+				continue
+			}
+			_proofs = append(_proofs, proofs[i].(*groth16_bn254.Proof))
+			_witnesses = append(_witnesses, w)
+			batch = append(batch, i)
+		}
+		batchErrs, err = groth16_bn254.BatchVerify(_proofs, _vk, _witnesses)
+	case *groth16_bw6761.VerifyingKey:
+		var _proofs []*groth16_bw6761.Proof
+		var _witnesses []witness_bw6761.Witness
+		for i := range proofs {
+			w := witness_bw6761.Witness{}
+			if _, errs[i] = w.LimitReadFrom(publicWitnesses[i], vk.SizePublicWitness()); errs[i] != nil {
+				continue
+			}
+			_proofs = append(_proofs, proofs[i].(*groth16_bw6761.Proof))
+			_witnesses = append(_witnesses, w)
+			batch = append(batch, i)
+		}
+		batchErrs, err = groth16_bw6761.BatchVerify(_proofs, _vk, _witnesses)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range batch {
+		errs[i] = batchErrs[j]
+	}
+	return errs, nil
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // If force flag is set, executes all the prover computations, even if the witness is invalid
@@ -15,6 +15,7 @@
 package plonk
 
 import (
+	"io"
 	"testing"
 
 	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
@@ -66,7 +67,12 @@ func (assert *Assert) ProverFailed(sparseR1cs frontend.CompiledConstraintSystem,
 	assert.NoError(err, "Generating public data should not have failed")
 
 	// generates the proof
-	proof, err := Prove(sparseR1cs, publicData, witness)
+	_, err = Prove(sparseR1cs, publicData, witness)
+	assert.Error(err, "Proving with bad witness should output an error")
+
+	// generates the proof anyway
+	proof, err := Prove(sparseR1cs, publicData, witness, true)
+	assert.NoError(err, "Proving with bad witness in force mode should not output an error")
 
 	// verifies the proof
 	err = Verify(proof, publicData, witness)
@@ -116,3 +122,40 @@ func IsSolved(sparseR1cs frontend.CompiledConstraintSystem, witness frontend.Cir
 		panic("WIP")
 	}
 }
+
+// ReadAndIsSolved behaves like IsSolved, except witness is read from a io.Reader
+// witness must be the binary encoded full witness [public | secret], see gnark/backend/witness package.
+// If the witness doesn't solve the constraint system, the returned error is a *backend.UnsatisfiedConstraintError
+func ReadAndIsSolved(sparseR1cs frontend.CompiledConstraintSystem, witness io.Reader) error {
+	_, nbSecret, nbPublic := sparseR1cs.GetNbVariables()
+	expectedSize := nbSecret + nbPublic
+
+	switch _sparseR1cs := sparseR1cs.(type) {
+	case *backend_bls12377.SparseR1CS:
+		w := witness_bls12377.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _sparseR1cs.IsSolved(w)
+	case *backend_bls12381.SparseR1CS:
+		w := witness_bls12381.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _sparseR1cs.IsSolved(w)
+	case *backend_bn254.SparseR1CS:
+		w := witness_bn254.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _sparseR1cs.IsSolved(w)
+	case *backend_bw6761.SparseR1CS:
+		w := witness_bw6761.Witness{}
+		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
+			return err
+		}
+		return _sparseR1cs.IsSolved(w)
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
@@ -0,0 +1,80 @@
+// +build gofuzz
+
+package plonk
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	mockcommitment_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial/mockcommitment"
+	mockcommitment_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial/mockcommitment"
+	"github.com/consensys/gnark/frontend"
+	backend_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	plonk_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/plonk"
+	witness_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/witness"
+	backend_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
+	plonk_bn254 "github.com/consensys/gnark/internal/backend/bn254/plonk"
+	witness_bn254 "github.com/consensys/gnark/internal/backend/bn254/witness"
+)
+
+// Fuzz compiles the circuits of frontend.SparseCsFuzzed (including lookups and cubic gates), and checks that a witness
+// derived from data either solves the circuit and gives a verifying proof, or fails
+// both in the solver and in the verifier (the proof being computed in force mode).
+func Fuzz(data []byte) int {
+	curves := []ecc.ID{ecc.BN254, ecc.BLS12_381}
+	for _, curveID := range curves {
+		ccs, _ := frontend.SparseCsFuzzed(data, curveID)
+		ccs.SetLoggerOutput(nil)
+		switch _sparseR1cs := ccs.(type) {
+		case *backend_bls12381.SparseR1CS:
+			w := make(witness_bls12381.Witness, _sparseR1cs.NbPublicVariables+_sparseR1cs.NbSecretVariables)
+			for i := 0; i < len(w) && len(data) > 0; i++ {
+				w[i].SetUint64(uint64(data[i%len(data)] % 3))
+			}
+			publicWitness := w[:_sparseR1cs.NbPublicVariables]
+			errSolving := _sparseR1cs.IsSolved(w)
+			if len(w)+_sparseR1cs.NbInternalVariables == 0 {
+				continue // nothing to prove
+			}
+			publicData, err := plonk_bls12381.SetupRaw(_sparseR1cs, &mockcommitment_bls12381.Scheme{}, publicWitness)
+			if err != nil {
+				panic(err)
+			}
+			proof, err := plonk_bls12381.ProveRaw(_sparseR1cs, publicData, w, true)
+			if err != nil {
+				panic(err)
+			}
+			checkConsistency(errSolving, plonk_bls12381.VerifyRaw(proof, publicData, publicWitness))
+		case *backend_bn254.SparseR1CS:
+			w := make(witness_bn254.Witness, _sparseR1cs.NbPublicVariables+_sparseR1cs.NbSecretVariables)
+			for i := 0; i < len(w) && len(data) > 0; i++ {
+				w[i].SetUint64(uint64(data[i%len(data)] % 3))
+			}
+			publicWitness := w[:_sparseR1cs.NbPublicVariables]
+			errSolving := _sparseR1cs.IsSolved(w)
+			if len(w)+_sparseR1cs.NbInternalVariables == 0 {
+				continue // nothing to prove
+			}
+			publicData, err := plonk_bn254.SetupRaw(_sparseR1cs, &mockcommitment_bn254.Scheme{}, publicWitness)
+			if err != nil {
+				panic(err)
+			}
+			proof, err := plonk_bn254.ProveRaw(_sparseR1cs, publicData, w, true)
+			if err != nil {
+				panic(err)
+			}
+			checkConsistency(errSolving, plonk_bn254.VerifyRaw(proof, publicData, publicWitness))
+		default:
+			panic("unrecognized SparseR1CS curve type")
+		}
+	}
+	return 1
+}
+
+// checkConsistency panics if the solver and the verifier disagree on the witness
+func checkConsistency(errSolving, errVerifying error) {
+	if errSolving == nil && errVerifying != nil {
+		panic("solving succeeded, yet the proof doesn't verify: " + errVerifying.Error())
+	}
+	if errSolving != nil && errVerifying == nil {
+		panic("solving failed, yet the proof verifies: " + errSolving.Error())
+	}
+}
@@ -20,6 +20,9 @@
 package plonk
 
 import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/polynomial"
 	"github.com/consensys/gnark/frontend"
 
@@ -55,7 +58,10 @@ type PublicData interface{}
 // Proof content might vary according to the PLONK version which is chosen.
 //
 // For instance it can be the commitments of L,R,O,H,Z and the opening proofs.
-type Proof interface{}
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+}
 
 // Setup prepares the public data associated to a circuit + public inputs.
 func Setup(sparseR1cs frontend.CompiledConstraintSystem, polynomialCommitment polynomial.CommitmentScheme, publicWitness frontend.Circuit) (PublicData, error) {
@@ -66,32 +72,28 @@ func Setup(sparseR1cs frontend.CompiledConstraintSystem, polynomialCommitment po
 		if err := w.FromPublicAssignment(publicWitness); err != nil {
 			return nil, err
 		}
-		publicData := plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bls12381.SparseR1CS:
 		w := bls12381witness.Witness{}
 		if err := w.FromPublicAssignment(publicWitness); err != nil {
 			return nil, err
 		}
-		publicData := plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bls12377.SparseR1CS:
 		w := bls12377witness.Witness{}
 		if err := w.FromPublicAssignment(publicWitness); err != nil {
 			return nil, err
 		}
-		publicData := plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bw6761.SparseR1CS:
 		w := bw6761witness.Witness{}
 		if err := w.FromPublicAssignment(publicWitness); err != nil {
 			return nil, err
 		}
-		publicData := plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	default:
 		panic("unrecognized R1CS curve type")
@@ -109,8 +111,7 @@ func SetupDummyCommitment(sparseR1cs frontend.CompiledConstraintSystem, publicWi
 			return nil, err
 		}
 		polynomialCommitment := &mockcommitment_bn254.Scheme{}
-		publicData := plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bls12381.SparseR1CS:
 		w := bls12381witness.Witness{}
@@ -118,8 +119,7 @@ func SetupDummyCommitment(sparseR1cs frontend.CompiledConstraintSystem, publicWi
 			return nil, err
 		}
 		polynomialCommitment := &mockcommitment_bls12381.Scheme{}
-		publicData := plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bls12377.SparseR1CS:
 		w := bls12377witness.Witness{}
@@ -127,8 +127,7 @@ func SetupDummyCommitment(sparseR1cs frontend.CompiledConstraintSystem, publicWi
 			return nil, err
 		}
 		polynomialCommitment := &mockcommitment_bls12377.Scheme{}
-		publicData := plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	case *backend_bw6761.SparseR1CS:
 		w := bw6761witness.Witness{}
@@ -136,8 +135,7 @@ func SetupDummyCommitment(sparseR1cs frontend.CompiledConstraintSystem, publicWi
 			return nil, err
 		}
 		polynomialCommitment := &mockcommitment_bw6761.Scheme{}
-		publicData := plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w)
-		return publicData, nil
+		return plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w)
 
 	default:
 		panic("unrecognized R1CS curve type")
@@ -146,7 +144,14 @@ func SetupDummyCommitment(sparseR1cs frontend.CompiledConstraintSystem, publicWi
 }
 
 // Prove generates PLONK proof from a circuit, associated preprocessed public data, and the witness
-func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData, fullWitness frontend.Circuit) (Proof, error) {
+// if the force flag is set, executes all the prover computations, even if the witness is invalid
+// (in which case it will produce an invalid proof)
+func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData, fullWitness frontend.Circuit, force ...bool) (Proof, error) {
+
+	_force := false
+	if len(force) > 0 {
+		_force = force[0]
+	}
 
 	switch _sparseR1cs := sparseR1cs.(type) {
 	case *backend_bn254.SparseR1CS:
@@ -155,8 +160,7 @@ func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData,
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		proof := plonkbn254.ProveRaw(_sparseR1cs, _publicData, w)
-		return proof, nil
+		return plonkbn254.ProveRaw(_sparseR1cs, _publicData, w, _force)
 
 	case *backend_bls12381.SparseR1CS:
 		_publicData := publicData.(*plonkbls12381.PublicRaw)
@@ -164,8 +168,7 @@ func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData,
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		proof := plonkbls12381.ProveRaw(_sparseR1cs, _publicData, w)
-		return proof, nil
+		return plonkbls12381.ProveRaw(_sparseR1cs, _publicData, w, _force)
 
 	case *backend_bls12377.SparseR1CS:
 		_publicData := publicData.(*plonkbls12377.PublicRaw)
@@ -173,8 +176,7 @@ func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData,
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		proof := plonkbls12377.ProveRaw(_sparseR1cs, _publicData, w)
-		return proof, nil
+		return plonkbls12377.ProveRaw(_sparseR1cs, _publicData, w, _force)
 
 	case *backend_bw6761.SparseR1CS:
 		_publicData := publicData.(*plonkbw6761.PublicRaw)
@@ -182,8 +184,7 @@ func Prove(sparseR1cs frontend.CompiledConstraintSystem, publicData PublicData,
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		proof := plonkbw6761.ProveRaw(_sparseR1cs, _publicData, w)
-		return proof, nil
+		return plonkbw6761.ProveRaw(_sparseR1cs, _publicData, w, _force)
 
 	default:
 		panic("unrecognized R1CS curve type")
@@ -231,3 +232,184 @@ func Verify(proof Proof, publicData PublicData, publicWitness frontend.Circuit)
 		panic("unrecognized proof type")
 	}
 }
+
+// ReadAndProve generates PLONK proof from a circuit, a polynomial commitment scheme and a binary encoded full witness [public | secret]
+// the public data is set up from the public part of the witness.
+// if the force flag is set, executes all the prover computations, even if the witness is invalid
+// (in which case it will produce an invalid proof)
+func ReadAndProve(sparseR1cs frontend.CompiledConstraintSystem, polynomialCommitment polynomial.CommitmentScheme, fullWitness io.Reader, force ...bool) (Proof, error) {
+
+	_force := false
+	if len(force) > 0 {
+		_force = force[0]
+	}
+
+	_, nbSecret, nbPublic := sparseR1cs.GetNbVariables()
+	expectedSize := nbSecret + nbPublic
+
+	switch _sparseR1cs := sparseR1cs.(type) {
+	case *backend_bn254.SparseR1CS:
+		w := bn254witness.Witness{}
+		if _, err := w.LimitReadFrom(fullWitness, expectedSize); err != nil {
+			return nil, err
+		}
+		publicData, err := plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w[:nbPublic])
+		if err != nil {
+			return nil, err
+		}
+		return plonkbn254.ProveRaw(_sparseR1cs, publicData, w, _force)
+
+	case *backend_bls12381.SparseR1CS:
+		w := bls12381witness.Witness{}
+		if _, err := w.LimitReadFrom(fullWitness, expectedSize); err != nil {
+			return nil, err
+		}
+		publicData, err := plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w[:nbPublic])
+		if err != nil {
+			return nil, err
+		}
+		return plonkbls12381.ProveRaw(_sparseR1cs, publicData, w, _force)
+
+	case *backend_bls12377.SparseR1CS:
+		w := bls12377witness.Witness{}
+		if _, err := w.LimitReadFrom(fullWitness, expectedSize); err != nil {
+			return nil, err
+		}
+		publicData, err := plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w[:nbPublic])
+		if err != nil {
+			return nil, err
+		}
+		return plonkbls12377.ProveRaw(_sparseR1cs, publicData, w, _force)
+
+	case *backend_bw6761.SparseR1CS:
+		w := bw6761witness.Witness{}
+		if _, err := w.LimitReadFrom(fullWitness, expectedSize); err != nil {
+			return nil, err
+		}
+		publicData, err := plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w[:nbPublic])
+		if err != nil {
+			return nil, err
+		}
+		return plonkbw6761.ProveRaw(_sparseR1cs, publicData, w, _force)
+
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// ReadAndVerify verifies a PLONK proof from a circuit, a polynomial commitment scheme and a binary encoded public witness
+// the public data is set up from the public witness.
+func ReadAndVerify(proof Proof, sparseR1cs frontend.CompiledConstraintSystem, polynomialCommitment polynomial.CommitmentScheme, publicWitness io.Reader) error {
+
+	_, _, nbPublic := sparseR1cs.GetNbVariables()
+
+	switch _sparseR1cs := sparseR1cs.(type) {
+	case *backend_bn254.SparseR1CS:
+		w := bn254witness.Witness{}
+		if _, err := w.LimitReadFrom(publicWitness, nbPublic); err != nil {
+			return err
+		}
+		publicData, err := plonkbn254.SetupRaw(_sparseR1cs, polynomialCommitment, w)
+		if err != nil {
+			return err
+		}
+		return plonkbn254.VerifyRaw(proof.(*plonkbn254.ProofRaw), publicData, w)
+
+	case *backend_bls12381.SparseR1CS:
+		w := bls12381witness.Witness{}
+		if _, err := w.LimitReadFrom(publicWitness, nbPublic); err != nil {
+			return err
+		}
+		publicData, err := plonkbls12381.SetupRaw(_sparseR1cs, polynomialCommitment, w)
+		if err != nil {
+			return err
+		}
+		return plonkbls12381.VerifyRaw(proof.(*plonkbls12381.ProofRaw), publicData, w)
+
+	case *backend_bls12377.SparseR1CS:
+		w := bls12377witness.Witness{}
+		if _, err := w.LimitReadFrom(publicWitness, nbPublic); err != nil {
+			return err
+		}
+		publicData, err := plonkbls12377.SetupRaw(_sparseR1cs, polynomialCommitment, w)
+		if err != nil {
+			return err
+		}
+		return plonkbls12377.VerifyRaw(proof.(*plonkbls12377.ProofRaw), publicData, w)
+
+	case *backend_bw6761.SparseR1CS:
+		w := bw6761witness.Witness{}
+		if _, err := w.LimitReadFrom(publicWitness, nbPublic); err != nil {
+			return err
+		}
+		publicData, err := plonkbw6761.SetupRaw(_sparseR1cs, polynomialCommitment, w)
+		if err != nil {
+			return err
+		}
+		return plonkbw6761.VerifyRaw(proof.(*plonkbw6761.ProofRaw), publicData, w)
+
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// NewProof instantiate a concrete curved-typed Proof and return a Proof interface
+// This method exists for (de)serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &plonkbn254.ProofRaw{}
+	case ecc.BLS12_377:
+		proof = &plonkbls12377.ProofRaw{}
+	case ecc.BLS12_381:
+		proof = &plonkbls12381.ProofRaw{}
+	case ecc.BW6_761:
+		proof = &plonkbw6761.ProofRaw{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewCS instantiate a concrete curved-typed SparseR1CS and return a CompiledConstraintSystem interface
+// This method exists for (de)serialization purposes
+func NewCS(curveID ecc.ID) frontend.CompiledConstraintSystem {
+	var spr frontend.CompiledConstraintSystem
+	switch curveID {
+	case ecc.BN254:
+		spr = &backend_bn254.SparseR1CS{}
+	case ecc.BLS12_377:
+		spr = &backend_bls12377.SparseR1CS{}
+	case ecc.BLS12_381:
+		spr = &backend_bls12381.SparseR1CS{}
+	case ecc.BW6_761:
+		spr = &backend_bw6761.SparseR1CS{}
+	default:
+		panic("not implemented")
+	}
+	return spr
+}
+
+// NewCommitmentScheme instantiate a concrete curved-typed polynomial commitment scheme
+// This method exists for (de)serialization purposes
+//
+// gnark-crypto only provides a mock commitment scheme for now (see SetupDummyCommitment),
+// which doesn't offer any security: its opening proofs are never checked, a forged proof verifies.
+func NewCommitmentScheme(curveID ecc.ID) polynomial.CommitmentScheme {
+	var scheme polynomial.CommitmentScheme
+	switch curveID {
+	case ecc.BN254:
+		scheme = &mockcommitment_bn254.Scheme{}
+	case ecc.BLS12_377:
+		scheme = &mockcommitment_bls12377.Scheme{}
+	case ecc.BLS12_381:
+		scheme = &mockcommitment_bls12381.Scheme{}
+	case ecc.BW6_761:
+		scheme = &mockcommitment_bw6761.Scheme{}
+	default:
+		panic("not implemented")
+	}
+	return scheme
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
+)
+
+// WriteJSON encodes the assigned variables of witness as JSON, values are decimal strings
+// unassigned variables are omitted: the secret variables of a public witness needn't be assigned.
+func WriteJSON(w io.Writer, curveID ecc.ID, witness frontend.Circuit) error {
+	var root interface{} = map[string]interface{}{}
+	var handler parser.PathHandler = func(visibility compiled.Visibility, path []string, tInput reflect.Value) error {
+		val := frontend.GetAssignedValue(tInput.Interface().(frontend.Variable))
+		if val == nil {
+			return nil
+		}
+		return insertJSON(&root, path, toDecimal(curveID, val))
+	}
+	if err := parser.VisitPaths(witness, handler, reflect.TypeOf(frontend.Variable{})); err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(root)
+}
+
+// ReadJSON decodes a JSON witness and assigns its values to the variables of witness, which must be unassigned
+//
+// the variables missing from the JSON witness (or null) stay unassigned; names that aren't variables of witness
+// are an error.
+func ReadJSON(r io.Reader, curveID ecc.ID, witness frontend.Circuit) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return err
+	}
+	if _, ok := root.(map[string]interface{}); !ok {
+		return fmt.Errorf("a JSON witness is an object, got %T", root)
+	}
+
+	// values by name, e.g. Path[0].Sibling
+	values := make(map[string]interface{})
+	flattenJSON(root, "", values)
+	modulus := FrModulus(curveID)
+	var handler parser.PathHandler = func(visibility compiled.Visibility, path []string, tInput reflect.Value) error {
+		name := parser.PathName(path)
+		value, ok := values[name]
+		if !ok {
+			return nil
+		}
+		delete(values, name)
+		v, err := parseValue(value, modulus)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		variable := tInput.Addr().Interface().(*frontend.Variable)
+		if frontend.GetAssignedValue(*variable) != nil {
+			return fmt.Errorf("%s: variable already assigned", name)
+		}
+		variable.Assign(v)
+		return nil
+	}
+	if err := parser.VisitPaths(witness, handler, reflect.TypeOf(frontend.Variable{})); err != nil {
+		return err
+	}
+	if len(values) > 0 {
+		unknown := make([]string, 0, len(values))
+		for name := range values {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown variables %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// flattenJSON adds the leaves of the decoded JSON node to values, by name
+func flattenJSON(node interface{}, name string, values map[string]interface{}) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, child := range n {
+			childName := key
+			if name != "" {
+				childName = name + "." + key
+			}
+			flattenJSON(child, childName, values)
+		}
+	case []interface{}:
+		for i, child := range n {
+			flattenJSON(child, name+"["+strconv.Itoa(i)+"]", values)
+		}
+	case nil:
+		// unassigned
+	default:
+		values[name] = n
+	}
+}
+
+// insertJSON sets the value at path in the JSON node, creating the objects and arrays on the way
+func insertJSON(node *interface{}, path []string, value string) error {
+	if len(path) == 0 {
+		*node = value
+		return nil
+	}
+	if strings.HasPrefix(path[0], "[") {
+		i, err := strconv.Atoi(strings.Trim(path[0], "[]"))
+		if err != nil {
+			return err
+		}
+		if *node == nil {
+			*node = []interface{}{}
+		}
+		elems := (*node).([]interface{})
+		for len(elems) <= i {
+			elems = append(elems, nil)
+		}
+		if err := insertJSON(&elems[i], path[1:], value); err != nil {
+			return err
+		}
+		*node = elems
+		return nil
+	}
+	if *node == nil {
+		*node = map[string]interface{}{}
+	}
+	fields := (*node).(map[string]interface{})
+	child := fields[path[0]]
+	if err := insertJSON(&child, path[1:], value); err != nil {
+		return err
+	}
+	fields[path[0]] = child
+	return nil
+}
+
+// parseValue parses a value of the JSON witness, a string or an integer, see ParseValue
+func parseValue(value interface{}, modulus *big.Int) (*big.Int, error) {
+	switch v := value.(type) {
+	case string:
+		return parseInt(v, modulus)
+	case json.Number:
+		return parseInt(v.String(), modulus)
+	default:
+		return nil, fmt.Errorf("invalid value %v, expected a string or an integer", value)
+	}
+}
+
+// ParseValue parses a named witness value: a decimal or 0x prefixed hexadecimal integer in [0, modulus),
+// modulus being the one of the scalar field of the curve
+//
+// values out of range are an error, they aren't reduced: the witness of p+1 is not the witness of 1.
+func ParseValue(curveID ecc.ID, value string) (*big.Int, error) {
+	return parseInt(value, FrModulus(curveID))
+}
+
+// parseInt parses a decimal or 0x prefixed hexadecimal integer, in [0, modulus)
+func parseInt(s string, modulus *big.Int) (*big.Int, error) {
+	v, ok := new(big.Int), false
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		_, ok = v.SetString(s[2:], 16)
+	} else {
+		_, ok = v.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid value %q, expected a decimal or 0x prefixed hexadecimal integer", s)
+	}
+	if v.Sign() < 0 || v.Cmp(modulus) >= 0 {
+		return nil, fmt.Errorf("value %s out of range [0, modulus)", s)
+	}
+	return v, nil
+}
+
+// toDecimal returns the decimal value of an assigned variable, reduced modulo the scalar field of the curve
+func toDecimal(curveID ecc.ID, val interface{}) string {
+	switch curveID {
+	case ecc.BN254:
+		var e fr_bn254.Element
+		return e.SetInterface(val).String()
+	case ecc.BLS12_377:
+		var e fr_bls12377.Element
+		return e.SetInterface(val).String()
+	case ecc.BLS12_381:
+		var e fr_bls12381.Element
+		return e.SetInterface(val).String()
+	case ecc.BW6_761:
+		var e fr_bw6761.Element
+		return e.SetInterface(val).String()
+	default:
+		panic("not implemented")
+	}
+}
+
+// FrModulus returns the modulus of the scalar field of the curve, the elements of the witnesses are reduced modulo it
+func FrModulus(curveID ecc.ID) *big.Int {
+	switch curveID {
+	case ecc.BN254:
+		return fr_bn254.Modulus()
+	case ecc.BLS12_377:
+		return fr_bls12377.Modulus()
+	case ecc.BLS12_381:
+		return fr_bls12381.Modulus()
+	case ecc.BW6_761:
+		return fr_bw6761.Modulus()
+	default:
+		panic("not implemented")
+	}
+}
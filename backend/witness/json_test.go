@@ -0,0 +1,134 @@
+package witness_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+type jsonCircuit struct {
+	X      frontend.Variable `gnark:"x"`
+	Y      frontend.Variable `gnark:",public"`
+	Path   [2]struct{ Sibling frontend.Variable }
+	Proofs [2][2]frontend.Variable `gnark:",public"`
+}
+
+func (c *jsonCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return nil
+}
+
+func TestJSON(t *testing.T) {
+	var assignment jsonCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(35)
+	assignment.Path[0].Sibling.Assign(1)
+	assignment.Path[1].Sibling.Assign(2)
+	for i := range assignment.Proofs {
+		for j := range assignment.Proofs[i] {
+			assignment.Proofs[i][j].Assign(10*i + j)
+		}
+	}
+	var expected bytes.Buffer
+	if _, err := witness.WriteFullTo(&expected, ecc.BN254, &assignment); err != nil {
+		t.Fatal(err)
+	}
+
+	// round trip
+	var bJSON bytes.Buffer
+	if err := witness.WriteJSON(&bJSON, ecc.BN254, &assignment); err != nil {
+		t.Fatal(err)
+	}
+	var decoded jsonCircuit
+	if err := witness.ReadJSON(&bJSON, ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if _, err := witness.WriteFullTo(&b, ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), expected.Bytes()) {
+		t.Fatal("JSON round trip doesn't match the binary witness")
+	}
+
+	// hexadecimal strings and integers
+	const full = `{"x": "0x3", "Y": 35, "Path": [{"Sibling": "1"}, {"Sibling": "0x02"}], "Proofs": [["0", "1"], ["10", "11"]]}`
+	decoded = jsonCircuit{}
+	if err := witness.ReadJSON(strings.NewReader(full), ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if _, err := witness.WriteFullTo(&b, ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), expected.Bytes()) {
+		t.Fatal("JSON witness doesn't match the binary witness")
+	}
+
+	// public witness
+	const public = `{"Y": "35", "Proofs": [["0", "1"], ["10", "11"]]}`
+	decoded = jsonCircuit{}
+	if err := witness.ReadJSON(strings.NewReader(public), ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	var expectedPublic bytes.Buffer
+	if _, err := witness.WritePublicTo(&expectedPublic, ecc.BN254, &assignment); err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if _, err := witness.WritePublicTo(&b, ecc.BN254, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), expectedPublic.Bytes()) {
+		t.Fatal("JSON public witness doesn't match the binary public witness")
+	}
+	if _, err := witness.WriteFullTo(&b, ecc.BN254, &decoded); err == nil {
+		t.Fatal("the secret variables of a public witness should be unassigned")
+	}
+
+	// invalid witnesses
+	for _, invalid := range []string{
+		`{"x": "3", "Z": "1"}`,
+		`{"Path": [{"Sibling": "1"}, {"Sibling": "2"}, {"Sibling": "3"}]}`,
+		`{"x": "three"}`,
+		`{"x": "-1"}`,
+		`{"x": "21888242871839275222246405745257275088548364400416034343698204186575808495617"}`,
+		`{"x": true}`,
+		`["3"]`,
+	} {
+		decoded = jsonCircuit{}
+		if err := witness.ReadJSON(strings.NewReader(invalid), ecc.BN254, &decoded); err == nil {
+			t.Fatalf("%s should be invalid", invalid)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	for value, expected := range map[string]int64{"35": 35, "0x23": 35, "0X23": 35, "0": 0} {
+		v, err := witness.ParseValue(ecc.BN254, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Int64() != expected {
+			t.Fatalf("%s parsed as %s", value, v)
+		}
+	}
+
+	// values aren't reduced, and only decimal and hexadecimal integers are accepted
+	for _, invalid := range []string{
+		"-1",
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617",
+		"21888242871839275222246405745257275088548364400416034343698204186575808495618",
+		"0b11",
+		"0o43",
+		"3_5",
+		"",
+	} {
+		if _, err := witness.ParseValue(ecc.BN254, invalid); err == nil {
+			t.Fatalf("%q should be invalid", invalid)
+		}
+	}
+}
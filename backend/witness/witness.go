@@ -38,10 +38,28 @@
 // 	* `[uint32(3)|bytes(Y)|bytes(X)|bytes(Z)]`
 // 	* Hex representation with values `Y = 35`, `X = 3`, `Z = 2`
 // 	`00000003000000000000000000000000000000000000000000000000000000000000002300000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000002`
+//
+// JSON protocol
+//
+// A JSON witness (see ReadJSON and WriteJSON) maps the names of the variables, as in the circuit structure
+// (field name or gnark tag), to their values. Nested structs are JSON objects, slices and arrays are JSON arrays,
+// and values are decimal or 0x prefixed hexadecimal strings (or JSON integers). With this circuit
+//
+// 	type Circuit struct {
+// 	    X    frontend.Variable `gnark:"x"`
+// 	    Y    frontend.Variable `gnark:",public"`
+// 	    Path [2]struct{ Sibling frontend.Variable }
+// 	}
+//
+// a valid witness would be `{"x": "3", "Y": "0x23", "Path": [{"Sibling": "1"}, {"Sibling": "2"}]}`.
+//
+// The visibility and the order of the variables come from the circuit structure: ReadJSON followed by
+// WriteFullTo (or WritePublicTo) converts a JSON witness to the binary protocol.
 package witness
 
 import (
 	"io"
+	"reflect"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	witness_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/witness"
@@ -50,6 +68,8 @@ import (
 	witness_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/witness"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
 )
 
 // WriteFullTo encodes the witness to a slice of []fr.Element and write the []byte on provided writer
@@ -115,3 +135,95 @@ func WritePublicTo(w io.Writer, curveID ecc.ID, publicWitness frontend.Circuit)
 		panic("not implemented")
 	}
 }
+
+// ReadFullFrom decodes a full witness (see WriteFullTo) and assigns its values to the variables of witness,
+// which must be unassigned
+func ReadFullFrom(r io.Reader, curveID ecc.ID, witness frontend.Circuit) (int64, error) {
+	nbSecret, nbPublic := count(witness)
+	switch curveID {
+	case ecc.BN254:
+		_witness := &witness_bn254.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic+nbSecret)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToFullAssignment(witness)
+	case ecc.BLS12_377:
+		_witness := &witness_bls12377.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic+nbSecret)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToFullAssignment(witness)
+	case ecc.BLS12_381:
+		_witness := &witness_bls12381.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic+nbSecret)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToFullAssignment(witness)
+	case ecc.BW6_761:
+		_witness := &witness_bw6761.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic+nbSecret)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToFullAssignment(witness)
+	default:
+		panic("not implemented")
+	}
+}
+
+// ReadPublicFrom decodes a public witness (see WritePublicTo) and assigns its values to the public variables
+// of publicWitness, which must be unassigned; the secret variables are left unassigned
+func ReadPublicFrom(r io.Reader, curveID ecc.ID, publicWitness frontend.Circuit) (int64, error) {
+	_, nbPublic := count(publicWitness)
+	switch curveID {
+	case ecc.BN254:
+		_witness := &witness_bn254.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToPublicAssignment(publicWitness)
+	case ecc.BLS12_377:
+		_witness := &witness_bls12377.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToPublicAssignment(publicWitness)
+	case ecc.BLS12_381:
+		_witness := &witness_bls12381.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToPublicAssignment(publicWitness)
+	case ecc.BW6_761:
+		_witness := &witness_bw6761.Witness{}
+		n, err := _witness.LimitReadFrom(r, nbPublic)
+		if err != nil {
+			return n, err
+		}
+		return n, _witness.ToPublicAssignment(publicWitness)
+	default:
+		panic("not implemented")
+	}
+}
+
+// count returns the number of secret and public variables of the circuit
+func count(w frontend.Circuit) (nbSecret, nbPublic int) {
+	var collectHandler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
+		if visibility == compiled.Secret {
+			nbSecret++
+		} else if visibility == compiled.Public {
+			nbPublic++
+		}
+		return nil
+	}
+	if err := parser.Visit(w, "", compiled.Unset, collectHandler, reflect.TypeOf(frontend.Variable{})); err != nil {
+		panic("count handler doesn't return an error -- this panic should not happen")
+	}
+	return
+}
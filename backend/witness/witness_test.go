@@ -0,0 +1,80 @@
+package witness_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func TestReadFrom(t *testing.T) {
+	var assignment jsonCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(35)
+	assignment.Path[0].Sibling.Assign(1)
+	assignment.Path[1].Sibling.Assign(2)
+	for i := range assignment.Proofs {
+		for j := range assignment.Proofs[i] {
+			assignment.Proofs[i][j].Assign(10*i + j)
+		}
+	}
+
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761} {
+		// full witness
+		var full bytes.Buffer
+		if _, err := witness.WriteFullTo(&full, curveID, &assignment); err != nil {
+			t.Fatal(err)
+		}
+		var decoded jsonCircuit
+		n, err := witness.ReadFullFrom(bytes.NewReader(full.Bytes()), curveID, &decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(full.Len()) {
+			t.Fatalf("%s: read %d bytes, expected %d", curveID, n, full.Len())
+		}
+		var b bytes.Buffer
+		if _, err := witness.WriteFullTo(&b, curveID, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b.Bytes(), full.Bytes()) {
+			t.Fatalf("%s: full witness round trip mismatch", curveID)
+		}
+
+		// public witness
+		var public bytes.Buffer
+		if _, err := witness.WritePublicTo(&public, curveID, &assignment); err != nil {
+			t.Fatal(err)
+		}
+		decoded = jsonCircuit{}
+		if _, err := witness.ReadPublicFrom(bytes.NewReader(public.Bytes()), curveID, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		b.Reset()
+		if _, err := witness.WritePublicTo(&b, curveID, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b.Bytes(), public.Bytes()) {
+			t.Fatalf("%s: public witness round trip mismatch", curveID)
+		}
+		if _, err := witness.WriteFullTo(&b, curveID, &decoded); err == nil {
+			t.Fatalf("%s: the secret variables of a public witness should be unassigned", curveID)
+		}
+
+		// size mismatch
+		decoded = jsonCircuit{}
+		if _, err := witness.ReadFullFrom(bytes.NewReader(public.Bytes()), curveID, &decoded); err == nil {
+			t.Fatalf("%s: a public witness isn't a full witness", curveID)
+		}
+		decoded = jsonCircuit{}
+		if _, err := witness.ReadPublicFrom(bytes.NewReader(full.Bytes()), curveID, &decoded); err == nil {
+			t.Fatalf("%s: a full witness isn't a public witness", curveID)
+		}
+
+		// assigned variables
+		if _, err := witness.ReadFullFrom(bytes.NewReader(full.Bytes()), curveID, &assignment); err == nil {
+			t.Fatalf("%s: variables already assigned", curveID)
+		}
+	}
+}
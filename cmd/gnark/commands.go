@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/polynomial"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
+)
+
+// csInfo describes a constraint system, see runInfo
+type csInfo struct {
+	Curve               string `json:"curve"`
+	Backend             string `json:"backend"`
+	NbConstraints       int    `json:"nbConstraints"`
+	NbInternalVariables int    `json:"nbInternalVariables"`
+	NbSecretVariables   int    `json:"nbSecretVariables"`
+	NbPublicVariables   int    `json:"nbPublicVariables"`
+	NbCoefficients      int    `json:"nbCoefficients"`
+
+	// number of elements of the binary witnesses
+	FullWitnessSize   int `json:"fullWitnessSize"`
+	PublicWitnessSize int `json:"publicWitnessSize"`
+}
+
+func newCSInfo(cs frontend.CompiledConstraintSystem, backendID backend.ID) csInfo {
+	info := csInfo{
+		Curve:          cs.CurveID().String(),
+		Backend:        backendName(backendID),
+		NbConstraints:  cs.GetNbConstraints(),
+		NbCoefficients: cs.GetNbCoefficients(),
+	}
+	info.NbInternalVariables, info.NbSecretVariables, info.NbPublicVariables = cs.GetNbVariables()
+
+	// the R1CS public variables include the ONE_WIRE, which isn't in the witnesses
+	info.PublicWitnessSize = info.NbPublicVariables
+	if backendID == backend.GROTH16 {
+		info.PublicWitnessSize--
+	}
+	info.FullWitnessSize = info.PublicWitnessSize + info.NbSecretVariables
+	return info
+}
+
+func runInfo(curveID ecc.ID, backendID backend.ID, args []string) error {
+	fs := newFlagSet("info")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gnark info <constraint system file>\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("missing constraint system file")
+	}
+	cs, err := readCS(curveID, backendID, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printResult(newCSInfo(cs, backendID))
+}
+
+func runSetup(curveID ecc.ID, backendID backend.ID, args []string) error {
+	fs := newFlagSet("setup")
+	csFile := fs.String("cs", "", "R1CS file")
+	pkFile := fs.String("pk", "", "file the proving key is written to")
+	vkFile := fs.String("vk", "", "file the verifying key is written to")
+	fs.Parse(args)
+	if err := required(fs, "cs", "pk", "vk"); err != nil {
+		return err
+	}
+	if backendID != backend.GROTH16 {
+		return errors.New("only groth16 has a setup, plonk is set up from the public witness at each prove and verify")
+	}
+
+	r1cs, err := readCS(curveID, backendID, *csFile)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		return err
+	}
+	if err := writeObject(pk, *pkFile); err != nil {
+		return err
+	}
+	return writeObject(vk, *vkFile)
+}
+
+func runProve(curveID ecc.ID, backendID backend.ID, args []string) error {
+	fs := newFlagSet("prove")
+	csFile := fs.String("cs", "", "constraint system file (.r1cs or .spr)")
+	pkFile := fs.String("pk", "", "groth16 proving key file")
+	pcsFile := fs.String("pcs", "", "plonk polynomial commitment scheme file (a mock scheme if empty)")
+	witnessFile := fs.String("witness", "", "binary full witness file (see backend/witness)")
+	proofFile := fs.String("o", "", "file the binary proof is written to (stdout if empty)")
+	fs.Parse(args)
+	if err := required(fs, "cs", "witness"); err != nil {
+		return err
+	}
+
+	cs, err := readCS(curveID, backendID, *csFile)
+	if err != nil {
+		return err
+	}
+	witness, err := os.Open(*witnessFile)
+	if err != nil {
+		return err
+	}
+	defer witness.Close()
+
+	var proof io.WriterTo
+	if backendID == backend.PLONK {
+		scheme, err := readCommitmentScheme(curveID, *pcsFile)
+		if err != nil {
+			return err
+		}
+		proof, err = plonk.ReadAndProve(cs, scheme, bufio.NewReader(witness))
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := required(fs, "pk"); err != nil {
+			return err
+		}
+		pk := groth16.NewProvingKey(curveID)
+		if err := readObject(pk, *pkFile); err != nil {
+			return err
+		}
+		proof, err = groth16.ReadAndProve(cs, pk, bufio.NewReader(witness))
+		if err != nil {
+			return err
+		}
+	}
+	return writeObject(proof, *proofFile)
+}
+
+func runVerify(curveID ecc.ID, backendID backend.ID, args []string) error {
+	fs := newFlagSet("verify")
+	vkFile := fs.String("vk", "", "groth16 verifying key file")
+	csFile := fs.String("cs", "", "plonk sparse R1CS file")
+	pcsFile := fs.String("pcs", "", "plonk polynomial commitment scheme file (a mock scheme if empty)")
+	proofFile := fs.String("proof", "", "binary proof file")
+	witnessFile := fs.String("public", "", "binary public witness file (see backend/witness)")
+	fs.Parse(args)
+	if err := required(fs, "proof", "public"); err != nil {
+		return err
+	}
+
+	publicWitness, err := os.Open(*witnessFile)
+	if err != nil {
+		return err
+	}
+	defer publicWitness.Close()
+
+	if backendID == backend.PLONK {
+		if err := required(fs, "cs"); err != nil {
+			return err
+		}
+		spr, err := readCS(curveID, backendID, *csFile)
+		if err != nil {
+			return err
+		}
+		scheme, err := readCommitmentScheme(curveID, *pcsFile)
+		if err != nil {
+			return err
+		}
+		proof := plonk.NewProof(curveID)
+		if err := readObject(proof, *proofFile); err != nil {
+			return err
+		}
+		err = plonk.ReadAndVerify(proof, spr, scheme, bufio.NewReader(publicWitness))
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := required(fs, "vk"); err != nil {
+			return err
+		}
+		vk := groth16.NewVerifyingKey(curveID)
+		if err := readObject(vk, *vkFile); err != nil {
+			return err
+		}
+		proof := groth16.NewProof(curveID)
+		if err := readObject(proof, *proofFile); err != nil {
+			return err
+		}
+		if err := groth16.ReadAndVerify(proof, vk, bufio.NewReader(publicWitness)); err != nil {
+			return err
+		}
+	}
+	fmt.Println("valid proof")
+	return nil
+}
+
+// readCommitmentScheme reads the polynomial commitment scheme of a plonk circuit, a mock scheme if path is empty
+func readCommitmentScheme(curveID ecc.ID, path string) (polynomial.CommitmentScheme, error) {
+	scheme := plonk.NewCommitmentScheme(curveID)
+	if path == "" {
+		return scheme, nil
+	}
+	if err := readObject(scheme, path); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func runExportSolidity(curveID ecc.ID, backendID backend.ID, args []string) error {
+	fs := newFlagSet("export-solidity")
+	vkFile := fs.String("vk", "", "groth16 verifying key file")
+	outFile := fs.String("o", "", "file the solidity verifier is written to (stdout if empty)")
+	fs.Parse(args)
+	if err := required(fs, "vk"); err != nil {
+		return err
+	}
+	if backendID != backend.GROTH16 {
+		return errors.New("only groth16 verifiers can be exported")
+	}
+
+	vk := groth16.NewVerifyingKey(curveID)
+	if err := readObject(vk, *vkFile); err != nil {
+		return err
+	}
+	w := io.Writer(os.Stdout)
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return vk.ExportSolidity(w)
+}
+
+// witnessInfo describes a binary witness, see runWitness
+type witnessInfo struct {
+	Curve      string   `json:"curve"`
+	NbElements int      `json:"nbElements"`
+	Kind       string   `json:"kind,omitempty"` // full or public, if -cs is set
+	Values     []string `json:"values"`         // decimal
+	Names      []string `json:"names,omitempty"`
+}
+
+func runWitness(curveID ecc.ID, backendID backend.ID, args []string) error {
+	if len(args) == 0 || args[0] != "inspect" {
+		return errors.New("usage: gnark witness inspect [-cs file] [-schema file] <witness file>")
+	}
+	fs := newFlagSet("witness inspect")
+	csFile := fs.String("cs", "", "constraint system file, to check the witness size and name its elements")
+	schemaFile := fs.String("schema", "", "witness schema file (json, public and secret variable names), to name the elements")
+	fs.Parse(args[1:])
+	if fs.NArg() != 1 {
+		return errors.New("missing witness file")
+	}
+
+	// binary witness: [uint32(nbElements) | elements], see backend/witness
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var nbElements uint32
+	if err := binary.Read(r, binary.BigEndian, &nbElements); err != nil {
+		return fmt.Errorf("couldn't read the witness size: %w", err)
+	}
+	modulus := witness.FrModulus(curveID)
+	info := witnessInfo{Curve: curveID.String(), NbElements: int(nbElements)}
+	buf := make([]byte, len(modulus.Bytes()))
+	for i := 0; i < int(nbElements); i++ {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return fmt.Errorf("witness truncated at element %d of %d: %w", i, nbElements, err)
+		}
+		v := new(big.Int).SetBytes(buf)
+		if v.Cmp(modulus) >= 0 {
+			return fmt.Errorf("element %d (%s) isn't reduced modulo the %s scalar field", i, v, curveID)
+		}
+		info.Values = append(info.Values, v.String())
+	}
+	if n, _ := r.Read(buf[:1]); n != 0 {
+		return fmt.Errorf("trailing bytes after the %d elements", nbElements)
+	}
+
+	// public then secret variables
+	var public, secret []string
+	if *schemaFile != "" {
+		f, err := os.Open(*schemaFile)
+		if err != nil {
+			return err
+		}
+		schema, err := manifest.ReadWitnessSchema(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *schemaFile, err)
+		}
+		public, secret = schema.Public, schema.Secret
+	}
+	if *csFile != "" {
+		cs, err := readCS(curveID, backendID, *csFile)
+		if err != nil {
+			return err
+		}
+		sizes := newCSInfo(cs, backendID)
+		switch info.NbElements {
+		case sizes.FullWitnessSize:
+			info.Kind = "full"
+		case sizes.PublicWitnessSize:
+			info.Kind = "public"
+		default:
+			return fmt.Errorf("%d elements, a witness of the constraint system has %d (full) or %d (public) elements", info.NbElements, sizes.FullWitnessSize, sizes.PublicWitnessSize)
+		}
+		if public == nil {
+			for i := 0; i < sizes.PublicWitnessSize; i++ {
+				public = append(public, fmt.Sprintf("public[%d]", i))
+			}
+			for i := 0; i < sizes.NbSecretVariables; i++ {
+				secret = append(secret, fmt.Sprintf("secret[%d]", i))
+			}
+		}
+	}
+	if public != nil {
+		names := append(append([]string{}, public...), secret...)
+		if info.NbElements != len(public) && info.NbElements != len(names) {
+			return fmt.Errorf("%d elements, the schema has %d public and %d secret variables", info.NbElements, len(public), len(secret))
+		}
+		info.Names = names[:info.NbElements]
+	}
+	return printResult(info)
+}
+
+// printResult prints v as json on stdout
+func printResult(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	// the files of the cubic circuit: constraint system, witness schema and witnesses
+	var circuit cubic.Circuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+	assert.NoError(writeObject(r1cs, path("cubic.r1cs")))
+	schema, err := manifest.NewWitnessSchema(&circuit)
+	assert.NoError(err)
+	data, err := json.Marshal(schema)
+	assert.NoError(err)
+	assert.NoError(ioutil.WriteFile(path("cubic.schema"), data, 0600))
+
+	writeWitness := func(name string, x, y int) {
+		var w cubic.Circuit
+		w.X.Assign(x)
+		w.Y.Assign(y)
+		f, err := os.Create(path(name + ".wit"))
+		assert.NoError(err)
+		_, err = witness.WriteFullTo(f, ecc.BN254, &w)
+		assert.NoError(err)
+		assert.NoError(f.Close())
+		f, err = os.Create(path(name + ".pub"))
+		assert.NoError(err)
+		_, err = witness.WritePublicTo(f, ecc.BN254, &w)
+		assert.NoError(err)
+		assert.NoError(f.Close())
+	}
+	writeWitness("cubic", 3, 35)
+	writeWitness("other", 4, 73)
+
+	// setup, prove and verify
+	assert.NoError(runSetup(ecc.BN254, backend.GROTH16, []string{"-cs", path("cubic.r1cs"), "-pk", path("cubic.pk"), "-vk", path("cubic.vk")}))
+	assert.NoError(runProve(ecc.BN254, backend.GROTH16, []string{"-cs", path("cubic.r1cs"), "-pk", path("cubic.pk"), "-witness", path("cubic.wit"), "-o", path("cubic.proof")}))
+	assert.NoError(runVerify(ecc.BN254, backend.GROTH16, []string{"-vk", path("cubic.vk"), "-proof", path("cubic.proof"), "-public", path("cubic.pub")}))
+	assert.Error(runVerify(ecc.BN254, backend.GROTH16, []string{"-vk", path("cubic.vk"), "-proof", path("cubic.proof"), "-public", path("other.pub")}))
+
+	// inspect the witnesses, named by the schema or the constraint system
+	assert.NoError(runWitness(ecc.BN254, backend.GROTH16, []string{"inspect", "-cs", path("cubic.r1cs"), "-schema", path("cubic.schema"), path("cubic.wit")}))
+	assert.NoError(runWitness(ecc.BN254, backend.GROTH16, []string{"inspect", "-cs", path("cubic.r1cs"), path("cubic.pub")}))
+	assert.Error(runWitness(ecc.BN254, backend.GROTH16, []string{"inspect", "-cs", path("cubic.r1cs"), path("cubic.proof")}))
+	assert.Error(runWitness(ecc.BW6_761, backend.GROTH16, []string{"inspect", path("cubic.wit")}), "bn254 elements aren't bw6_761 witnesses")
+
+	// the constraint system is described with its backend
+	info := newCSInfo(r1cs, backend.GROTH16)
+	assert.Equal("groth16", info.Backend)
+	assert.Equal(2, info.FullWitnessSize)
+	assert.Equal(1, info.PublicWitnessSize)
+}
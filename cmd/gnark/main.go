@@ -0,0 +1,171 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnark inspects and operates on serialized gnark objects, offline
+//
+//	gnark [global flags] <command> [command flags]
+//
+// objects are the files written by their WriteTo method: constraint systems (.r1cs, .spr), keys (.pk, .vk),
+// proofs and binary witnesses (see backend/witness). They don't record their curve, set it with -curve.
+// Run gnark -h for the list of commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/build"
+)
+
+// -------------------------------------------------------------------------------------------------
+// global flags
+var (
+	fCurve   = flag.String("curve", "bn254", "curve of the objects: bn254, bls12_381, bls12_377 or bw6_761")
+	fBackend = flag.String("backend", "groth16", "proving backend: groth16 or plonk")
+)
+
+// command of gnark, run parses args (the arguments following the command name)
+type command struct {
+	usage string
+	run   func(curveID ecc.ID, backendID backend.ID, args []string) error
+}
+
+var commands = map[string]command{
+	"info":            {"describe a constraint system (.r1cs or .spr)", runInfo},
+	"setup":           {"run the groth16 setup of a constraint system", runSetup},
+	"prove":           {"prove a binary witness", runProve},
+	"verify":          {"verify a proof", runVerify},
+	"export-solidity": {"write the solidity verifier of a groth16 verifying key (bn254)", runExportSolidity},
+	"witness":         {"inspect a binary witness (witness inspect)", runWitness},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	curveID, err := build.ParseCurve(*fCurve)
+	if err == nil {
+		var backendID backend.ID
+		if backendID, err = build.ParseBackend(*fBackend); err == nil {
+			err = cmd.run(curveID, backendID, flag.Args()[1:])
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gnark %s: %s\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gnark [flags] <command> [command flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+// backendName returns the name of the backend, as parsed by build.ParseBackend
+func backendName(backendID backend.ID) string {
+	if backendID == backend.PLONK {
+		return "plonk"
+	}
+	return "groth16"
+}
+
+// newFlagSet returns the flag set of a command
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("gnark "+name, flag.ExitOnError)
+}
+
+// required returns an error if one of the flags isn't set
+func required(fs *flag.FlagSet, names ...string) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for _, name := range names {
+		if !set[name] {
+			return fmt.Errorf("missing -%s", name)
+		}
+	}
+	return nil
+}
+
+// readObject reads object from the file written by its WriteTo method
+func readObject(object io.ReaderFrom, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := object.ReadFrom(f); err != nil {
+		return fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeObject writes object to path, or to stdout if path is empty or "-"
+func writeObject(object io.WriterTo, path string) error {
+	if path == "" || path == "-" {
+		_, err := object.WriteTo(os.Stdout)
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := object.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readCS reads the constraint system of the backend in path
+func readCS(curveID ecc.ID, backendID backend.ID, path string) (frontend.CompiledConstraintSystem, error) {
+	var cs frontend.CompiledConstraintSystem
+	if backendID == backend.PLONK {
+		cs = plonk.NewCS(curveID)
+	} else {
+		cs = groth16.NewCS(curveID)
+	}
+	if err := readObject(cs, path); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
@@ -54,6 +54,10 @@ type ConstraintSystem struct {
 	constraints []compiled.R1C // list of R1C that yield an output (for example v3 == v1 * v2, return v3)
 	assertions  []compiled.R1C // list of R1C that yield no output (for example ensuring v1 == v2)
 
+	// Lookups (PLONK only)
+	tables  [][][3]big.Int // lookup tables declared with NewLookupTable
+	lookups []lookup       // lookup constraints, see AssertInTable and Lookup
+
 	// Coefficients in the constraints
 	coeffs    []big.Int      // list of unique coefficients.
 	coeffsIDs map[string]int // map to fast check existence of a coefficient (key = coeff.Text(16))
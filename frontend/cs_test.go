@@ -1,9 +1,11 @@
 package frontend
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/internal/backend/compiled"
 )
 
@@ -107,3 +109,74 @@ func TestFindUnsolvedVariable(t *testing.T) {
 		solvedVariables[i] = true
 	}
 }
+
+func TestFuseCubic(t *testing.T) {
+
+	cs := newConstraintSystem()
+	x := cs.newSecretVariable()
+	y := cs.newPublicVariable()
+
+	// x**5 = (x**2)**2 * x, the last two multiplications are fused
+	x2 := cs.Mul(x, x)
+	x4 := cs.Mul(x2, x2)
+	x5 := cs.Mul(x4, x)
+	cs.AssertIsEqual(x5, y)
+
+	ccs, err := cs.toSparseR1CS(ecc.UNKNOWN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spr := ccs.(*compiled.SparseR1CS)
+
+	if len(spr.Constraints) != 2 {
+		t.Fatalf("expected 2 constraints, got %d", len(spr.Constraints))
+	}
+	if spr.Constraints[0].C[0].CoeffID() != 0 {
+		t.Fatal("x**2 should not be a cubic gate")
+	}
+	if spr.Constraints[1].C[0].CoeffID() == 0 {
+		t.Fatal("x**4 * x should be a cubic gate")
+	}
+
+	// x**2 is referenced twice in x**2 * x**2, it can't be fused
+	if spr.Constraints[1].C[0].VariableID() != spr.Constraints[0].O.VariableID() {
+		t.Fatal("cubic gate should be on x**2")
+	}
+}
+
+func TestLookup(t *testing.T) {
+
+	cs := newConstraintSystem()
+	x := cs.newSecretVariable()
+	y := cs.newPublicVariable()
+
+	table := cs.NewLookupTable([]interface{}{0, 1, 1}, []interface{}{1, 1, 0})
+	z := cs.Lookup(table, x, 1)
+	cs.AssertInTable(table, y, 1, z)
+
+	// lookups are only supported by PLONK
+	if _, err := cs.toR1CS(ecc.UNKNOWN); !errors.Is(err, ErrLookupNotSupported) {
+		t.Fatal("expected ErrLookupNotSupported, got", err)
+	}
+
+	ccs, err := cs.toSparseR1CS(ecc.UNKNOWN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spr := ccs.(*compiled.SparseR1CS)
+
+	if len(spr.Tables) != 1 || len(spr.Tables[0]) != 2 {
+		t.Fatal("expected 1 lookup table of 2 entries")
+	}
+
+	// the constant 1 is set on a wire by a constraint before each lookup
+	if len(spr.Constraints) != 3 || spr.Constraints[1].Table != 1 || spr.Constraints[1].Solver != compiled.TableLookup {
+		t.Fatal("expected the lookup of z after the constraint setting the constant")
+	}
+	if len(spr.Assertions) != 1 || spr.Assertions[0].Table != 1 {
+		t.Fatal("expected 1 lookup assertion")
+	}
+	if spr.Assertions[0].O.VariableID() != spr.Constraints[1].O.VariableID() {
+		t.Fatal("the lookup assertion should be on z")
+	}
+}
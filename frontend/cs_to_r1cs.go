@@ -15,6 +15,10 @@ import (
 // toR1CS constructs a rank-1 constraint sytem
 func (cs *ConstraintSystem) toR1CS(curveID ecc.ID) (CompiledConstraintSystem, error) {
 
+	if len(cs.lookups) != 0 {
+		return nil, ErrLookupNotSupported
+	}
+
 	// wires = public wires  | secret wires | internal wires
 
 	// setting up the result
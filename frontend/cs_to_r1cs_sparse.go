@@ -57,13 +57,55 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 	varPcsToVarCs := make(map[idCS]idPCS)
 	solvedVariables := make([]bool, len(cs.internal.variables))
 
+	// number of times each internal variable is referenced, used to
+	// detect the intermediate variables that can be fused in a cubic gate
+	nbReferences := countInternalReferences(cs)
+
+	// convert the lookup tables
+	res.Tables = make([][][3]int, len(cs.tables))
+	for i := 0; i < len(cs.tables); i++ {
+		res.Tables[i] = make([][3]int, len(cs.tables[i]))
+		for j := 0; j < len(cs.tables[i]); j++ {
+			for k := 0; k < 3; k++ {
+				res.Tables[i][j][k] = coeffID(&res, &cs.tables[i][j][k])
+			}
+		}
+	}
+
+	// the lookups yielding an output are recorded between the constraints,
+	// so that their output is solved before the constraints using it
+	nextLookup := 0
+	recordLookups := func(position int) {
+		for ; nextLookup < len(cs.lookups); nextLookup++ {
+			if cs.lookups[nextLookup].output {
+				if cs.lookups[nextLookup].position > position {
+					return
+				}
+				lookupToSparseR1C(&res, cs, cs.lookups[nextLookup], varPcsToVarCs, solvedVariables)
+			}
+		}
+	}
+
 	// convert the constraints invidually
 	for i := 0; i < len(cs.constraints); i++ {
+		recordLookups(i)
+		// two constraints separated by a lookup are not fused
+		fuse := i+1 < len(cs.constraints) && (nextLookup == len(cs.lookups) || cs.lookups[nextLookup].position > i+1)
+		if fuse && fuseCubic(&res, cs, cs.constraints[i], cs.constraints[i+1], nbReferences, varPcsToVarCs, solvedVariables) {
+			i++
+			continue
+		}
 		r1cToSparseR1C(&res, cs, cs.constraints[i], varPcsToVarCs, solvedVariables)
 	}
+	recordLookups(len(cs.constraints))
 	for i := 0; i < len(cs.assertions); i++ {
 		splitR1C(&res, cs, cs.assertions[i], varPcsToVarCs)
 	}
+	for i := 0; i < len(cs.lookups); i++ {
+		if !cs.lookups[i].output {
+			lookupToSparseR1C(&res, cs, cs.lookups[i], varPcsToVarCs, solvedVariables)
+		}
+	}
 
 	// offset the ID in a term
 	offsetIDTerm := func(t *compiled.Term) error {
@@ -129,6 +171,12 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 		if err != nil {
 			return err
 		}
+		for j := 0; j < len(exp.C); j++ {
+			err = offsetIDTerm(&exp.C[j])
+			if err != nil {
+				return err
+			}
+		}
 		err = offsetIDTerm(&exp.M[0])
 		if err != nil {
 			return err
@@ -153,17 +201,22 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 	for i := 0; i < len(cs.logs); i++ {
 		entry := compiled.LogEntry{
 			Format:    cs.logs[i].format,
-			ToResolve: make([]int, len(cs.logs[i].toResolve)),
+			ToResolve: make([]int, 0, len(cs.logs[i].toResolve)),
 		}
 		for j := 0; j < len(cs.logs[i].toResolve); j++ {
 			_, _, cID, cVisibility := cs.logs[i].toResolve[j].Unpack()
 			switch cVisibility {
 			case compiled.Public:
-				entry.ToResolve[j] += cID - 1 //+ res.NbInternalVariables + res.NbSecretVariables // -1 because the ONE_WIRE's is not counted
+				if cID == 0 {
+					// the ONE_WIRE is not a variable in PLONK, its value goes in the format
+					entry.Format = resolveInFormat(entry.Format, len(entry.ToResolve), "1")
+					continue
+				}
+				entry.ToResolve = append(entry.ToResolve, cID-1) // -1 because the ONE_WIRE's is not counted
 			case compiled.Secret:
-				entry.ToResolve[j] += cID + res.NbPublicVariables
+				entry.ToResolve = append(entry.ToResolve, cID+res.NbPublicVariables)
 			case compiled.Internal:
-				entry.ToResolve[j] = varPcsToVarCs[cID] + res.NbSecretVariables + res.NbPublicVariables
+				entry.ToResolve = append(entry.ToResolve, varPcsToVarCs[cID]+res.NbSecretVariables+res.NbPublicVariables)
 			case compiled.Unset:
 				panic("encountered unset visibility on a variable in logs id offset routine")
 			}
@@ -300,6 +353,23 @@ func popConstantTerm(l compiled.LinearExpression, cs *ConstraintSystem, pcs *com
 	return lCopy, resConstantID
 }
 
+// resolveInFormat replaces the n-th %s of format by value
+func resolveInFormat(format string, n int, value string) string {
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if format[i+1] == 's' {
+			if n == 0 {
+				return format[:i] + value + format[i+2:]
+			}
+			n--
+		}
+		i++ // skip the verb, or the escaped %
+	}
+	return format
+}
+
 // change t's ID to csPcsMapping[t.ID] to get the corresponding variable in the pcs,
 // the coeff ID is changed as well so that it corresponds to a coeff in the pcs.
 func getCorrespondingTerm(pcs *compiled.SparseR1CS, t compiled.Term, csCoeffs []big.Int, csPcsMapping map[idCS]idPCS) compiled.Term {
@@ -1142,3 +1212,190 @@ func splitR1C(pcs *compiled.SparseR1CS, cs *ConstraintSystem, r1c compiled.R1C,
 		}
 	}
 }
+
+// countInternalReferences returns, for each internal variable of cs, the
+// number of times it appears in the constraints, the assertions, the lookups and the logs.
+func countInternalReferences(cs *ConstraintSystem) []int {
+	res := make([]int, len(cs.internal.variables))
+	count := func(l compiled.LinearExpression) {
+		for _, t := range l {
+			if t.VariableVisibility() == compiled.Internal {
+				res[t.VariableID()]++
+			}
+		}
+	}
+	for _, r1c := range cs.constraints {
+		count(r1c.L)
+		count(r1c.R)
+		count(r1c.O)
+	}
+	for _, r1c := range cs.assertions {
+		count(r1c.L)
+		count(r1c.R)
+		count(r1c.O)
+	}
+	for _, l := range cs.lookups {
+		count(l.l.linExp)
+		count(l.r.linExp)
+		count(l.o.linExp)
+	}
+	for _, l := range cs.logs {
+		count(l.toResolve)
+	}
+	return res
+}
+
+// isSingleVariable returns true if l is a single term which is not a constant
+func isSingleVariable(l compiled.LinearExpression) bool {
+	if len(l) != 1 {
+		return false
+	}
+	return !(l[0].VariableVisibility() == compiled.Public && l[0].VariableID() == 0)
+}
+
+// sameVariable returns true if t1 and t2 refer to the same variable (the coefficients may differ)
+func sameVariable(t1, t2 compiled.Term) bool {
+	return t1.VariableVisibility() == t2.VariableVisibility() && t1.VariableID() == t2.VariableID()
+}
+
+// isSolved returns true if the variable in t is an input or an internal variable already solved
+func isSolved(t compiled.Term, solvedVariables []bool) bool {
+	return t.VariableVisibility() != compiled.Internal || solvedVariables[t.VariableID()]
+}
+
+// fuseCubic tries to record the two consecutive constraints
+//
+//	(c0*a)*(c1*a) = 1*t
+//	(c2*t)*(c3*b) = c4*o (or (c3*b)*(c2*t) = c4*o)
+//
+// as the single cubic gate c0c1c2c3*a*a*b - c4*o = 0, where t is an intermediate
+// variable referenced nowhere else, o is the variable to solve and a, b are solved.
+// It returns false, without side effects, if the constraints don't match.
+func fuseCubic(pcs *compiled.SparseR1CS, cs *ConstraintSystem, first, second compiled.R1C, nbReferences []int, csPcsMapping map[idCS]idPCS, solvedVariables []bool) bool {
+
+	if first.Solver != compiled.SingleOutput || second.Solver != compiled.SingleOutput {
+		return false
+	}
+	if !isSingleVariable(first.L) || !isSingleVariable(first.R) || !isSingleVariable(first.O) {
+		return false
+	}
+	if !isSingleVariable(second.L) || !isSingleVariable(second.R) || !isSingleVariable(second.O) {
+		return false
+	}
+
+	// first: a*a = t, with a solved and t an intermediate variable with coefficient 1
+	a, t := first.L[0], first.O[0]
+	if !sameVariable(a, first.R[0]) || !isSolved(a, solvedVariables) {
+		return false
+	}
+	if t.VariableVisibility() != compiled.Internal || solvedVariables[t.VariableID()] || nbReferences[t.VariableID()] != 2 {
+		return false
+	}
+	if cs.coeffs[t.CoeffID()].Cmp(bOne) != 0 {
+		return false
+	}
+
+	// second: t*b = o, with b solved and o unsolved
+	tt, b := second.L[0], second.R[0]
+	if !sameVariable(tt, t) {
+		tt, b = b, tt
+	}
+	if !sameVariable(tt, t) || sameVariable(b, t) || !isSolved(b, solvedVariables) {
+		return false
+	}
+	o := second.O[0]
+	if o.VariableVisibility() != compiled.Internal || solvedVariables[o.VariableID()] {
+		return false
+	}
+
+	// the coefficient of the cubic term is c0c1c2c3
+	var qc big.Int
+	qc.Mul(&cs.coeffs[first.L[0].CoeffID()], &cs.coeffs[first.R[0].CoeffID()]).
+		Mul(&qc, &cs.coeffs[tt.CoeffID()]).
+		Mul(&qc, &cs.coeffs[b.CoeffID()])
+
+	at := getCorrespondingTerm(pcs, a, cs.coeffs, csPcsMapping)
+	bt := getCorrespondingTerm(pcs, b, cs.coeffs, csPcsMapping)
+
+	// a and b are the L and R wires of the gate, the coefficients are carried by C
+	l, r := at, bt
+	l.SetCoeffID(0)
+	r.SetCoeffID(0)
+	c0, c1, c2 := at, at, bt
+	c0.SetCoeffID(coeffID(pcs, &qc))
+	c1.SetCoeffID(coeffID(pcs, bOne))
+	c2.SetCoeffID(coeffID(pcs, bOne))
+
+	res := newInternalVariable(pcs)
+	csPcsMapping[o.VariableID()] = res.VariableID()
+	res.SetCoeffID(coeffID(pcs, &cs.coeffs[o.CoeffID()]))
+
+	recordConstraint(pcs, compiled.SparseR1C{
+		L: l,
+		R: r,
+		M: [2]compiled.Term{l, r},
+		C: [3]compiled.Term{c0, c1, c2},
+		O: negate(pcs, res),
+	})
+
+	solvedVariables[t.VariableID()] = true
+	solvedVariables[o.VariableID()] = true
+
+	return true
+}
+
+// lookupWire returns a term 1*w, where w is a PLONK wire equal to le. If le is not
+// a single variable with coefficient 1, w is a new internal variable solved by an
+// extra constraint, since the lookup argument only applies to the wires.
+func lookupWire(pcs *compiled.SparseR1CS, cs *ConstraintSystem, le compiled.LinearExpression, csPcsMapping map[idCS]idPCS) compiled.Term {
+
+	l, k := popConstantTerm(le, cs, pcs)
+	if k == 0 && len(l) == 1 && cs.coeffs[l[0].CoeffID()].Cmp(bOne) == 0 {
+		return getCorrespondingTerm(pcs, l[0], cs.coeffs, csPcsMapping)
+	}
+
+	w := newInternalVariable(pcs)
+	if len(l) == 0 { // w = k
+		recordConstraint(pcs, compiled.SparseR1C{O: negate(pcs, w), K: k})
+		return w
+	}
+	t := split(pcs, 0, cs.coeffs, l, csPcsMapping)
+	recordConstraint(pcs, compiled.SparseR1C{L: t, O: negate(pcs, w), K: k})
+	return w
+}
+
+// lookupToSparseR1C records the lookup constraint (l, r, o) in table. The
+// coefficients of the constraint are zero, only the wires are used.
+// If the lookup yields an output, it is solved by reading it in the table.
+func lookupToSparseR1C(pcs *compiled.SparseR1CS, cs *ConstraintSystem, lk lookup, csPcsMapping map[idCS]idPCS, solvedVariables []bool) {
+
+	l := lookupWire(pcs, cs, lk.l.linExp, csPcsMapping)
+	r := lookupWire(pcs, cs, lk.r.linExp, csPcsMapping)
+
+	var o compiled.Term
+	if lk.output {
+		o = newInternalVariable(pcs)
+		csPcsMapping[lk.o.id] = o.VariableID()
+		solvedVariables[lk.o.id] = true
+	} else {
+		o = lookupWire(pcs, cs, lk.o.linExp, csPcsMapping)
+	}
+
+	l.SetCoeffID(0)
+	r.SetCoeffID(0)
+	o.SetCoeffID(0)
+	c := compiled.SparseR1C{
+		L:     l,
+		R:     r,
+		M:     [2]compiled.Term{l, r},
+		O:     o,
+		Table: lk.table + 1,
+	}
+
+	if lk.output {
+		c.Solver = compiled.TableLookup
+		recordConstraint(pcs, c)
+	} else {
+		recordAssertion(pcs, c)
+	}
+}
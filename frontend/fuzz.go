@@ -21,12 +21,36 @@ func Fuzz(data []byte) int {
 	curves := []ecc.ID{ecc.BN254, ecc.BLS12_381}
 	for _, curveID := range curves {
 		_, _ = CsFuzzed(data, curveID)
+		_, _ = SparseCsFuzzed(data, curveID)
 	}
 
 	return 1
 }
 
 func CsFuzzed(data []byte, curveID ecc.ID) (ccs CompiledConstraintSystem, nbAssertions int) {
+	cs := fuzzedConstraintSystem(data, false)
+	ccs, err := cs.toR1CS(curveID)
+	if err != nil {
+		panic(fmt.Sprintf("compiling (curve %s) failed: %v", curveID.String(), err))
+	}
+	return ccs, len(cs.assertions)
+}
+
+// SparseCsFuzzed compiles the circuits of CsFuzzed to a SparseR1CS (PLONK), with the PLONK only
+// constraints added: lookups, and multiplications fused into cubic gates
+func SparseCsFuzzed(data []byte, curveID ecc.ID) (ccs CompiledConstraintSystem, nbAssertions int) {
+	cs := fuzzedConstraintSystem(data, true)
+	ccs, err := cs.toSparseR1CS(curveID)
+	if err != nil {
+		panic(fmt.Sprintf("compiling (curve %s) to a sparse R1CS failed: %v", curveID.String(), err))
+	}
+	return ccs, len(cs.assertions)
+}
+
+// fuzzedConstraintSystem builds a circuit from data, each byte adding variables, operations,
+// assertions or logs depending on its bits
+// if plonk is set, the circuit also uses the constraints only the SparseR1CS supports, see fuzzPlonk
+func fuzzedConstraintSystem(data []byte, plonk bool) *ConstraintSystem {
 	cs := newConstraintSystem()
 	reader := bytes.NewReader(data)
 
@@ -34,7 +58,7 @@ func CsFuzzed(data []byte, curveID ecc.ID) (ccs CompiledConstraintSystem, nbAsse
 		b, err := reader.ReadByte()
 		if err != nil {
 			if err == io.EOF {
-				goto compile
+				return &cs
 			}
 			panic(fmt.Sprintf("reading byte from reader errored: %v", err))
 		}
@@ -118,13 +142,46 @@ func CsFuzzed(data []byte, curveID ecc.ID) (ccs CompiledConstraintSystem, nbAsse
 			}
 		}
 
+		if plonk {
+			cs.fuzzPlonk(b)
+		}
 	}
-compile:
-	ccs, err := cs.toR1CS(curveID)
-	if err != nil {
-		panic(fmt.Sprintf("compiling (curve %s) failed: %v", curveID.String(), err))
+}
+
+// fuzzPlonk adds the constraints of the SparseR1CS compilation paths to cs, depending on the bits of b:
+// chained multiplications x*x*y (fused into a cubic gate), and lookups in a table of 3 bits values
+// (the fuzzed witnesses values are 0, 1 or 2: some lookups succeed, some fail)
+func (cs *ConstraintSystem) fuzzPlonk(b byte) {
+	if b&0b00000101 == 0b00000101 {
+		// cubic gate: (x*x)*y, and (x*x)*y*x whose second multiplication can't be fused
+		v := cs.shuffleVariables(int64(b), false)
+		if len(v) >= 2 {
+			x, y := v[0].(Variable), v[1].(Variable)
+			o := cs.Mul(cs.Mul(x, x), y)
+			if len(v) >= 3 {
+				cs.Mul(o, x)
+			}
+		}
+	}
+	if b&0b00001010 == 0b00001010 {
+		// lookups: the table holds (x, y, x*y+b mod 3) for x, y in {0, 1, 2}
+		var entries [][]interface{}
+		for x := 0; x < 3; x++ {
+			for y := 0; y < 3; y++ {
+				entries = append(entries, []interface{}{x, y, (x*y + int(b)) % 3})
+			}
+		}
+		table := cs.NewLookupTable(entries...)
+		v := cs.shuffleVariables(int64(b), false)
+		if len(v) >= 2 {
+			// a lookup between two multiplications, which aren't fused then
+			t := cs.Mul(v[0], v[0])
+			o := cs.Lookup(table, v[0], v[1])
+			cs.Mul(t, o)
+			cs.AssertInTable(table, v[1], o)
+			cs.AssertInTable(table, v[0], 1, o)
+		}
 	}
-	return ccs, len(cs.assertions)
 }
 
 func (cs *ConstraintSystem) shuffleVariables(seed int64, withConstant bool) []interface{} {
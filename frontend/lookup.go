@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrLookupNotSupported is returned when compiling a circuit using lookup tables to a R1CS
+var ErrLookupNotSupported = errors.New("lookup tables are only supported by the PLONK backend")
+
+// LookupTable is a table of constant entries declared with NewLookupTable.
+// Lookup constraints are only supported by the PLONK backend (plookup).
+type LookupTable struct {
+	id int // index of the table in cs.tables
+}
+
+// lookup is a constraint ensuring that (l, r, o) is an entry of cs.tables[table]
+type lookup struct {
+	table   int
+	l, r, o Variable
+
+	// if output is set, o is an internal variable solved by reading it from the table,
+	// and the lookup is solved after the first position constraints of cs.constraints
+	output   bool
+	position int
+}
+
+// NewLookupTable declares a lookup table, each entry is a list of 1 to 3 constants
+// (convertible to big.Int, see FromInterface).
+//
+// Entries with less than 3 values are completed by repeating their last value,
+// so that a table of single values ([]interface{}{v}) is stored as (v, v, v).
+func (cs *ConstraintSystem) NewLookupTable(entries ...[]interface{}) LookupTable {
+	if len(entries) == 0 {
+		panic("a lookup table must have at least one entry")
+	}
+	table := make([][3]big.Int, len(entries))
+	for i, entry := range entries {
+		if len(entry) == 0 || len(entry) > 3 {
+			panic("an entry of a lookup table must have 1 to 3 values")
+		}
+		for j := 0; j < 3; j++ {
+			v := entry[len(entry)-1]
+			if j < len(entry) {
+				v = entry[j]
+			}
+			table[i][j] = FromInterface(v)
+		}
+	}
+	cs.tables = append(cs.tables, table)
+	return LookupTable{id: len(cs.tables) - 1}
+}
+
+// AssertInTable adds a lookup constraint ensuring that (values...) is an entry of table.
+//
+// values is a list of 1 to 3 Variables or constants, completed by repeating the last value
+// like the entries of the table (see NewLookupTable).
+func (cs *ConstraintSystem) AssertInTable(table LookupTable, values ...interface{}) {
+	if len(values) == 0 || len(values) > 3 {
+		panic("a lookup must have 1 to 3 values")
+	}
+	var v [3]Variable
+	for j := 0; j < 3; j++ {
+		if j < len(values) {
+			v[j] = cs.Constant(values[j])
+		} else {
+			v[j] = v[len(values)-1]
+		}
+	}
+	cs.lookups = append(cs.lookups, lookup{table: table.id, l: v[0], r: v[1], o: v[2]})
+}
+
+// Lookup returns c such that (a, b, c) is an entry of table. If several entries
+// start with (a, b), the first one is used; if there are none, the solver fails.
+//
+// a and b are Variables or constants.
+func (cs *ConstraintSystem) Lookup(table LookupTable, a, b interface{}) Variable {
+	res := cs.newInternalVariable()
+	cs.lookups = append(cs.lookups, lookup{
+		table:    table.id,
+		l:        cs.Constant(a),
+		r:        cs.Constant(b),
+		o:        res,
+		output:   true,
+		position: len(cs.constraints),
+	})
+	return res
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package build packages Go circuits for gnarkd.
+//
+// It compiles the circuits of a Registry for the chosen curves and backends, runs their setup and writes their
+// files in the gnarkd circuit directory structure (<dir>/<curve>/<name>/), with a manifest.Manifest describing them.
+// Main turns a Registry into a command:
+//
+//	func main() {
+//		build.Main(build.Registry{"cubic": &cubic.Circuit{}})
+//	}
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
+)
+
+// Registry maps circuit names to circuits (pointers to circuit structs, their variables unset)
+type Registry map[string]frontend.Circuit
+
+// Target is a curve and a backend to build the circuits for
+type Target struct {
+	Curve   ecc.ID
+	Backend backend.ID
+}
+
+// Dir returns the directory of the circuit name for the target, relative to the circuit directory of gnarkd
+// PLONK circuits are suffixed with _plonk, as a gnarkd circuit has a single backend
+func (t Target) Dir(name string) string {
+	if t.Backend == backend.PLONK {
+		name += "_plonk"
+	}
+	return filepath.Join(t.Curve.String(), name)
+}
+
+// Option configures BuildCircuit
+type Option func(*options)
+
+type options struct {
+	insecurePlonk bool
+}
+
+// WithInsecurePlonk builds the PLONK targets, served by gnarkd with -insecure_plonk
+//
+// PLONK proofs are NOT sound, a forged proof verifies. Without this option, PLONK targets fail with ErrPlonkUnsupported.
+func WithInsecurePlonk() Option {
+	return func(o *options) {
+		o.insecurePlonk = true
+	}
+}
+
+// Build builds each circuit of the registry for each target in dir, see BuildCircuit
+func (r Registry) Build(dir string, targets []Target, opts ...Option) ([]*manifest.Manifest, error) {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var manifests []*manifest.Manifest
+	for _, name := range names {
+		for _, target := range targets {
+			m, err := BuildCircuit(dir, name, r[name], target, opts...)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, m)
+		}
+	}
+	return manifests, nil
+}
+
+// ErrPlonkUnsupported is returned when building a PLONK circuit without WithInsecurePlonk: gnark-crypto only
+// provides a mock polynomial commitment scheme for now, whose opening proofs are never checked
+var ErrPlonkUnsupported = errors.New("PLONK circuits can't be built: no secure polynomial commitment scheme is available")
+
+// BuildCircuit compiles the circuit for the target, runs its setup and writes its files in dir/target.Dir(name):
+// .r1cs, .pk and .vk for Groth16, .spr and .pcs for PLONK, the witness schema (.schema) and the manifest.Manifest
+//
+// existing files are overwritten. PLONK targets fail with ErrPlonkUnsupported, unless WithInsecurePlonk is set.
+func BuildCircuit(dir, name string, circuit frontend.Circuit, target Target, opts ...Option) (*manifest.Manifest, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var backendName string
+	switch target.Backend {
+	case backend.GROTH16:
+		backendName = "groth16"
+	case backend.PLONK:
+		if !o.insecurePlonk {
+			return nil, ErrPlonkUnsupported
+		}
+		backendName = "plonk"
+	default:
+		return nil, fmt.Errorf("unknown backend %d", target.Backend)
+	}
+	circuitDir := filepath.Join(dir, target.Dir(name))
+	base := filepath.Base(circuitDir)
+
+	ccs, err := frontend.Compile(target.Curve, target.Backend, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	schema, err := manifest.NewWitnessSchema(circuit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	m := &manifest.Manifest{
+		CircuitID:     filepath.ToSlash(target.Dir(name)),
+		Curve:         target.Curve.String(),
+		Backend:       backendName,
+		NbConstraints: ccs.GetNbConstraints(),
+		Witness:       schema,
+		Files:         make(map[string]manifest.File),
+	}
+	m.NbInternalVariables, m.NbSecretVariables, m.NbPublicVariables = ccs.GetNbVariables()
+
+	// the objects to write, by file extension
+	var objects map[string]io.WriterTo
+	if target.Backend == backend.PLONK {
+		objects = map[string]io.WriterTo{".spr": ccs, ".pcs": plonk.NewCommitmentScheme(target.Curve)}
+	} else {
+		pk, vk, err := groth16.Setup(ccs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		objects = map[string]io.WriterTo{".r1cs": ccs, ".pk": pk, ".vk": vk}
+	}
+
+	if err := os.MkdirAll(circuitDir, 0700); err != nil {
+		return nil, err
+	}
+	for ext, object := range objects {
+		file, err := writeFile(filepath.Join(circuitDir, base+ext), object)
+		if err != nil {
+			return nil, err
+		}
+		m.Files[base+ext] = file
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(circuitDir, base+".schema"), data, 0600); err != nil {
+		return nil, err
+	}
+	m.Files[base+".schema"] = newFile(data)
+
+	data, err = json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(circuitDir, base+manifest.Ext), data, 0600); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// writeFile writes object to path and returns its size and hash
+func writeFile(path string, object io.WriterTo) (manifest.File, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return manifest.File{}, err
+	}
+	h := sha256.New()
+	n, err := object.WriteTo(io.MultiWriter(f, h))
+	if err != nil {
+		f.Close()
+		return manifest.File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return manifest.File{}, err
+	}
+	return manifest.File{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+func newFile(data []byte) manifest.File {
+	h := sha256.Sum256(data)
+	return manifest.File{Size: int64(len(data)), SHA256: hex.EncodeToString(h[:])}
+}
+
+// ReadManifest reads the manifest of a built circuit and checks the sizes and hashes of its files
+// circuitDir is the directory of the circuit, e.g. circuits/bn254/cubic
+func ReadManifest(circuitDir string) (*manifest.Manifest, error) {
+	m, err := manifest.Read(circuitDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Check(circuitDir); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
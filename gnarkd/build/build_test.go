@@ -0,0 +1,71 @@
+package build
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/gnarkd/circuits/bn254/cubic"
+	"github.com/consensys/gnark/gnarkd/server"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBuild(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	targets, err := ParseTargets("bn254,bls12_381", "groth16")
+	assert.NoError(err)
+	assert.Len(targets, 2)
+	manifests, err := Registry{"cubic": &cubic.Circuit{}}.Build(dir, targets)
+	assert.NoError(err)
+	assert.Len(manifests, 2)
+
+	// the manifest describes the circuit and its files
+	manifest, err := ReadManifest(filepath.Join(dir, "bn254", "cubic"))
+	assert.NoError(err)
+	assert.Equal("bn254/cubic", manifest.CircuitID)
+	assert.Equal("groth16", manifest.Backend)
+	assert.Equal(3, manifest.NbConstraints)
+	assert.Equal([]string{"Y"}, manifest.Witness.Public)
+	assert.Equal([]string{"x"}, manifest.Witness.Secret)
+	assert.Len(manifest.Files, 4)
+	manifest, err = ReadManifest(filepath.Join(dir, "bls12_381", "cubic"))
+	assert.NoError(err)
+	assert.Contains(manifest.Files, "cubic.r1cs")
+
+	// PLONK circuits use a mock polynomial commitment scheme
+	_, err = BuildCircuit(dir, "cubic", &cubic.Circuit{}, Target{Curve: ecc.BN254, Backend: backend.PLONK})
+	assert.Equal(ErrPlonkUnsupported, err)
+	manifest, err = BuildCircuit(dir, "cubic", &cubic.Circuit{}, Target{Curve: ecc.BN254, Backend: backend.PLONK}, WithInsecurePlonk())
+	assert.NoError(err)
+	assert.Equal("bn254/cubic_plonk", manifest.CircuitID)
+	assert.Equal("plonk", manifest.Backend)
+	_, err = ReadManifest(filepath.Join(dir, "bn254", "cubic_plonk"))
+	assert.NoError(err)
+	assert.Contains(manifest.Files, "cubic_plonk.spr")
+	assert.Contains(manifest.Files, "cubic_plonk.pcs")
+
+	// modified files don't match the manifest
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "bn254", "cubic", "cubic.vk"), []byte("vk"), 0600))
+	_, err = ReadManifest(filepath.Join(dir, "bn254", "cubic"))
+	assert.Error(err)
+
+	// gnarkd serves the built circuits
+	_, err = BuildCircuit(dir, "cubic", &cubic.Circuit{}, Target{Curve: ecc.BN254, Backend: backend.GROTH16})
+	assert.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := server.NewServer(ctx, zap.NewNop().Sugar(), dir, server.WithInsecurePlonk())
+	assert.NoError(err)
+	info, err := s.Circuits().ListCircuits(ctx, nil)
+	assert.NoError(err)
+	assert.Len(info.Circuits, 3)
+
+	_, err = ParseTargets("bn254", "marlin")
+	assert.Error(err)
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+// Main is the main function of a build command for the circuits of the registry
+//
+//	build [-o dir] [-curves bn254,bls12_381] [-backends groth16] [-insecure_plonk] [circuit names]
+//
+// it builds the named circuits (all the circuits of the registry if none) and prints the IDs of the built circuits.
+func Main(registry Registry) {
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fOut := flags.String("o", ".", "gnarkd circuit directory the circuits are written to")
+	fCurves := flags.String("curves", "bn254", "comma separated curves: bn254, bls12_381, bls12_377, bw6_761")
+	fBackends := flags.String("backends", "groth16", "comma separated backends: groth16, plonk (requires -insecure_plonk)")
+	fInsecurePlonk := flags.Bool("insecure_plonk", false, "build the PLONK targets; INSECURE: PLONK proofs use a mock polynomial commitment scheme, forged proofs verify")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [circuits]\n\ncircuits:\n", os.Args[0])
+		for name := range registry {
+			fmt.Fprintf(os.Stderr, "  %s\n", name)
+		}
+		fmt.Fprintf(os.Stderr, "\nflags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	var opts []Option
+	if *fInsecurePlonk {
+		opts = append(opts, WithInsecurePlonk())
+	}
+	if err := run(registry, *fOut, *fCurves, *fBackends, flags.Args(), opts...); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
+		os.Exit(1)
+	}
+}
+
+func run(registry Registry, dir, curves, backends string, names []string, opts ...Option) error {
+	targets, err := ParseTargets(curves, backends)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		selected := make(Registry, len(names))
+		for _, name := range names {
+			circuit, ok := registry[name]
+			if !ok {
+				return fmt.Errorf("unknown circuit %q", name)
+			}
+			selected[name] = circuit
+		}
+		registry = selected
+	}
+	manifests, err := registry.Build(dir, targets, opts...)
+	if err != nil {
+		return err
+	}
+	for _, manifest := range manifests {
+		fmt.Printf("%s: %d constraints\n", manifest.CircuitID, manifest.NbConstraints)
+	}
+	return nil
+}
+
+// ParseTargets returns the targets of each comma separated curve and backend, e.g. "bn254,bls12_381" and "groth16"
+func ParseTargets(curves, backends string) ([]Target, error) {
+	var targets []Target
+	for _, c := range strings.Split(curves, ",") {
+		curve, err := ParseCurve(c)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range strings.Split(backends, ",") {
+			backendID, err := ParseBackend(b)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, Target{Curve: curve, Backend: backendID})
+		}
+	}
+	return targets, nil
+}
+
+// ParseCurve returns the curve named as ecc.ID.String()
+func ParseCurve(name string) (ecc.ID, error) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761} {
+		if strings.TrimSpace(name) == curve.String() {
+			return curve, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unknown curve %q", name)
+}
+
+// ParseBackend returns the backend named groth16 or plonk
+func ParseBackend(name string) (backend.ID, error) {
+	switch strings.TrimSpace(name) {
+	case "groth16":
+		return backend.GROTH16, nil
+	case "plonk":
+		return backend.PLONK, nil
+	default:
+		return backend.UNKNOWN, fmt.Errorf("unknown backend %q", name)
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/examples/cubic"
 	"github.com/consensys/gnark/gnarkd/pb"
-	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -84,13 +83,9 @@ func main() {
 	}()
 	go func() {
 		// send witness
-		conn, _ := tls.Dial("tcp", "127.0.0.1:9001", config)
-		defer conn.Close()
-
-		jobID, _ := uuid.Parse(r.JobID)
-		bjobID, _ := jobID.MarshalBinary()
-		conn.Write(bjobID)
-		conn.Write(buf.Bytes())
+		wStream, _ := c.SubmitWitness(ctx)
+		wStream.Send(&pb.WitnessChunk{JobID: r.JobID, Data: buf.Bytes()})
+		wStream.CloseAndRecv()
 	}()
 
 	<-done
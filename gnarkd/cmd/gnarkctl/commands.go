@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc"
+)
+
+// prover wraps the Groth16 or the Plonk client, depending on -backend
+// the two services have the same RPCs, only their stream types differ
+type prover struct {
+	unaryProver
+	subscribe     func(ctx context.Context, in *pb.SubscribeToProveJobRequest) (jobStream, error)
+	submitWitness func(ctx context.Context) (witnessStream, error)
+}
+
+// unaryProver is implemented by pb.Groth16Client and pb.PlonkClient
+type unaryProver interface {
+	Prove(ctx context.Context, in *pb.ProveRequest, opts ...grpc.CallOption) (*pb.ProveResult, error)
+	Verify(ctx context.Context, in *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResult, error)
+	CreateProveJob(ctx context.Context, in *pb.CreateProveJobRequest, opts ...grpc.CallOption) (*pb.CreateProveJobResponse, error)
+	CancelProveJob(ctx context.Context, in *pb.CancelProveJobRequest, opts ...grpc.CallOption) (*pb.CancelProveJobResponse, error)
+	ListProveJob(ctx context.Context, in *pb.ListProveJobRequest, opts ...grpc.CallOption) (*pb.ListProveJobResponse, error)
+}
+
+type jobStream interface {
+	Recv() (*pb.ProveJobResult, error)
+}
+
+type witnessStream interface {
+	Send(*pb.WitnessChunk) error
+	CloseAndRecv() (*pb.SubmitWitnessResponse, error)
+}
+
+func newProver(conn *grpc.ClientConn) (*prover, error) {
+	switch *fBackend {
+	case "groth16":
+		c := pb.NewGroth16Client(conn)
+		return &prover{
+			unaryProver: c,
+			subscribe: func(ctx context.Context, in *pb.SubscribeToProveJobRequest) (jobStream, error) {
+				return c.SubscribeToProveJob(ctx, in)
+			},
+			submitWitness: func(ctx context.Context) (witnessStream, error) {
+				return c.SubmitWitness(ctx)
+			},
+		}, nil
+	case "plonk":
+		c := pb.NewPlonkClient(conn)
+		return &prover{
+			unaryProver: c,
+			subscribe: func(ctx context.Context, in *pb.SubscribeToProveJobRequest) (jobStream, error) {
+				return c.SubscribeToProveJob(ctx, in)
+			},
+			submitWitness: func(ctx context.Context) (witnessStream, error) {
+				return c.SubmitWitness(ctx)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %s", *fBackend)
+	}
+}
+
+func runProve(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("prove")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	witnessFile := fs.String("witness", "", "binary full witness file (see backend/witness)")
+	proofFile := fs.String("o", "", "file the binary proof is written to (the proof is printed if empty)")
+	fs.Parse(args)
+	if err := required(fs, "circuit", "witness"); err != nil {
+		return err
+	}
+
+	witness, err := ioutil.ReadFile(*witnessFile)
+	if err != nil {
+		return err
+	}
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	result, err := p.Prove(ctx, &pb.ProveRequest{CircuitID: *circuitID, Witness: witness})
+	if err != nil {
+		return err
+	}
+	if *proofFile != "" {
+		return ioutil.WriteFile(*proofFile, result.Proof, 0644)
+	}
+	return printResult(result)
+}
+
+func runVerify(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("verify")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	proofFile := fs.String("proof", "", "binary proof file")
+	witnessFile := fs.String("public", "", "binary public witness file (see backend/witness)")
+	fs.Parse(args)
+	if err := required(fs, "circuit", "proof", "public"); err != nil {
+		return err
+	}
+
+	proof, err := ioutil.ReadFile(*proofFile)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := ioutil.ReadFile(*witnessFile)
+	if err != nil {
+		return err
+	}
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	result, err := p.Verify(ctx, &pb.VerifyRequest{CircuitID: *circuitID, Proof: proof, PublicWitness: publicWitness})
+	if err != nil {
+		return err
+	}
+	if err := printResult(result); err != nil {
+		return err
+	}
+	if !result.Ok {
+		return fmt.Errorf("invalid proof")
+	}
+	return nil
+}
+
+func runBatchVerify(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("batch-verify")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	fs.Parse(args)
+	if err := required(fs, "circuit"); err != nil {
+		return err
+	}
+	if *fBackend != "groth16" {
+		return fmt.Errorf("batch-verify is only supported by groth16")
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("expected proof:public file pairs")
+	}
+
+	request := &pb.BatchVerifyRequest{CircuitID: *circuitID}
+	for _, arg := range fs.Args() {
+		files := strings.SplitN(arg, ":", 2)
+		if len(files) != 2 {
+			return fmt.Errorf("invalid argument %s, expected proof:public", arg)
+		}
+		proof, err := ioutil.ReadFile(files[0])
+		if err != nil {
+			return err
+		}
+		publicWitness, err := ioutil.ReadFile(files[1])
+		if err != nil {
+			return err
+		}
+		request.Proofs = append(request.Proofs, &pb.ProofToVerify{Proof: proof, PublicWitness: publicWitness})
+	}
+	result, err := pb.NewGroth16Client(conn).BatchVerify(ctx, request)
+	if err != nil {
+		return err
+	}
+	if err := printResult(result); err != nil {
+		return err
+	}
+	for _, r := range result.Results {
+		if !r.Ok {
+			return fmt.Errorf("invalid proofs")
+		}
+	}
+	return nil
+}
+
+func runCreateJob(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("create-job")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	ttl := fs.Int64("ttl", 0, "TTL of the job in seconds, 0 for the server default")
+	priority := fs.Int("priority", 0, "jobs with higher priority run first")
+	callbackURL := fs.String("callback", "", "URL the result is POSTed to when the job finishes")
+	witnessFile := fs.String("witness", "", "binary full witness file, submitted once the job is created")
+	wait := fs.Bool("wait", false, "stream the status of the job until it finishes (requires -witness)")
+	proofFile := fs.String("o", "", "with -wait, file the binary proof is written to")
+	fs.Parse(args)
+	if err := required(fs, "circuit"); err != nil {
+		return err
+	}
+	if *wait && *witnessFile == "" {
+		return fmt.Errorf("-wait requires -witness")
+	}
+
+	request := &pb.CreateProveJobRequest{CircuitID: *circuitID}
+	if *ttl != 0 {
+		request.TTL = ttl
+	}
+	if *priority != 0 {
+		p := int32(*priority)
+		request.Priority = &p
+	}
+	if *callbackURL != "" {
+		request.CallbackURL = callbackURL
+	}
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	r, err := p.CreateProveJob(ctx, request)
+	if err != nil {
+		return err
+	}
+	if err := printResult(r); err != nil {
+		return err
+	}
+	if *witnessFile == "" {
+		return nil
+	}
+
+	if err := submitWitness(ctx, p, r.JobID, *witnessFile); err != nil {
+		return err
+	}
+	if !*wait {
+		return nil
+	}
+	stream, err := p.subscribe(ctx, &pb.SubscribeToProveJobRequest{JobID: r.JobID})
+	if err != nil {
+		return err
+	}
+	return watch(stream, *proofFile)
+}
+
+func runSubmitWitness(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("submit-witness")
+	jobID := fs.String("job", "", "jobID")
+	witnessFile := fs.String("witness", "", "binary full witness file (see backend/witness)")
+	fs.Parse(args)
+	if err := required(fs, "job", "witness"); err != nil {
+		return err
+	}
+
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	return submitWitness(ctx, p, *jobID, *witnessFile)
+}
+
+// submitWitness streams the witness file to the job
+func submitWitness(ctx context.Context, p *prover, jobID, witnessFile string) error {
+	f, err := os.Open(witnessFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stream, err := p.submitWitness(ctx)
+	if err != nil {
+		return err
+	}
+	chunk := &pb.WitnessChunk{JobID: jobID}
+	err = sendChunks(f, func(data []byte) error {
+		chunk.Data = data
+		if err := stream.Send(chunk); err != nil {
+			return streamError(err, func() error {
+				_, err := stream.CloseAndRecv()
+				return err
+			})
+		}
+		chunk = &pb.WitnessChunk{}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+// sendChunks reads r in chunks of chunkSize and sends them, until r is read or send fails
+// the chunks share a buffer: send must not keep them
+func sendChunks(r io.Reader, send func(data []byte) error) error {
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// streamError returns the error of a client stream whose Send failed with sendErr
+// the server closed the stream (sendErr is io.EOF): its error is returned by closeAndRecv
+func streamError(sendErr error, closeAndRecv func() error) error {
+	if err := closeAndRecv(); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func runWatchJob(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("watch-job")
+	jobID := fs.String("job", "", "jobID")
+	proofFile := fs.String("o", "", "file the binary proof is written to")
+	fs.Parse(args)
+	if err := required(fs, "job"); err != nil {
+		return err
+	}
+
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	stream, err := p.subscribe(ctx, &pb.SubscribeToProveJobRequest{JobID: *jobID})
+	if err != nil {
+		return err
+	}
+	return watch(stream, *proofFile)
+}
+
+// watch prints the status updates of a job until it finishes
+// the proof of a completed job is written to proofFile, if set
+func watch(stream jobStream, proofFile string) error {
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			return errStreamEnded
+		}
+		if err != nil {
+			return err
+		}
+		if proofFile != "" && result.Proof != nil {
+			if err := ioutil.WriteFile(proofFile, result.Proof, 0644); err != nil {
+				return err
+			}
+			result.Proof = nil
+		}
+		if err := printResult(result); err != nil {
+			return err
+		}
+		switch result.Status {
+		case pb.ProveJobResult_COMPLETED:
+			return nil
+		case pb.ProveJobResult_ERRORED:
+			return errJobFailed
+		}
+	}
+}
+
+func runCancelJob(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("cancel-job")
+	jobID := fs.String("job", "", "jobID")
+	fs.Parse(args)
+	if err := required(fs, "job"); err != nil {
+		return err
+	}
+
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	_, err = p.CancelProveJob(ctx, &pb.CancelProveJobRequest{JobID: *jobID})
+	return err
+}
+
+func runListJobs(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	newFlagSet("list-jobs").Parse(args)
+
+	p, err := newProver(conn)
+	if err != nil {
+		return err
+	}
+	r, err := p.ListProveJob(ctx, &pb.ListProveJobRequest{})
+	if err != nil {
+		return err
+	}
+	return printResult(r)
+}
+
+// fileTypes maps the extensions of the circuit files to their type
+var fileTypes = map[string]pb.RegisterCircuitRequest_FileType{
+	".r1cs":   pb.RegisterCircuitRequest_R1CS,
+	".pk":     pb.RegisterCircuitRequest_PROVING_KEY,
+	".vk":     pb.RegisterCircuitRequest_VERIFYING_KEY,
+	".spr":    pb.RegisterCircuitRequest_SPARSE_R1CS,
+	".pcs":    pb.RegisterCircuitRequest_COMMITMENT_SCHEME,
+	".schema": pb.RegisterCircuitRequest_WITNESS_SCHEMA,
+}
+
+func runRegisterCircuit(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("register-circuit")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gnarkctl register-circuit -circuit <curve/name> <files...>\n")
+		fmt.Fprintf(fs.Output(), "the type of the files is given by their extension (.r1cs, .pk, .vk, .spr, .pcs, .schema)\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := required(fs, "circuit"); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("missing circuit files")
+	}
+	for _, file := range fs.Args() {
+		if _, ok := fileTypes[filepath.Ext(file)]; !ok {
+			return fmt.Errorf("unknown type of file %s", file)
+		}
+	}
+
+	stream, err := pb.NewCircuitsClient(conn).RegisterCircuit(ctx)
+	if err != nil {
+		return err
+	}
+	for _, file := range fs.Args() {
+		if err := sendCircuitFile(stream, *circuitID, file); err != nil {
+			return err
+		}
+	}
+	info, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return printResult(info)
+}
+
+// sendCircuitFile streams the file of the circuit in chunks of chunkSize
+func sendCircuitFile(stream pb.Circuits_RegisterCircuitClient, circuitID, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return sendChunks(f, func(data []byte) error {
+		request := &pb.RegisterCircuitRequest{CircuitID: circuitID, FileType: fileTypes[filepath.Ext(file)], Data: data}
+		if err := stream.Send(request); err != nil {
+			return streamError(err, func() error {
+				_, err := stream.CloseAndRecv()
+				return err
+			})
+		}
+		return nil
+	})
+}
+
+func runListCircuits(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	newFlagSet("list-circuits").Parse(args)
+
+	r, err := pb.NewCircuitsClient(conn).ListCircuits(ctx, &pb.ListCircuitsRequest{})
+	if err != nil {
+		return err
+	}
+	return printResult(r)
+}
+
+func runCircuitInfo(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("circuit-info")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	fs.Parse(args)
+	if err := required(fs, "circuit"); err != nil {
+		return err
+	}
+
+	info, err := pb.NewCircuitsClient(conn).GetCircuitInfo(ctx, &pb.GetCircuitInfoRequest{CircuitID: *circuitID})
+	if err != nil {
+		return err
+	}
+	return printResult(info)
+}
+
+func runUnloadCircuit(ctx context.Context, conn *grpc.ClientConn, args []string) error {
+	fs := newFlagSet("unload-circuit")
+	circuitID := fs.String("circuit", "", "circuitID, e.g. bn254/cubic")
+	deleteFiles := fs.Bool("delete", false, "also delete the files of the circuit on the server")
+	fs.Parse(args)
+	if err := required(fs, "circuit"); err != nil {
+		return err
+	}
+
+	_, err := pb.NewCircuitsClient(conn).UnloadCircuit(ctx, &pb.UnloadCircuitRequest{CircuitID: *circuitID, DeleteFiles: *deleteFiles})
+	return err
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/gnarkd/server"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// startServer starts gnarkd on a bufconn listener, with a copy of the bn254/cubic circuit in its circuit directory
+// it returns a connection to it and the directory of the circuit files
+func startServer(t *testing.T) (*grpc.ClientConn, string) {
+	assert := require.New(t)
+
+	circuitDir := t.TempDir()
+	cubicDir := filepath.Join(circuitDir, "bn254", "cubic")
+	assert.NoError(os.MkdirAll(cubicDir, 0700))
+	for _, ext := range []string{".r1cs", ".pk", ".vk"} {
+		data, err := ioutil.ReadFile(filepath.Join("../../circuits/bn254/cubic", "cubic"+ext))
+		assert.NoError(err)
+		assert.NoError(ioutil.WriteFile(filepath.Join(cubicDir, "cubic"+ext), data, 0600))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s, err := server.NewServer(ctx, zap.NewNop().Sugar(), circuitDir)
+	assert.NoError(err)
+
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGroth16Server(grpcServer, s)
+	pb.RegisterCircuitsServer(grpcServer, s.Circuits())
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, cubicDir
+}
+
+// writeWitness writes the full witness of the cubic circuit for x and y in dir, and returns its file
+func writeWitness(t *testing.T, dir string, x, y int) string {
+	assert := require.New(t)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(x)
+	w.Y.Assign(y)
+	_, err := witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	file := filepath.Join(dir, "cubic.wit")
+	assert.NoError(ioutil.WriteFile(file, bWitness.Bytes(), 0600))
+	return file
+}
+
+// checkProof checks that the proof file is a valid proof of the cubic circuit for y
+func checkProof(t *testing.T, cubicDir, proofFile string, y int) {
+	assert := require.New(t)
+
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	f, err := os.Open(filepath.Join(cubicDir, "cubic.vk"))
+	assert.NoError(err)
+	defer f.Close()
+	_, err = vk.ReadFrom(f)
+	assert.NoError(err)
+
+	proof := groth16.NewProof(ecc.BN254)
+	data, err := ioutil.ReadFile(proofFile)
+	assert.NoError(err)
+	_, err = proof.ReadFrom(bytes.NewReader(data))
+	assert.NoError(err)
+	var w cubic.Circuit
+	w.Y.Assign(y)
+	var bPublic bytes.Buffer
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &w)
+	assert.NoError(err)
+	assert.NoError(groth16.ReadAndVerify(proof, vk, &bPublic))
+}
+
+func TestProve(t *testing.T) {
+	assert := require.New(t)
+	conn, cubicDir := startServer(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	witnessFile := writeWitness(t, dir, 3, 35)
+	proofFile := filepath.Join(dir, "cubic.proof")
+	assert.NoError(runProve(ctx, conn, []string{"-circuit", "bn254/cubic", "-witness", witnessFile, "-o", proofFile}))
+	checkProof(t, cubicDir, proofFile, 35)
+
+	// the solver error is returned
+	witnessFile = writeWitness(t, dir, 4, 42)
+	err := runProve(ctx, conn, []string{"-circuit", "bn254/cubic", "-witness", witnessFile})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateJobWait(t *testing.T) {
+	assert := require.New(t)
+	conn, cubicDir := startServer(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	witnessFile := writeWitness(t, dir, 3, 35)
+	proofFile := filepath.Join(dir, "cubic.proof")
+	assert.NoError(runCreateJob(ctx, conn, []string{"-circuit", "bn254/cubic", "-witness", witnessFile, "-wait", "-o", proofFile}))
+	checkProof(t, cubicDir, proofFile, 35)
+
+	// the witness is refused when it is submitted
+	witnessFile = writeWitness(t, dir, 4, 42)
+	err := runCreateJob(ctx, conn, []string{"-circuit", "bn254/cubic", "-witness", witnessFile, "-wait"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestRegisterCircuit(t *testing.T) {
+	assert := require.New(t)
+	conn, cubicDir := startServer(t)
+	ctx := context.Background()
+
+	files := []string{filepath.Join(cubicDir, "cubic.r1cs"), filepath.Join(cubicDir, "cubic.pk"), filepath.Join(cubicDir, "cubic.vk")}
+	assert.NoError(runRegisterCircuit(ctx, conn, append([]string{"-circuit", "bn254/cubic2"}, files...)))
+	info, err := pb.NewCircuitsClient(conn).GetCircuitInfo(ctx, &pb.GetCircuitInfoRequest{CircuitID: "bn254/cubic2"})
+	assert.NoError(err)
+	assert.Equal("bn254/cubic2", info.CircuitID)
+
+	// the server closes the stream: its error is returned
+	err = runRegisterCircuit(ctx, conn, append([]string{"-circuit", "bn254/cubic"}, files...))
+	assert.Equal(codes.AlreadyExists, status.Code(err))
+}
+
+// endedStream is a job stream ending before the job finishes
+type endedStream struct {
+	results []*pb.ProveJobResult
+}
+
+func (s *endedStream) Recv() (*pb.ProveJobResult, error) {
+	if len(s.results) == 0 {
+		return nil, io.EOF
+	}
+	result := s.results[0]
+	s.results = s.results[1:]
+	return result, nil
+}
+
+func TestWatchStreamEnded(t *testing.T) {
+	assert := require.New(t)
+
+	stream := &endedStream{results: []*pb.ProveJobResult{{Status: pb.ProveJobResult_QUEUED}, {Status: pb.ProveJobResult_RUNNING}}}
+	assert.Equal(errStreamEnded, watch(stream, ""))
+}
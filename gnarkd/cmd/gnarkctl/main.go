@@ -0,0 +1,217 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnarkctl is a command-line client for gnarkd
+//
+//	gnarkctl [global flags] <command> [command flags]
+//
+// run gnarkctl -h for the list of commands. Results are printed as json on stdout.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// -------------------------------------------------------------------------------------------------
+// global flags
+var (
+	fAddress  = flag.String("addr", "127.0.0.1:9002", "gnarkd gRPC address")
+	fCAFile   = flag.String("ca_file", "", "CA verifying the gnarkd certificate (the system roots if empty)")
+	fInsecure = flag.Bool("insecure", false, "don't verify the gnarkd certificate; INSECURE, the token isn't sent")
+	fCertFile = flag.String("cert_file", "", "TLS client certificate file")
+	fKeyFile  = flag.String("key_file", "", "TLS client key file")
+	fToken    = flag.String("token", "", "bearer token authenticating the calls, $GNARKD_TOKEN if empty")
+	fBackend  = flag.String("backend", "groth16", "proving backend of the circuit: groth16 or plonk")
+	fTimeout  = flag.Duration("timeout", 0, "timeout of the command, 0 for none")
+)
+
+// command of gnarkctl, run parses args (the arguments following the command name) and calls gnarkd
+type command struct {
+	usage string
+	run   func(ctx context.Context, conn *grpc.ClientConn, args []string) error
+}
+
+var commands = map[string]command{
+	"prove":            {"prove a witness synchronously", runProve},
+	"verify":           {"verify a proof", runVerify},
+	"batch-verify":     {"verify groth16 proofs in a batch (proof:public file pairs)", runBatchVerify},
+	"create-job":       {"create a prove job, optionally submit its witness and wait for the result", runCreateJob},
+	"submit-witness":   {"send the witness of a job", runSubmitWitness},
+	"watch-job":        {"stream the status of a job until it finishes", runWatchJob},
+	"cancel-job":       {"cancel a job", runCancelJob},
+	"list-jobs":        {"list the jobs", runListJobs},
+	"register-circuit": {"upload the files of a circuit", runRegisterCircuit},
+	"list-circuits":    {"list the loaded circuits", runListCircuits},
+	"circuit-info":     {"describe a circuit", runCircuitInfo},
+	"unload-circuit":   {"unload a circuit", runUnloadCircuit},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(cmd, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "gnarkctl %s: %s\n", flag.Arg(0), err)
+		printSolverError(err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gnarkctl [flags] <command> [command flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+func run(cmd command, args []string) error {
+	config, err := getTLSConfig()
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.Dial(*fAddress, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if *fTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *fTimeout)
+		defer cancel()
+	}
+	token := *fToken
+	if token == "" {
+		token = os.Getenv("GNARKD_TOKEN")
+	}
+	if token != "" && *fInsecure {
+		// the token would be sent to whichever server answers
+		return errors.New("refusing to send the token with -insecure, the gnarkd certificate isn't verified")
+	}
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	return cmd.run(ctx, conn, args)
+}
+
+// getTLSConfig returns the TLS config of the connection to gnarkd
+// the gnarkd certificate is verified with -ca_file, or the system roots; only -insecure skips the verification
+func getTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{}
+	if *fInsecure {
+		if *fCAFile != "" {
+			return nil, errors.New("-insecure and -ca_file are exclusive")
+		}
+		config.InsecureSkipVerify = true
+	} else if *fCAFile != "" {
+		caCert, err := ioutil.ReadFile(*fCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("couldn't parse CA %s", *fCAFile)
+		}
+	}
+	if *fCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(*fCertFile, *fKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// newFlagSet returns the flag set of a command
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("gnarkctl "+name, flag.ExitOnError)
+}
+
+// required returns an error if one of the flags isn't set
+func required(fs *flag.FlagSet, names ...string) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for _, name := range names {
+		if !set[name] {
+			return fmt.Errorf("missing -%s", name)
+		}
+	}
+	return nil
+}
+
+// printResult prints m as json on stdout
+func printResult(m proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printSolverError prints the failing constraint on stderr, if err has a pb.SolverError detail
+func printSolverError(err error) {
+	for _, detail := range status.Convert(err).Details() {
+		if serr, ok := detail.(*pb.SolverError); ok {
+			data, _ := protojson.MarshalOptions{Multiline: true}.Marshal(serr)
+			fmt.Fprintln(os.Stderr, string(data))
+		}
+	}
+}
+
+// errJobFailed is returned by the commands waiting for a job, if the job errored
+var errJobFailed = errors.New("job failed")
+
+// errStreamEnded is returned by the commands waiting for a job, if its status stream ends before it finishes
+var errStreamEnded = errors.New("the job status stream ended before the job finished")
+
+// chunkSize of the streamed witnesses and circuit files, under the default gRPC message size limit
+const chunkSize = 1 << 20
@@ -0,0 +1,190 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnarkd-worker computes the multi-exponentiations of the bn254 Groth16 proofs of gnarkd over proving key chunks
+//
+//	gnarkd-worker split -pk circuits/bn254/cubic/cubic.pk -n 2 -o out
+//	gnarkd-worker serve -chunk_dir chunks
+//
+// split writes the proving key without its multi-exponentiation points (out/cubic.pk, to replace the .pk of the
+// circuit in the circuit directory of gnarkd) and its chunks (out/cubic.0.pkchunk, out/cubic.1.pkchunk).
+// serve loads the chunks of <chunk_dir>/bn254/<circuit>/ (one .pkchunk per circuit) and serves the MSMWorker
+// gRPC service to gnarkd (-msm_workers).
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/gnarkd/worker"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "serve":
+		err = runServe(os.Args[2:])
+	case "split":
+		err = runSplit(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gnarkd-worker %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gnarkd-worker <command> [command flags]\n\ncommands:\n")
+	fmt.Fprintf(os.Stderr, "  %-8s %s\n", "serve", "serve the multi-exponentiations over the proving key chunks")
+	fmt.Fprintf(os.Stderr, "  %-8s %s\n", "split", "split a bn254 Groth16 proving key in chunks")
+}
+
+// runServe serves the MSMWorker service until SIGINT or SIGTERM
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	fChunkDir := flags.String("chunk_dir", "chunks", "proving key chunks root directory")
+	fgRPCPort := flags.Int("grpc_port", 9005, "gRPC server port")
+	fCertFile := flags.String("cert_file", "certs/gnarkd.crt", "TLS cert file")
+	fKeyFile := flags.String("key_file", "certs/gnarkd.key", "TLS key file")
+	fClientCA := flags.String("client_ca_file", "", "CA verifying the certificate of gnarkd (client certificates are not checked if empty)")
+	flags.Parse(args)
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+	log := logger.Sugar()
+
+	w, err := worker.New(log, *fChunkDir)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(*fCertFile, *fKeyFile)
+	if err != nil {
+		return err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if *fClientCA != "" {
+		caCert, err := ioutil.ReadFile(*fClientCA)
+		if err != nil {
+			return err
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("couldn't parse client CA %s", *fClientCA)
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *fgRPCPort))
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(config)))
+	pb.RegisterMSMWorkerServer(s, w)
+
+	chDone := make(chan os.Signal, 1)
+	signal.Notify(chDone, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-chDone
+		s.GracefulStop()
+	}()
+
+	log.Infow("starting gnarkd-worker", "port", *fgRPCPort)
+	defer log.Warn("stopping gnarkd-worker")
+	return s.Serve(lis)
+}
+
+// runSplit splits a proving key, see groth16_bn254.SplitProvingKey
+func runSplit(args []string) error {
+	flags := flag.NewFlagSet("split", flag.ExitOnError)
+	fPk := flags.String("pk", "", "bn254 Groth16 proving key file")
+	fN := flags.Int("n", 2, "number of chunks (one per worker)")
+	fOut := flags.String("o", ".", "output directory")
+	flags.Parse(args)
+	if *fPk == "" || *fN < 1 {
+		flags.Usage()
+		return fmt.Errorf("-pk and a positive -n are required")
+	}
+
+	in, err := os.Open(*fPk)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(*fOut, 0700); err != nil {
+		return err
+	}
+	name := strings.TrimSuffix(filepath.Base(*fPk), filepath.Ext(*fPk))
+
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	create := func(path string) (io.Writer, error) {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		return f, nil
+	}
+	pk, err := create(filepath.Join(*fOut, name+".pk"))
+	if err != nil {
+		return err
+	}
+	chunks := make([]io.Writer, *fN)
+	for i := range chunks {
+		if chunks[i], err = create(filepath.Join(*fOut, fmt.Sprintf("%s.%d%s", name, i, worker.ChunkExtension))); err != nil {
+			return err
+		}
+	}
+	if err := groth16_bn254.SplitProvingKey(in, pk, chunks); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Println(f.Name())
+	}
+	files = nil
+	return nil
+}
@@ -0,0 +1,453 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gateway provides a REST/JSON front-end to the Groth16 service of gnarkd/server.
+//
+// The gateway calls the Server in-process, it serves
+//
+//	POST   /v1/groth16/prove               prove (sync)
+//	POST   /v1/groth16/verify              verify
+//	POST   /v1/groth16/batch-verify        verify many proofs (json encoded pb.BatchVerifyRequest)
+//	POST   /v1/groth16/jobs                create a prove job, with its witness or not
+//	GET    /v1/groth16/jobs                list the jobs
+//	DELETE /v1/groth16/jobs/{id}           cancel a job
+//	POST   /v1/groth16/jobs/{id}/witness   send the witness of a job
+//	GET    /v1/groth16/jobs/{id}/events    job status changes, as server-sent events
+//
+// Witnesses are sent either as binary (application/octet-stream body, or base64 "witness" field of a json body)
+// or as named values ("values" field of a json body, e.g. {"X": "3", "Y": 35}), if the circuit has a witness schema.
+// Errors are returned as {"error": "message"}, with a HTTP status matching the gRPC status of the Server.
+package gateway
+
+import (
+	"bytes"
+	context "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/gnarkd/server"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	prefix        = "/v1/groth16/"
+	maxBodySize   = 1 << 30 // witnesses of large circuits are large
+	octetStream   = "application/octet-stream"
+	groth16Method = "/gnarkd.Groth16/"
+)
+
+// Gateway serves the Groth16 service of a Server over HTTP/JSON
+type Gateway struct {
+	s          *server.Server
+	authorizer *server.Authorizer
+	log        *zap.SugaredLogger
+}
+
+// New returns a Gateway to s
+// if authorizer is not nil, requests are authorized as the gRPC calls, with the TLS client certificate
+// or the bearer token in the Authorization header
+func New(s *server.Server, log *zap.SugaredLogger, authorizer *server.Authorizer) *Gateway {
+	return &Gateway{s: s, authorizer: authorizer, log: log}
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case len(path) == 1 && path[0] == "prove":
+		g.handle(w, r, http.MethodPost, g.prove)
+	case len(path) == 1 && path[0] == "verify":
+		g.handle(w, r, http.MethodPost, g.verify)
+	case len(path) == 1 && path[0] == "batch-verify":
+		g.handle(w, r, http.MethodPost, g.batchVerify)
+	case len(path) == 1 && path[0] == "jobs":
+		if r.Method == http.MethodGet {
+			g.handle(w, r, http.MethodGet, g.listJobs)
+		} else {
+			g.handle(w, r, http.MethodPost, g.createJob)
+		}
+	case len(path) == 2 && path[0] == "jobs":
+		g.handle(w, r, http.MethodDelete, func(r *http.Request) (proto.Message, error) {
+			return g.cancelJob(r, path[1])
+		})
+	case len(path) == 3 && path[0] == "jobs" && path[2] == "witness":
+		g.handle(w, r, http.MethodPost, func(r *http.Request) (proto.Message, error) {
+			return g.submitWitness(r, path[1])
+		})
+	case len(path) == 3 && path[0] == "jobs" && path[2] == "events":
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+			return
+		}
+		g.subscribe(w, r, path[1])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handle checks the method of r and writes the result of handler
+func (g *Gateway) handle(w http.ResponseWriter, r *http.Request, method string, handler func(*http.Request) (proto.Message, error)) {
+	if r.Method != method {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+	result, err := handler(r)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	data, err := marshaler.Marshal(result)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+var marshaler = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// witnessRequest is the json body of the requests carrying a witness
+type witnessRequest struct {
+	CircuitID string                     `json:"circuitID"`
+	Witness   []byte                     `json:"witness,omitempty"` // binary witness, base64 encoded
+	Values    map[string]json.RawMessage `json:"values,omitempty"`  // values named as in the witness schema, json strings or numbers
+
+	// CreateProveJob
+	TTL         *int64  `json:"ttl,omitempty"`
+	Priority    *int32  `json:"priority,omitempty"`
+	CallbackURL *string `json:"callbackURL,omitempty"`
+
+	// Verify
+	Proof []byte `json:"proof,omitempty"` // base64 encoded
+}
+
+// readRequest parses the body of r
+// an application/octet-stream body is a binary witness, the circuitID is then a query parameter
+func readRequest(r *http.Request) (*witnessRequest, error) {
+	var req witnessRequest
+	if r.Header.Get("Content-Type") == octetStream {
+		witness, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "couldn't read body: %s", err)
+		}
+		req.CircuitID = r.URL.Query().Get("circuitID")
+		req.Witness = witness
+		return &req, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid json body: %s", err)
+	}
+	return &req, nil
+}
+
+// witness returns the binary witness of the request, nil if the request has none
+func (g *Gateway) witness(req *witnessRequest, full bool) ([]byte, error) {
+	if req.Values == nil {
+		return req.Witness, nil
+	}
+	if req.Witness != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "witness and values are exclusive")
+	}
+	values := make(map[string]string, len(req.Values))
+	for name, raw := range req.Values {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			// not a json string, use the number as is
+			s = string(bytes.TrimSpace(raw))
+		}
+		values[name] = s
+	}
+	return g.s.EncodeWitness(req.CircuitID, values, full)
+}
+
+// authorize returns the context to call method of the Server with, see server.Authorizer
+func (g *Gateway) authorize(r *http.Request, method string, req interface{}) (context.Context, error) {
+	ctx := r.Context()
+	if g.authorizer == nil {
+		return ctx, nil
+	}
+	if r.TLS != nil {
+		// verified client certificates authenticate the client, as for the gRPC services
+		ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+	}
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", auth))
+	}
+	return g.authorizer.Authorize(ctx, groth16Method+method, req)
+}
+
+func (g *Gateway) prove(r *http.Request) (proto.Message, error) {
+	req, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	request := &pb.ProveRequest{CircuitID: req.CircuitID}
+	ctx, err := g.authorize(r, "Prove", request)
+	if err != nil {
+		return nil, err
+	}
+	if request.Witness, err = g.witness(req, true); err != nil {
+		return nil, err
+	}
+	return g.s.Prove(ctx, request)
+}
+
+func (g *Gateway) verify(r *http.Request) (proto.Message, error) {
+	req, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	request := &pb.VerifyRequest{CircuitID: req.CircuitID, Proof: req.Proof}
+	ctx, err := g.authorize(r, "Verify", request)
+	if err != nil {
+		return nil, err
+	}
+	if request.PublicWitness, err = g.witness(req, false); err != nil {
+		return nil, err
+	}
+	return g.s.Verify(ctx, request)
+}
+
+// batchVerify takes the protojson encoding of a pb.BatchVerifyRequest, proofs and public witnesses being binary
+func (g *Gateway) batchVerify(r *http.Request) (proto.Message, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "couldn't read body: %s", err)
+	}
+	request := &pb.BatchVerifyRequest{}
+	if err := protojson.Unmarshal(body, request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid json body: %s", err)
+	}
+	ctx, err := g.authorize(r, "BatchVerify", request)
+	if err != nil {
+		return nil, err
+	}
+	return g.s.BatchVerify(ctx, request)
+}
+
+// createJob creates a job, and submits its witness if the request has one
+func (g *Gateway) createJob(r *http.Request) (proto.Message, error) {
+	req, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	request := &pb.CreateProveJobRequest{CircuitID: req.CircuitID, TTL: req.TTL, Priority: req.Priority, CallbackURL: req.CallbackURL}
+	ctx, err := g.authorize(r, "CreateProveJob", request)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := g.witness(req, true)
+	if err != nil {
+		return nil, err
+	}
+	response, err := g.s.CreateProveJob(ctx, request)
+	if err != nil || witness == nil {
+		return response, err
+	}
+	if err := g.submit(r, response.JobID, witness); err != nil {
+		// the job would wait for a witness until its TTL, holding a job of the quota of the client
+		if _, errCancel := g.s.CancelProveJob(ctx, &pb.CancelProveJobRequest{JobID: response.JobID}); errCancel != nil {
+			g.log.Warnw("couldn't cancel the job of a refused witness", "jobID", response.JobID, "err", errCancel)
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+func (g *Gateway) listJobs(r *http.Request) (proto.Message, error) {
+	request := &pb.ListProveJobRequest{}
+	ctx, err := g.authorize(r, "ListProveJob", request)
+	if err != nil {
+		return nil, err
+	}
+	return g.s.ListProveJob(ctx, request)
+}
+
+func (g *Gateway) cancelJob(r *http.Request, jobID string) (proto.Message, error) {
+	request := &pb.CancelProveJobRequest{JobID: jobID}
+	ctx, err := g.authorize(r, "CancelProveJob", request)
+	if err != nil {
+		return nil, err
+	}
+	return g.s.CancelProveJob(ctx, request)
+}
+
+func (g *Gateway) submitWitness(r *http.Request, jobID string) (proto.Message, error) {
+	req, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := g.witness(req, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.submit(r, jobID, witness); err != nil {
+		return nil, err
+	}
+	return &pb.SubmitWitnessResponse{}, nil
+}
+
+// submit calls Server.SubmitWitness with the witness of a job
+func (g *Gateway) submit(r *http.Request, jobID string, witness []byte) error {
+	chunk := &pb.WitnessChunk{JobID: jobID, Data: witness}
+	ctx, err := g.authorize(r, "SubmitWitness", chunk)
+	if err != nil {
+		return err
+	}
+	return g.s.SubmitWitness(&witnessStream{ctx: ctx, chunks: []*pb.WitnessChunk{chunk}})
+}
+
+// subscribe streams the status changes of a job as server-sent events
+func (g *Gateway) subscribe(w http.ResponseWriter, r *http.Request, jobID string) {
+	request := &pb.SubscribeToProveJobRequest{JobID: jobID}
+	ctx, err := g.authorize(r, "SubscribeToProveJob", request)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		g.writeError(w, status.Errorf(codes.Unimplemented, "streaming not supported"))
+		return
+	}
+
+	// unknown jobs are reported before the events stream starts
+	if err := g.s.LookupProveJob(ctx, jobID); err != nil {
+		g.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &jobStream{ctx: ctx, send: func(result *pb.ProveJobResult) error {
+		data, err := marshaler.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}}
+	if err := g.s.SubscribeToProveJob(request, stream); err != nil {
+		g.log.Warnw("job events stream closed", "jobID", jobID, "err", err)
+		data, _ := json.Marshal(errorResponse{Error: status.Convert(err).Message()})
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	}
+}
+
+// witnessStream feeds chunks to Server.SubmitWitness
+type witnessStream struct {
+	grpc.ServerStream // nil, only the methods below are called by the Server
+	ctx               context.Context
+	chunks            []*pb.WitnessChunk
+}
+
+func (s *witnessStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *witnessStream) Recv() (*pb.WitnessChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *witnessStream) SendAndClose(*pb.SubmitWitnessResponse) error {
+	return nil
+}
+
+// jobStream forwards the results sent by Server.SubscribeToProveJob
+type jobStream struct {
+	grpc.ServerStream // nil, only the methods below are called by the Server
+	ctx               context.Context
+	send              func(*pb.ProveJobResult) error
+}
+
+func (s *jobStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *jobStream) Send(result *pb.ProveJobResult) error {
+	return s.send(result)
+}
+
+type errorResponse struct {
+	Error       string          `json:"error"`
+	SolverError json.RawMessage `json:"solverError,omitempty"` // pb.SolverError, if the witness doesn't solve the circuit
+}
+
+// writeError writes err with the HTTP status matching its gRPC status
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	code, ok := httpStatus[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	if code == http.StatusInternalServerError {
+		g.log.Errorw("gateway request failed", "err", err)
+	}
+	response := errorResponse{Error: st.Message()}
+	if serr := server.SolverErrorFromStatus(err); serr != nil {
+		response.SolverError, _ = marshaler.Marshal(serr)
+	}
+	writeJSON(w, code, response)
+}
+
+var httpStatus = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusConflict,
+	codes.FailedPrecondition: http.StatusConflict,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.NotFound:           http.StatusNotFound,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.Canceled:           499, // client closed request
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
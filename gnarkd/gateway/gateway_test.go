@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	context "context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/gnarkd/server"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGateway(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := zap.NewNop().Sugar()
+	s, err := server.NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+
+	ts := httptest.NewServer(New(s, log, nil))
+	defer ts.Close()
+
+	post := func(path, contentType string, body []byte, result proto.Message) int {
+		resp, err := http.Post(ts.URL+path, contentType, bytes.NewReader(body))
+		assert.NoError(err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(err)
+		if resp.StatusCode == http.StatusOK && result != nil {
+			assert.NoError(protojson.Unmarshal(data, result))
+		}
+		return resp.StatusCode
+	}
+	postJSON := func(path string, body interface{}, result proto.Message) int {
+		data, err := json.Marshal(body)
+		assert.NoError(err)
+		return post(path, "application/json", data, result)
+	}
+
+	// prove and verify with named values
+	var proveResult pb.ProveResult
+	code := postJSON("/v1/groth16/prove", map[string]interface{}{
+		"circuitID": "bn254/cubic",
+		"values":    map[string]interface{}{"x": "3", "Y": 35},
+	}, &proveResult)
+	assert.Equal(http.StatusOK, code)
+	assert.NotEmpty(proveResult.Proof)
+
+	var verifyResult pb.VerifyResult
+	code = postJSON("/v1/groth16/verify", map[string]interface{}{
+		"circuitID": "bn254/cubic",
+		"proof":     proveResult.Proof,
+		"values":    map[string]interface{}{"Y": "0x23"},
+	}, &verifyResult)
+	assert.Equal(http.StatusOK, code)
+	assert.True(verifyResult.Ok)
+
+	// batch verify, with binary public witnesses
+	var (
+		public  cubic.Circuit
+		bPublic bytes.Buffer
+	)
+	public.Y.Assign(35)
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &public)
+	assert.NoError(err)
+	batch, err := protojson.Marshal(&pb.BatchVerifyRequest{CircuitID: "bn254/cubic", Proofs: []*pb.ProofToVerify{
+		{Proof: proveResult.Proof, PublicWitness: bPublic.Bytes()},
+		{Proof: proveResult.Proof, PublicWitness: bPublic.Bytes()[:4]},
+	}})
+	assert.NoError(err)
+	var batchResult pb.BatchVerifyResult
+	code = post("/v1/groth16/batch-verify", "application/json", batch, &batchResult)
+	assert.Equal(http.StatusOK, code)
+	assert.Len(batchResult.Results, 2)
+	assert.True(batchResult.Results[0].Ok)
+	assert.False(batchResult.Results[1].Ok)
+
+	// errors
+	code = postJSON("/v1/groth16/prove", map[string]interface{}{
+		"circuitID": "bn254/cubic",
+		"values":    map[string]interface{}{"x": "3"},
+	}, nil)
+	assert.Equal(http.StatusBadRequest, code, "unassigned variable")
+	code = postJSON("/v1/groth16/prove", map[string]interface{}{
+		"circuitID": "bn254/unknown",
+		"values":    map[string]interface{}{"x": "3"},
+	}, nil)
+	assert.Equal(http.StatusNotFound, code)
+
+	// a witness which doesn't solve the circuit returns the failing constraint
+	resp, err := http.Post(ts.URL+"/v1/groth16/prove", "application/json",
+		strings.NewReader(`{"circuitID": "bn254/cubic", "values": {"x": 4, "Y": 42}}`))
+	assert.NoError(err)
+	var errResponse errorResponse
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&errResponse))
+	resp.Body.Close()
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	var serr pb.SolverError
+	assert.NoError(protojson.Unmarshal(errResponse.SolverError, &serr))
+	assert.Contains(serr.Wires, "Y")
+
+	resp, err = http.Get(ts.URL + "/v1/groth16/prove")
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// prove job, with a binary witness and the status as server-sent events
+	var job pb.CreateProveJobResponse
+	code = postJSON("/v1/groth16/jobs", map[string]interface{}{"circuitID": "bn254/cubic"}, &job)
+	assert.Equal(http.StatusOK, code)
+
+	var list pb.ListProveJobResponse
+	resp, err = http.Get(ts.URL + "/v1/groth16/jobs")
+	assert.NoError(err)
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(err)
+	assert.NoError(protojson.Unmarshal(data, &list))
+	assert.Len(list.Jobs, 1)
+	assert.Equal(job.JobID, list.Jobs[0].JobID)
+
+	events, err := http.Get(ts.URL + "/v1/groth16/jobs/" + job.JobID + "/events")
+	assert.NoError(err)
+	defer events.Body.Close()
+	assert.Equal(http.StatusOK, events.StatusCode)
+	assert.Equal("text/event-stream", events.Header.Get("Content-Type"))
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	code = post("/v1/groth16/jobs/"+job.JobID+"/witness", octetStream, bWitness.Bytes(), nil)
+	assert.Equal(http.StatusOK, code)
+	code = post("/v1/groth16/jobs/"+job.JobID+"/witness", octetStream, bWitness.Bytes(), nil)
+	assert.Equal(http.StatusConflict, code, "job isn't waiting for a witness anymore")
+
+	var result pb.ProveJobResult
+	scanner := bufio.NewScanner(events.Body)
+	for result.Status != pb.ProveJobResult_COMPLETED && scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		assert.NoError(protojson.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &result))
+		assert.NotEqual(pb.ProveJobResult_ERRORED, result.Status)
+	}
+	assert.Equal(pb.ProveJobResult_COMPLETED, result.Status)
+	assert.NotEmpty(result.Proof)
+
+	resp, err = http.Get(ts.URL + "/v1/groth16/jobs/unknown/events")
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	// the job of a refused witness is cancelled
+	code = postJSON("/v1/groth16/jobs", map[string]interface{}{
+		"circuitID": "bn254/cubic",
+		"values":    map[string]interface{}{"x": 4, "Y": 42},
+	}, nil)
+	assert.Equal(http.StatusBadRequest, code)
+	resp, err = http.Get(ts.URL + "/v1/groth16/jobs")
+	assert.NoError(err)
+	data, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(err)
+	assert.NoError(protojson.Unmarshal(data, &list))
+	assert.Len(list.Jobs, 2)
+	for _, j := range list.Jobs {
+		if j.JobID != job.JobID {
+			assert.Equal(pb.ProveJobResult_ERRORED, j.Status)
+		}
+	}
+}
@@ -16,31 +16,59 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/consensys/gnark/gnarkd/gateway"
 	"github.com/consensys/gnark/gnarkd/pb"
 	"github.com/consensys/gnark/gnarkd/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // -------------------------------------------------------------------------------------------------
 // flags
 var (
-	fCircuitDir  = flag.String("circuit_dir", "circuits", "circuits root directory")
-	fCertFile    = flag.String("cert_file", "certs/gnarkd.crt", "TLS cert file")
-	fKeyFile     = flag.String("key_file", "certs/gnarkd.key", "TLS key file")
-	fgRPCPort    = flag.Int("grpc_port", 9002, "gRPC server port")
-	fWitnessPort = flag.Int("witness_port", 9001, "witness tcp socket port")
+	fCircuitDir    = flag.String("circuit_dir", "circuits", "circuits root directory")
+	fCertFile      = flag.String("cert_file", "certs/gnarkd.crt", "TLS cert file")
+	fKeyFile       = flag.String("key_file", "certs/gnarkd.key", "TLS key file")
+	fgRPCPort      = flag.Int("grpc_port", 9002, "gRPC server port")
+	fJobDir        = flag.String("job_dir", "", "directory persisting the jobs across restarts (jobs are kept in memory if empty)")
+	fWorkers       = flag.Int("workers", 1, "number of jobs proved concurrently")
+	fMemoryLimit   = flag.Int64("memory_limit", 0, "estimated memory (bytes) the running jobs may use, 0 for no limit")
+	fCacheSize     = flag.Int64("circuit_cache_size", 0, "estimated memory (bytes) of the proving keys and constraint systems kept in memory, 0 for no limit")
+	fMaxJobs       = flag.Int("max_jobs_per_circuit", 0, "max number of concurrent jobs per circuit, 0 for no limit")
+	fClientCA      = flag.String("client_ca_file", "", "CA verifying the TLS client certificates (client certificates are not checked if empty)")
+	fMetricsPort   = flag.Int("metrics_port", 9003, "HTTP port serving /metrics (prometheus) and /healthz, 0 to disable")
+	fHTTPPort      = flag.Int("http_port", 0, "HTTPS port serving the REST/JSON gateway to the Groth16 service, 0 to disable")
+	fCallbackKey   = flag.String("callback_secret_file", "", "file containing the key signing the job results POSTed to the callback URLs (HMAC-SHA256)")
+	fCallbackHosts = flag.String("callback_hosts", "", "comma separated hosts (host or host:port) the job results may be POSTed to (no http(s) callbacks if empty)")
+	fCallbackDir   = flag.String("callback_socket_dir", "", "directory of the unix sockets the job results may be POSTed to (no unix socket callbacks if empty)")
+	fShutdown      = flag.Duration("shutdown_timeout", 5*time.Minute, "on SIGTERM, time given to the running jobs to finish before exiting")
+	fAuthConfig    = flag.String("auth_config", "", "json file mapping client identities to the circuits and RPCs they may use (no authorization if empty)")
+	fMSMWorkers    = flag.String("msm_workers", "", "comma separated gRPC addresses of the gnarkd-worker processes computing the multi-exponentiations of the bn254 Groth16 proofs")
+	fMSMWorkerCA   = flag.String("msm_worker_ca_file", "", "CA verifying the gnarkd-worker certificates (system roots if empty)")
+	fCircuitAdmin  = flag.Bool("circuit_admin", false, "serve the Circuits service (register, list and unload circuits at runtime); requires -auth_config, only the identities allowed to call its RPCs may use it")
+	fMaxFileSize   = flag.Int64("max_circuit_file_size", 4<<30, "max size (bytes) of each file of a circuit received by RegisterCircuit")
+	fMaxUploadSize = flag.Int64("max_circuit_upload_size", 8<<30, "max size (bytes) of all the files of a circuit received by RegisterCircuit")
+	fInsecurePlonk = flag.Bool("insecure_plonk", false, "serve the PLONK circuits; INSECURE: PLONK proofs use a mock polynomial commitment scheme, forged proofs verify")
 )
 
 // -------------------------------------------------------------------------------------------------
@@ -70,7 +98,7 @@ func main() {
 	defer logger.Sync() // flushes buffer, if any
 
 	// catch sigterm and sigint.
-	chDone := make(chan os.Signal)
+	chDone := make(chan os.Signal, 1)
 	signal.Notify(chDone, syscall.SIGTERM, syscall.SIGINT)
 
 	// Parse flags
@@ -79,20 +107,73 @@ func main() {
 	// init the server and load the ciruits
 	serverCtx, cancelServer := context.WithCancel(context.Background())
 	defer cancelServer()
-	gnarkdServer, err := server.NewServer(serverCtx, log, *fCircuitDir)
-	if err != nil {
-		log.Fatalw("couldn't init gnarkd", "err", err)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	options := []server.Option{server.WithMetrics(registry)}
+	if *fJobDir != "" {
+		store, err := server.NewDirJobStore(*fJobDir)
+		if err != nil {
+			log.Fatalw("couldn't open job store", "err", err)
+		}
+		options = append(options, server.WithJobStore(store))
+	}
+	if *fCallbackKey != "" {
+		secret, err := ioutil.ReadFile(*fCallbackKey)
+		if err != nil {
+			log.Fatalw("couldn't read callback secret", "err", err)
+		}
+		options = append(options, server.WithCallbackSecret(bytes.TrimSpace(secret)))
+	}
+
+	// circuits can be uploaded, and their directories deleted, with the Circuits service: authenticated clients only
+	if *fCircuitAdmin && *fAuthConfig == "" {
+		log.Fatal("-circuit_admin requires -auth_config")
 	}
 
-	// gnarkd listens on 2 sockets: 1 for the gRPC APIs, and 1 to receive (async) witnesses
+	if *fCallbackHosts != "" || *fCallbackDir != "" {
+		var hosts []string
+		if *fCallbackHosts != "" {
+			hosts = strings.Split(*fCallbackHosts, ",")
+		}
+		options = append(options, server.WithCallbacks(hosts, *fCallbackDir))
+	}
 
-	// ---------------------------------------------------------------------------------------------
-	// WITNESS LISTENER
-	wLis, err := tls.Listen("tcp", fmt.Sprintf(":%d", *fWitnessPort), getTLSConfig())
+	// client identities and their permissions, the server enforces their concurrent jobs
+	var authorizer *server.Authorizer
+	if *fAuthConfig != "" {
+		config, err := server.LoadAuthConfig(*fAuthConfig)
+		if err != nil {
+			log.Fatalw("couldn't load auth config", "err", err)
+		}
+		authorizer, err = server.NewAuthorizer(config)
+		if err != nil {
+			log.Fatalw("invalid auth config", "err", err)
+		}
+		options = append(options, server.WithQuotas(authorizer.Quotas()))
+	}
+	// gnarkd-worker processes, holding the proving key chunks of the circuits proved with them
+	if *fMSMWorkers != "" {
+		conns, err := dialMSMWorkers(strings.Split(*fMSMWorkers, ","))
+		if err != nil {
+			log.Fatalw("couldn't connect to the MSM workers", "err", err)
+		}
+		options = append(options, server.WithMSMWorkers(conns...))
+	}
+	if *fInsecurePlonk {
+		log.Warn("-insecure_plonk is set: PLONK proofs use a mock polynomial commitment scheme and are NOT sound, a forged proof verifies")
+		options = append(options, server.WithInsecurePlonk())
+	}
+	options = append(options,
+		server.WithWorkers(*fWorkers),
+		server.WithMemoryLimit(*fMemoryLimit),
+		server.WithCircuitCacheSize(*fCacheSize),
+		server.WithMaxConcurrentJobs("", *fMaxJobs),
+		server.WithCircuitUploadLimits(*fMaxFileSize, *fMaxUploadSize),
+	)
+	gnarkdServer, err := server.NewServer(serverCtx, log, *fCircuitDir, options...)
 	if err != nil {
-		log.Fatalw("failed to listen tcp", "err", err)
+		log.Fatalw("couldn't init gnarkd", "err", err)
 	}
-	go gnarkdServer.StartWitnessListener(wLis)
 
 	// ---------------------------------------------------------------------------------------------
 	// gRPC endpoint
@@ -100,44 +181,158 @@ func main() {
 	if err != nil {
 		log.Fatalw("failed to listen tcp", "err", err)
 	}
-	creds, err := credentials.NewServerTLSFromFile(*fCertFile, *fKeyFile)
+	tlsConfig, err := getTLSConfig()
 	if err != nil {
 		log.Fatalw("failed to setup TLS", "err", err)
 	}
-	s := grpc.NewServer(grpc.Creds(creds))
+	grpcOptions := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+	if authorizer != nil {
+		grpcOptions = append(grpcOptions,
+			grpc.ChainUnaryInterceptor(authorizer.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(authorizer.StreamInterceptor()),
+		)
+	}
+	s := grpc.NewServer(grpcOptions...)
 	pb.RegisterGroth16Server(s, gnarkdServer)
+	if *fInsecurePlonk {
+		pb.RegisterPlonkServer(s, gnarkdServer.Plonk())
+	}
+	if *fCircuitAdmin {
+		pb.RegisterCircuitsServer(s, gnarkdServer.Circuits())
+	}
 
+	// gRPC health checking, gnarkd serves once the circuits are loaded
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	// ---------------------------------------------------------------------------------------------
+	// metrics and health HTTP endpoint
+	if *fMetricsPort != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			if gnarkdServer.ShuttingDown() {
+				http.Error(w, "shutting down", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("ok"))
+		})
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", *fMetricsPort), mux); err != nil {
+				log.Fatalw("failed to start metrics server", "err", err)
+			}
+		}()
+	}
+
+	// ---------------------------------------------------------------------------------------------
+	// REST/JSON gateway
+	var httpServer *http.Server
+	if *fHTTPPort != 0 {
+		httpServer = &http.Server{
+			Addr:      fmt.Sprintf(":%d", *fHTTPPort),
+			Handler:   gateway.New(gnarkdServer, log, authorizer),
+			TLSConfig: tlsConfig,
+		}
+		go func() {
+			if err := httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatalw("failed to start gateway", "err", err)
+			}
+		}()
+	}
+
+	// main returns once the server is stopped, not when Serve returns: GracefulStop makes Serve return
+	// while the in-flight calls finish
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		defer signal.Stop(chDone)
 		<-chDone
 
-		// clean up  if SIGINT or SIGTERM is caught.
+		// if SIGINT or SIGTERM is caught, stop accepting jobs and let the running ones finish.
+		// the jobs still running at the deadline are queued again at restart if they are persisted (-job_dir)
+		healthServer.Shutdown()
+		ctx, cancel := context.WithTimeout(context.Background(), *fShutdown)
+		defer cancel()
+		if err := gnarkdServer.Shutdown(ctx); err != nil && *fJobDir == "" {
+			log.Warn("running jobs are lost, use -job_dir to queue them again at restart")
+		}
+		if httpServer != nil {
+			if err := httpServer.Shutdown(ctx); err != nil {
+				httpServer.Close()
+			}
+		}
 		cancelServer()
-		s.GracefulStop()
-		wLis.Close()
+
+		grpcStopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(grpcStopped)
+		}()
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			s.Stop()
+		}
 	}()
 
 	if err := s.Serve(grpcLis); err != nil {
 		log.Fatalw("failed to start server", "err", err)
 	}
+	<-stopped
 }
 
-func getTLSConfig() *tls.Config {
-	crt, err := ioutil.ReadFile(*fCertFile)
+// getTLSConfig loads the server certificate, and the CA verifying the client certificates if any
+// without auth config, a valid client certificate is required; otherwise clients may authenticate with a token instead
+func getTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(*fCertFile, *fKeyFile)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	key, err := ioutil.ReadFile(*fKeyFile)
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if *fClientCA == "" {
+		return config, nil
+	}
+	caCert, err := ioutil.ReadFile(*fClientCA)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("couldn't parse client CA %s", *fClientCA)
 	}
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	if *fAuthConfig != "" {
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
 
-	cer, err := tls.X509KeyPair(crt, key)
+// dialMSMWorkers connects to the gnarkd-worker processes, authenticating with the gnarkd certificate
+func dialMSMWorkers(addrs []string) ([]grpc.ClientConnInterface, error) {
+	cert, err := tls.LoadX509KeyPair(*fCertFile, *fKeyFile)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	return &tls.Config{Certificates: []tls.Certificate{cer}}
-
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if *fMSMWorkerCA != "" {
+		caCert, err := ioutil.ReadFile(*fMSMWorkerCA)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("couldn't parse MSM worker CA %s", *fMSMWorkerCA)
+		}
+	}
+	conns := make([]grpc.ClientConnInterface, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, err := grpc.Dial(strings.TrimSpace(addr), grpc.WithTransportCredentials(credentials.NewTLS(config)))
+		if err != nil {
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
 }
 
 func newZapConfig() zap.Config {
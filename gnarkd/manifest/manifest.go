@@ -0,0 +1,200 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest describes the files of a gnarkd circuit: the Manifest written next to them by gnarkd/build,
+// and the WitnessSchema naming the variables of its witnesses.
+//
+// It is shared by gnarkd and the circuit builders, which don't need the gnarkd server.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
+)
+
+// Ext is the extension of the manifest of a circuit, <name>.manifest.json
+const Ext = ".manifest.json"
+
+// Manifest describes a built circuit, it is written next to its files
+type Manifest struct {
+	CircuitID string `json:"circuitID"` // as in the gnarkd APIs, e.g. bn254/cubic
+	Curve     string `json:"curve"`
+	Backend   string `json:"backend"`
+
+	NbConstraints       int `json:"nbConstraints"`
+	NbInternalVariables int `json:"nbInternalVariables"`
+	NbSecretVariables   int `json:"nbSecretVariables"`
+	NbPublicVariables   int `json:"nbPublicVariables"`
+
+	// Witness lists the variables of the binary witnesses, in order
+	Witness WitnessSchema `json:"witness"`
+
+	// Files of the circuit, by file name
+	Files map[string]File `json:"files"`
+}
+
+// File is a file of a built circuit
+type File struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"` // hex encoded
+}
+
+// Read reads the manifest of the circuit in circuitDir (e.g. circuits/bn254/cubic), without checking its files
+// see Manifest.Check. The error satisfies os.IsNotExist if the circuit has no manifest.
+func Read(circuitDir string) (*Manifest, error) {
+	path := filepath.Join(circuitDir, filepath.Base(circuitDir)+Ext)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// Check checks the sizes and hashes of the files of the circuit in circuitDir
+func (manifest *Manifest) Check(circuitDir string) error {
+	for name, expected := range manifest.Files {
+		file, err := HashFile(filepath.Join(circuitDir, name))
+		if err != nil {
+			return err
+		}
+		if file != expected {
+			return fmt.Errorf("%s doesn't match the manifest of %s", name, manifest.CircuitID)
+		}
+	}
+	return nil
+}
+
+// CheckSizes checks the sizes of the files of the circuit in circuitDir, without reading them
+// their hashes are checked as they are read, see Reader
+func (manifest *Manifest) CheckSizes(circuitDir string) error {
+	for name, expected := range manifest.Files {
+		info, err := os.Stat(filepath.Join(circuitDir, name))
+		if err != nil {
+			return err
+		}
+		if info.Size() != expected.Size {
+			return fmt.Errorf("%s doesn't match the manifest of %s", name, manifest.CircuitID)
+		}
+	}
+	return nil
+}
+
+// HashFile returns the size and hash of the file at path
+func HashFile(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+	r := NewReader(f)
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return File{}, err
+	}
+	return r.File(), nil
+}
+
+// Reader computes the size and hash of the data it reads
+//
+// the file of a circuit is checked against its manifest as it is read, not before: it can't be swapped
+// between the check and the read
+type Reader struct {
+	r    io.Reader
+	h    hash.Hash
+	size int64
+}
+
+// NewReader returns a Reader reading from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r, h: sha256.New()}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	r.size += int64(n)
+	return n, err
+}
+
+// File returns the size and hash of the data read so far
+func (r *Reader) File() File {
+	return File{Size: r.size, SHA256: hex.EncodeToString(r.h.Sum(nil))}
+}
+
+// Check reads the rest of the data and checks its size and hash are the expected ones
+func (r *Reader) Check(expected File) error {
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+	if r.File() != expected {
+		return errors.New("the file doesn't match the manifest")
+	}
+	return nil
+}
+
+// WitnessSchema lists the names of the public and secret variables of a circuit, in the order of the binary witness
+//
+// it is stored next to the circuit files (.schema, json encoded) and lets gnarkd clients send witnesses
+// as named values
+type WitnessSchema struct {
+	Public []string `json:"public"`
+	Secret []string `json:"secret"`
+}
+
+// NewWitnessSchema returns the schema of the witnesses of circuit
+// names are the ones of the JSON witnesses (see backend/witness.ReadJSON): field name or gnark tag,
+// nested names are joined with "." and slice elements indexed, e.g. Path[0].Sibling
+func NewWitnessSchema(circuit frontend.Circuit) (WitnessSchema, error) {
+	var schema WitnessSchema
+	var handler parser.PathHandler = func(visibility compiled.Visibility, path []string, tInput reflect.Value) error {
+		if visibility == compiled.Secret {
+			schema.Secret = append(schema.Secret, parser.PathName(path))
+		} else if visibility == compiled.Public {
+			schema.Public = append(schema.Public, parser.PathName(path))
+		}
+		return nil
+	}
+	err := parser.VisitPaths(circuit, handler, reflect.TypeOf(frontend.Variable{}))
+	return schema, err
+}
+
+// ReadWitnessSchema reads a json encoded WitnessSchema
+func ReadWitnessSchema(r io.Reader) (*WitnessSchema, error) {
+	var schema WitnessSchema
+	if err := json.NewDecoder(r).Decode(&schema); err != nil {
+		return nil, fmt.Errorf("invalid witness schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// Size returns the number of variables in a full witness
+func (schema *WitnessSchema) Size() int {
+	return len(schema.Public) + len(schema.Secret)
+}
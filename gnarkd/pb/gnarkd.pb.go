@@ -86,7 +86,120 @@ func (x ProveJobResult_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ProveJobResult_Status.Descriptor instead.
 func (ProveJobResult_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_gnarkd_proto_rawDescGZIP(), []int{10, 0}
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{16, 0}
+}
+
+type RegisterCircuitRequest_FileType int32
+
+const (
+	RegisterCircuitRequest_R1CS              RegisterCircuitRequest_FileType = 0
+	RegisterCircuitRequest_PROVING_KEY       RegisterCircuitRequest_FileType = 1
+	RegisterCircuitRequest_VERIFYING_KEY     RegisterCircuitRequest_FileType = 2
+	RegisterCircuitRequest_SPARSE_R1CS       RegisterCircuitRequest_FileType = 3
+	RegisterCircuitRequest_COMMITMENT_SCHEME RegisterCircuitRequest_FileType = 4
+	RegisterCircuitRequest_WITNESS_SCHEMA    RegisterCircuitRequest_FileType = 5 // optional, json encoded list of the public and secret variable names
+)
+
+// Enum value maps for RegisterCircuitRequest_FileType.
+var (
+	RegisterCircuitRequest_FileType_name = map[int32]string{
+		0: "R1CS",
+		1: "PROVING_KEY",
+		2: "VERIFYING_KEY",
+		3: "SPARSE_R1CS",
+		4: "COMMITMENT_SCHEME",
+		5: "WITNESS_SCHEMA",
+	}
+	RegisterCircuitRequest_FileType_value = map[string]int32{
+		"R1CS":              0,
+		"PROVING_KEY":       1,
+		"VERIFYING_KEY":     2,
+		"SPARSE_R1CS":       3,
+		"COMMITMENT_SCHEME": 4,
+		"WITNESS_SCHEMA":    5,
+	}
+)
+
+func (x RegisterCircuitRequest_FileType) Enum() *RegisterCircuitRequest_FileType {
+	p := new(RegisterCircuitRequest_FileType)
+	*p = x
+	return p
+}
+
+func (x RegisterCircuitRequest_FileType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RegisterCircuitRequest_FileType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_gnarkd_proto_enumTypes[1].Descriptor()
+}
+
+func (RegisterCircuitRequest_FileType) Type() protoreflect.EnumType {
+	return &file_pb_gnarkd_proto_enumTypes[1]
+}
+
+func (x RegisterCircuitRequest_FileType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RegisterCircuitRequest_FileType.Descriptor instead.
+func (RegisterCircuitRequest_FileType) EnumDescriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{19, 0}
+}
+
+type MultiExpChunk_MSM int32
+
+const (
+	MultiExpChunk_G1_A MultiExpChunk_MSM = 0
+	MultiExpChunk_G1_B MultiExpChunk_MSM = 1
+	MultiExpChunk_G1_Z MultiExpChunk_MSM = 2
+	MultiExpChunk_G1_K MultiExpChunk_MSM = 3
+	MultiExpChunk_G2_B MultiExpChunk_MSM = 4
+)
+
+// Enum value maps for MultiExpChunk_MSM.
+var (
+	MultiExpChunk_MSM_name = map[int32]string{
+		0: "G1_A",
+		1: "G1_B",
+		2: "G1_Z",
+		3: "G1_K",
+		4: "G2_B",
+	}
+	MultiExpChunk_MSM_value = map[string]int32{
+		"G1_A": 0,
+		"G1_B": 1,
+		"G1_Z": 2,
+		"G1_K": 3,
+		"G2_B": 4,
+	}
+)
+
+func (x MultiExpChunk_MSM) Enum() *MultiExpChunk_MSM {
+	p := new(MultiExpChunk_MSM)
+	*p = x
+	return p
+}
+
+func (x MultiExpChunk_MSM) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MultiExpChunk_MSM) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_gnarkd_proto_enumTypes[2].Descriptor()
+}
+
+func (MultiExpChunk_MSM) Type() protoreflect.EnumType {
+	return &file_pb_gnarkd_proto_enumTypes[2]
+}
+
+func (x MultiExpChunk_MSM) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MultiExpChunk_MSM.Descriptor instead.
+func (MultiExpChunk_MSM) EnumDescriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{30, 0}
 }
 
 type ProveRequest struct {
@@ -301,17 +414,17 @@ func (x *VerifyResult) GetOk() bool {
 	return false
 }
 
-type CreateProveJobRequest struct {
+type BatchVerifyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CircuitID string `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
-	TTL       *int64 `protobuf:"varint,2,opt,name=TTL,proto3,oneof" json:"TTL,omitempty"` // in seconds
+	CircuitID string           `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+	Proofs    []*ProofToVerify `protobuf:"bytes,2,rep,name=proofs,proto3" json:"proofs,omitempty"`
 }
 
-func (x *CreateProveJobRequest) Reset() {
-	*x = CreateProveJobRequest{}
+func (x *BatchVerifyRequest) Reset() {
+	*x = BatchVerifyRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -319,13 +432,13 @@ func (x *CreateProveJobRequest) Reset() {
 	}
 }
 
-func (x *CreateProveJobRequest) String() string {
+func (x *BatchVerifyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateProveJobRequest) ProtoMessage() {}
+func (*BatchVerifyRequest) ProtoMessage() {}
 
-func (x *CreateProveJobRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchVerifyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -337,35 +450,36 @@ func (x *CreateProveJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateProveJobRequest.ProtoReflect.Descriptor instead.
-func (*CreateProveJobRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchVerifyRequest.ProtoReflect.Descriptor instead.
+func (*BatchVerifyRequest) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateProveJobRequest) GetCircuitID() string {
+func (x *BatchVerifyRequest) GetCircuitID() string {
 	if x != nil {
 		return x.CircuitID
 	}
 	return ""
 }
 
-func (x *CreateProveJobRequest) GetTTL() int64 {
-	if x != nil && x.TTL != nil {
-		return *x.TTL
+func (x *BatchVerifyRequest) GetProofs() []*ProofToVerify {
+	if x != nil {
+		return x.Proofs
 	}
-	return 0
+	return nil
 }
 
-type CreateProveJobResponse struct {
+type ProofToVerify struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+	Proof         []byte `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	PublicWitness []byte `protobuf:"bytes,2,opt,name=publicWitness,proto3" json:"publicWitness,omitempty"`
 }
 
-func (x *CreateProveJobResponse) Reset() {
-	*x = CreateProveJobResponse{}
+func (x *ProofToVerify) Reset() {
+	*x = ProofToVerify{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -373,13 +487,13 @@ func (x *CreateProveJobResponse) Reset() {
 	}
 }
 
-func (x *CreateProveJobResponse) String() string {
+func (x *ProofToVerify) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateProveJobResponse) ProtoMessage() {}
+func (*ProofToVerify) ProtoMessage() {}
 
-func (x *CreateProveJobResponse) ProtoReflect() protoreflect.Message {
+func (x *ProofToVerify) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -391,28 +505,35 @@ func (x *CreateProveJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateProveJobResponse.ProtoReflect.Descriptor instead.
-func (*CreateProveJobResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProofToVerify.ProtoReflect.Descriptor instead.
+func (*ProofToVerify) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CreateProveJobResponse) GetJobID() string {
+func (x *ProofToVerify) GetProof() []byte {
 	if x != nil {
-		return x.JobID
+		return x.Proof
 	}
-	return ""
+	return nil
 }
 
-type CancelProveJobRequest struct {
+func (x *ProofToVerify) GetPublicWitness() []byte {
+	if x != nil {
+		return x.PublicWitness
+	}
+	return nil
+}
+
+type BatchVerifyResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+	Results []*ProofVerifyResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // results[i] is the result of proofs[i]
 }
 
-func (x *CancelProveJobRequest) Reset() {
-	*x = CancelProveJobRequest{}
+func (x *BatchVerifyResult) Reset() {
+	*x = BatchVerifyResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -420,13 +541,13 @@ func (x *CancelProveJobRequest) Reset() {
 	}
 }
 
-func (x *CancelProveJobRequest) String() string {
+func (x *BatchVerifyResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelProveJobRequest) ProtoMessage() {}
+func (*BatchVerifyResult) ProtoMessage() {}
 
-func (x *CancelProveJobRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchVerifyResult) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -438,26 +559,29 @@ func (x *CancelProveJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelProveJobRequest.ProtoReflect.Descriptor instead.
-func (*CancelProveJobRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchVerifyResult.ProtoReflect.Descriptor instead.
+func (*BatchVerifyResult) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *CancelProveJobRequest) GetJobID() string {
+func (x *BatchVerifyResult) GetResults() []*ProofVerifyResult {
 	if x != nil {
-		return x.JobID
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-type CancelProveJobResponse struct {
+type ProofVerifyResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Ok  bool    `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Err *string `protobuf:"bytes,2,opt,name=err,proto3,oneof" json:"err,omitempty"` // set if the proof is invalid, or if it or its public witness can't be decoded
 }
 
-func (x *CancelProveJobResponse) Reset() {
-	*x = CancelProveJobResponse{}
+func (x *ProofVerifyResult) Reset() {
+	*x = ProofVerifyResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -465,13 +589,13 @@ func (x *CancelProveJobResponse) Reset() {
 	}
 }
 
-func (x *CancelProveJobResponse) String() string {
+func (x *ProofVerifyResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelProveJobResponse) ProtoMessage() {}
+func (*ProofVerifyResult) ProtoMessage() {}
 
-func (x *CancelProveJobResponse) ProtoReflect() protoreflect.Message {
+func (x *ProofVerifyResult) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -483,19 +607,40 @@ func (x *CancelProveJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelProveJobResponse.ProtoReflect.Descriptor instead.
-func (*CancelProveJobResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProofVerifyResult.ProtoReflect.Descriptor instead.
+func (*ProofVerifyResult) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{7}
 }
 
-type ListProveJobRequest struct {
+func (x *ProofVerifyResult) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ProofVerifyResult) GetErr() string {
+	if x != nil && x.Err != nil {
+		return *x.Err
+	}
+	return ""
+}
+
+type CreateProveJobRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	CircuitID string `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+	TTL       *int64 `protobuf:"varint,2,opt,name=TTL,proto3,oneof" json:"TTL,omitempty"`           // in seconds
+	Priority  *int32 `protobuf:"varint,3,opt,name=priority,proto3,oneof" json:"priority,omitempty"` // jobs with higher priority run first, default 0
+	// when the job finishes, its ProveJobResult (json encoded) is POSTed to callbackURL (http, https,
+	// or unix:///path/to/socket), signed with the callback secret of the server if any
+	CallbackURL *string `protobuf:"bytes,4,opt,name=callbackURL,proto3,oneof" json:"callbackURL,omitempty"`
 }
 
-func (x *ListProveJobRequest) Reset() {
-	*x = ListProveJobRequest{}
+func (x *CreateProveJobRequest) Reset() {
+	*x = CreateProveJobRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -503,13 +648,13 @@ func (x *ListProveJobRequest) Reset() {
 	}
 }
 
-func (x *ListProveJobRequest) String() string {
+func (x *CreateProveJobRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProveJobRequest) ProtoMessage() {}
+func (*CreateProveJobRequest) ProtoMessage() {}
 
-func (x *ListProveJobRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateProveJobRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -521,21 +666,49 @@ func (x *ListProveJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProveJobRequest.ProtoReflect.Descriptor instead.
-func (*ListProveJobRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateProveJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateProveJobRequest) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{8}
 }
 
-type ListProveJobResponse struct {
+func (x *CreateProveJobRequest) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *CreateProveJobRequest) GetTTL() int64 {
+	if x != nil && x.TTL != nil {
+		return *x.TTL
+	}
+	return 0
+}
+
+func (x *CreateProveJobRequest) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *CreateProveJobRequest) GetCallbackURL() string {
+	if x != nil && x.CallbackURL != nil {
+		return *x.CallbackURL
+	}
+	return ""
+}
+
+type CreateProveJobResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Jobs []*ProveJobResult `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
 }
 
-func (x *ListProveJobResponse) Reset() {
-	*x = ListProveJobResponse{}
+func (x *CreateProveJobResponse) Reset() {
+	*x = CreateProveJobResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -543,13 +716,13 @@ func (x *ListProveJobResponse) Reset() {
 	}
 }
 
-func (x *ListProveJobResponse) String() string {
+func (x *CreateProveJobResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProveJobResponse) ProtoMessage() {}
+func (*CreateProveJobResponse) ProtoMessage() {}
 
-func (x *ListProveJobResponse) ProtoReflect() protoreflect.Message {
+func (x *CreateProveJobResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -561,31 +734,29 @@ func (x *ListProveJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProveJobResponse.ProtoReflect.Descriptor instead.
-func (*ListProveJobResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateProveJobResponse.ProtoReflect.Descriptor instead.
+func (*CreateProveJobResponse) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ListProveJobResponse) GetJobs() []*ProveJobResult {
+func (x *CreateProveJobResponse) GetJobID() string {
 	if x != nil {
-		return x.Jobs
+		return x.JobID
 	}
-	return nil
+	return ""
 }
 
-type ProveJobResult struct {
+type WitnessChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobID  string                `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
-	Status ProveJobResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=gnarkd.ProveJobResult_Status" json:"status,omitempty"`
-	Err    *string               `protobuf:"bytes,3,opt,name=err,proto3,oneof" json:"err,omitempty"`
-	Proof  []byte                `protobuf:"bytes,4,opt,name=proof,proto3,oneof" json:"proof,omitempty"`
+	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"` // set in the first chunk only
+	Data  []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`   // chunk of the binary encoded full witness, appended to the previous chunks
 }
 
-func (x *ProveJobResult) Reset() {
-	*x = ProveJobResult{}
+func (x *WitnessChunk) Reset() {
+	*x = WitnessChunk{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pb_gnarkd_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -593,13 +764,13 @@ func (x *ProveJobResult) Reset() {
 	}
 }
 
-func (x *ProveJobResult) String() string {
+func (x *WitnessChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProveJobResult) ProtoMessage() {}
+func (*WitnessChunk) ProtoMessage() {}
 
-func (x *ProveJobResult) ProtoReflect() protoreflect.Message {
+func (x *WitnessChunk) ProtoReflect() protoreflect.Message {
 	mi := &file_pb_gnarkd_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -611,40 +782,64 @@ func (x *ProveJobResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProveJobResult.ProtoReflect.Descriptor instead.
-func (*ProveJobResult) Descriptor() ([]byte, []int) {
+// Deprecated: Use WitnessChunk.ProtoReflect.Descriptor instead.
+func (*WitnessChunk) Descriptor() ([]byte, []int) {
 	return file_pb_gnarkd_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ProveJobResult) GetJobID() string {
+func (x *WitnessChunk) GetJobID() string {
 	if x != nil {
 		return x.JobID
 	}
 	return ""
 }
 
-func (x *ProveJobResult) GetStatus() ProveJobResult_Status {
+func (x *WitnessChunk) GetData() []byte {
 	if x != nil {
-		return x.Status
+		return x.Data
 	}
-	return ProveJobResult_WAITING_WITNESS
+	return nil
 }
 
-func (x *ProveJobResult) GetErr() string {
-	if x != nil && x.Err != nil {
-		return *x.Err
+type SubmitWitnessResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubmitWitnessResponse) Reset() {
+	*x = SubmitWitnessResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *ProveJobResult) GetProof() []byte {
-	if x != nil {
-		return x.Proof
+func (x *SubmitWitnessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitWitnessResponse) ProtoMessage() {}
+
+func (x *SubmitWitnessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type SubscribeToProveJobRequest struct {
+// Deprecated: Use SubmitWitnessResponse.ProtoReflect.Descriptor instead.
+func (*SubmitWitnessResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{11}
+}
+
+type CancelProveJobRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -652,23 +847,23 @@ type SubscribeToProveJobRequest struct {
 	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
 }
 
-func (x *SubscribeToProveJobRequest) Reset() {
-	*x = SubscribeToProveJobRequest{}
+func (x *CancelProveJobRequest) Reset() {
+	*x = CancelProveJobRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_gnarkd_proto_msgTypes[11]
+		mi := &file_pb_gnarkd_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SubscribeToProveJobRequest) String() string {
+func (x *CancelProveJobRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeToProveJobRequest) ProtoMessage() {}
+func (*CancelProveJobRequest) ProtoMessage() {}
 
-func (x *SubscribeToProveJobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_gnarkd_proto_msgTypes[11]
+func (x *CancelProveJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -679,19 +874,1046 @@ func (x *SubscribeToProveJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeToProveJobRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeToProveJobRequest) Descriptor() ([]byte, []int) {
-	return file_pb_gnarkd_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CancelProveJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelProveJobRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *SubscribeToProveJobRequest) GetJobID() string {
+func (x *CancelProveJobRequest) GetJobID() string {
 	if x != nil {
 		return x.JobID
 	}
 	return ""
 }
 
-var File_pb_gnarkd_proto protoreflect.FileDescriptor
+type CancelProveJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CancelProveJobResponse) Reset() {
+	*x = CancelProveJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelProveJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelProveJobResponse) ProtoMessage() {}
+
+func (x *CancelProveJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelProveJobResponse.ProtoReflect.Descriptor instead.
+func (*CancelProveJobResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{13}
+}
+
+type ListProveJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListProveJobRequest) Reset() {
+	*x = ListProveJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProveJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProveJobRequest) ProtoMessage() {}
+
+func (x *ListProveJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProveJobRequest.ProtoReflect.Descriptor instead.
+func (*ListProveJobRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{14}
+}
+
+type ListProveJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs []*ProveJobResult `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ListProveJobResponse) Reset() {
+	*x = ListProveJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProveJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProveJobResponse) ProtoMessage() {}
+
+func (x *ListProveJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProveJobResponse.ProtoReflect.Descriptor instead.
+func (*ListProveJobResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListProveJobResponse) GetJobs() []*ProveJobResult {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type ProveJobResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobID       string                `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+	Status      ProveJobResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=gnarkd.ProveJobResult_Status" json:"status,omitempty"`
+	Err         *string               `protobuf:"bytes,3,opt,name=err,proto3,oneof" json:"err,omitempty"`
+	Proof       []byte                `protobuf:"bytes,4,opt,name=proof,proto3,oneof" json:"proof,omitempty"`
+	SolverError *SolverError          `protobuf:"bytes,5,opt,name=solverError,proto3,oneof" json:"solverError,omitempty"` // set if the witness doesn't solve the circuit
+}
+
+func (x *ProveJobResult) Reset() {
+	*x = ProveJobResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProveJobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProveJobResult) ProtoMessage() {}
+
+func (x *ProveJobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProveJobResult.ProtoReflect.Descriptor instead.
+func (*ProveJobResult) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProveJobResult) GetJobID() string {
+	if x != nil {
+		return x.JobID
+	}
+	return ""
+}
+
+func (x *ProveJobResult) GetStatus() ProveJobResult_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ProveJobResult_WAITING_WITNESS
+}
+
+func (x *ProveJobResult) GetErr() string {
+	if x != nil && x.Err != nil {
+		return *x.Err
+	}
+	return ""
+}
+
+func (x *ProveJobResult) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *ProveJobResult) GetSolverError() *SolverError {
+	if x != nil {
+		return x.SolverError
+	}
+	return nil
+}
+
+// SolverError describes the constraint a witness doesn't satisfy
+// it is set in ProveJobResult, and in the details of the InvalidArgument status of Prove and SubmitWitness
+type SolverError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConstraintID uint64   `protobuf:"varint,1,opt,name=constraintID,proto3" json:"constraintID,omitempty"` // index of the constraint, in the constraints then the assertions of the circuit
+	DebugInfo    string   `protobuf:"bytes,2,opt,name=debugInfo,proto3" json:"debugInfo,omitempty"`        // debug message of the constraint (e.g. cs.AssertIsEqual) with the values of its wires
+	Wires        []string `protobuf:"bytes,3,rep,name=wires,proto3" json:"wires,omitempty"`                // wires of the constraint, named after the witness schema; internal[i] for internal wires
+}
+
+func (x *SolverError) Reset() {
+	*x = SolverError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SolverError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolverError) ProtoMessage() {}
+
+func (x *SolverError) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolverError.ProtoReflect.Descriptor instead.
+func (*SolverError) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SolverError) GetConstraintID() uint64 {
+	if x != nil {
+		return x.ConstraintID
+	}
+	return 0
+}
+
+func (x *SolverError) GetDebugInfo() string {
+	if x != nil {
+		return x.DebugInfo
+	}
+	return ""
+}
+
+func (x *SolverError) GetWires() []string {
+	if x != nil {
+		return x.Wires
+	}
+	return nil
+}
+
+type SubscribeToProveJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobID string `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+}
+
+func (x *SubscribeToProveJobRequest) Reset() {
+	*x = SubscribeToProveJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeToProveJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToProveJobRequest) ProtoMessage() {}
+
+func (x *SubscribeToProveJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToProveJobRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToProveJobRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubscribeToProveJobRequest) GetJobID() string {
+	if x != nil {
+		return x.JobID
+	}
+	return ""
+}
+
+type RegisterCircuitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID string                          `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"` // curve/name, e.g. bn254/cubic
+	FileType  RegisterCircuitRequest_FileType `protobuf:"varint,2,opt,name=fileType,proto3,enum=gnarkd.RegisterCircuitRequest_FileType" json:"fileType,omitempty"`
+	Data      []byte                          `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"` // chunk of the file, appended to the previous chunks of the same file
+}
+
+func (x *RegisterCircuitRequest) Reset() {
+	*x = RegisterCircuitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterCircuitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterCircuitRequest) ProtoMessage() {}
+
+func (x *RegisterCircuitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterCircuitRequest.ProtoReflect.Descriptor instead.
+func (*RegisterCircuitRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RegisterCircuitRequest) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *RegisterCircuitRequest) GetFileType() RegisterCircuitRequest_FileType {
+	if x != nil {
+		return x.FileType
+	}
+	return RegisterCircuitRequest_R1CS
+}
+
+func (x *RegisterCircuitRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type CircuitInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID           string `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+	Curve               string `protobuf:"bytes,2,opt,name=curve,proto3" json:"curve,omitempty"`
+	Backend             string `protobuf:"bytes,3,opt,name=backend,proto3" json:"backend,omitempty"`
+	NbConstraints       uint64 `protobuf:"varint,4,opt,name=nbConstraints,proto3" json:"nbConstraints,omitempty"`
+	NbInternalVariables uint64 `protobuf:"varint,5,opt,name=nbInternalVariables,proto3" json:"nbInternalVariables,omitempty"`
+	NbSecretVariables   uint64 `protobuf:"varint,6,opt,name=nbSecretVariables,proto3" json:"nbSecretVariables,omitempty"`
+	NbPublicVariables   uint64 `protobuf:"varint,7,opt,name=nbPublicVariables,proto3" json:"nbPublicVariables,omitempty"`
+	FullWitnessSize     uint64 `protobuf:"varint,8,opt,name=fullWitnessSize,proto3" json:"fullWitnessSize,omitempty"`     // in bytes
+	PublicWitnessSize   uint64 `protobuf:"varint,9,opt,name=publicWitnessSize,proto3" json:"publicWitnessSize,omitempty"` // in bytes
+}
+
+func (x *CircuitInfo) Reset() {
+	*x = CircuitInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CircuitInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitInfo) ProtoMessage() {}
+
+func (x *CircuitInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitInfo.ProtoReflect.Descriptor instead.
+func (*CircuitInfo) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CircuitInfo) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *CircuitInfo) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+func (x *CircuitInfo) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *CircuitInfo) GetNbConstraints() uint64 {
+	if x != nil {
+		return x.NbConstraints
+	}
+	return 0
+}
+
+func (x *CircuitInfo) GetNbInternalVariables() uint64 {
+	if x != nil {
+		return x.NbInternalVariables
+	}
+	return 0
+}
+
+func (x *CircuitInfo) GetNbSecretVariables() uint64 {
+	if x != nil {
+		return x.NbSecretVariables
+	}
+	return 0
+}
+
+func (x *CircuitInfo) GetNbPublicVariables() uint64 {
+	if x != nil {
+		return x.NbPublicVariables
+	}
+	return 0
+}
+
+func (x *CircuitInfo) GetFullWitnessSize() uint64 {
+	if x != nil {
+		return x.FullWitnessSize
+	}
+	return 0
+}
+
+func (x *CircuitInfo) GetPublicWitnessSize() uint64 {
+	if x != nil {
+		return x.PublicWitnessSize
+	}
+	return 0
+}
+
+type ListCircuitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListCircuitsRequest) Reset() {
+	*x = ListCircuitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCircuitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCircuitsRequest) ProtoMessage() {}
+
+func (x *ListCircuitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCircuitsRequest.ProtoReflect.Descriptor instead.
+func (*ListCircuitsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{21}
+}
+
+type ListCircuitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Circuits []*CircuitInfo `protobuf:"bytes,1,rep,name=circuits,proto3" json:"circuits,omitempty"`
+}
+
+func (x *ListCircuitsResponse) Reset() {
+	*x = ListCircuitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCircuitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCircuitsResponse) ProtoMessage() {}
+
+func (x *ListCircuitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCircuitsResponse.ProtoReflect.Descriptor instead.
+func (*ListCircuitsResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListCircuitsResponse) GetCircuits() []*CircuitInfo {
+	if x != nil {
+		return x.Circuits
+	}
+	return nil
+}
+
+type GetCircuitInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID string `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+}
+
+func (x *GetCircuitInfoRequest) Reset() {
+	*x = GetCircuitInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCircuitInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCircuitInfoRequest) ProtoMessage() {}
+
+func (x *GetCircuitInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCircuitInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetCircuitInfoRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetCircuitInfoRequest) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+type UnloadCircuitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID   string `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+	DeleteFiles bool   `protobuf:"varint,2,opt,name=deleteFiles,proto3" json:"deleteFiles,omitempty"`
+}
+
+func (x *UnloadCircuitRequest) Reset() {
+	*x = UnloadCircuitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnloadCircuitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnloadCircuitRequest) ProtoMessage() {}
+
+func (x *UnloadCircuitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnloadCircuitRequest.ProtoReflect.Descriptor instead.
+func (*UnloadCircuitRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UnloadCircuitRequest) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *UnloadCircuitRequest) GetDeleteFiles() bool {
+	if x != nil {
+		return x.DeleteFiles
+	}
+	return false
+}
+
+type UnloadCircuitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UnloadCircuitResponse) Reset() {
+	*x = UnloadCircuitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnloadCircuitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnloadCircuitResponse) ProtoMessage() {}
+
+func (x *UnloadCircuitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnloadCircuitResponse.ProtoReflect.Descriptor instead.
+func (*UnloadCircuitResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{25}
+}
+
+type ListChunksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListChunksRequest) Reset() {
+	*x = ListChunksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChunksRequest) ProtoMessage() {}
+
+func (x *ListChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChunksRequest.ProtoReflect.Descriptor instead.
+func (*ListChunksRequest) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{26}
+}
+
+type ListChunksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chunks []*ProvingKeyChunkInfo `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+}
+
+func (x *ListChunksResponse) Reset() {
+	*x = ListChunksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChunksResponse) ProtoMessage() {}
+
+func (x *ListChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChunksResponse.ProtoReflect.Descriptor instead.
+func (*ListChunksResponse) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListChunksResponse) GetChunks() []*ProvingKeyChunkInfo {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type ProvingKeyChunkInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID string      `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`
+	Ranges    []*MSMRange `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"` // indexed by MultiExpChunk.MSM
+}
+
+func (x *ProvingKeyChunkInfo) Reset() {
+	*x = ProvingKeyChunkInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProvingKeyChunkInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProvingKeyChunkInfo) ProtoMessage() {}
+
+func (x *ProvingKeyChunkInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProvingKeyChunkInfo.ProtoReflect.Descriptor instead.
+func (*ProvingKeyChunkInfo) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ProvingKeyChunkInfo) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *ProvingKeyChunkInfo) GetRanges() []*MSMRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+// MSMRange is the range [start, end) of the points of a multi-exponentiation held by a chunk, out of total points
+type MSMRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start uint64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Total uint64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *MSMRange) Reset() {
+	*x = MSMRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MSMRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSMRange) ProtoMessage() {}
+
+func (x *MSMRange) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSMRange.ProtoReflect.Descriptor instead.
+func (*MSMRange) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MSMRange) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *MSMRange) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *MSMRange) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type MultiExpChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CircuitID string            `protobuf:"bytes,1,opt,name=circuitID,proto3" json:"circuitID,omitempty"`                    // set in the first message only
+	Msm       MultiExpChunk_MSM `protobuf:"varint,2,opt,name=msm,proto3,enum=gnarkd.MultiExpChunk_MSM" json:"msm,omitempty"` // set in the first message only
+	Scalars   []byte            `protobuf:"bytes,3,opt,name=scalars,proto3" json:"scalars,omitempty"`                        // 32 bytes (big endian, regular form) per scalar, appended to the previous messages
+}
+
+func (x *MultiExpChunk) Reset() {
+	*x = MultiExpChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiExpChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiExpChunk) ProtoMessage() {}
+
+func (x *MultiExpChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiExpChunk.ProtoReflect.Descriptor instead.
+func (*MultiExpChunk) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MultiExpChunk) GetCircuitID() string {
+	if x != nil {
+		return x.CircuitID
+	}
+	return ""
+}
+
+func (x *MultiExpChunk) GetMsm() MultiExpChunk_MSM {
+	if x != nil {
+		return x.Msm
+	}
+	return MultiExpChunk_G1_A
+}
+
+func (x *MultiExpChunk) GetScalars() []byte {
+	if x != nil {
+		return x.Scalars
+	}
+	return nil
+}
+
+type MultiExpResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Point []byte `protobuf:"bytes,1,opt,name=point,proto3" json:"point,omitempty"` // uncompressed affine point, G1 or G2 depending on the msm
+}
+
+func (x *MultiExpResult) Reset() {
+	*x = MultiExpResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_gnarkd_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiExpResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiExpResult) ProtoMessage() {}
+
+func (x *MultiExpResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_gnarkd_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiExpResult.ProtoReflect.Descriptor instead.
+func (*MultiExpResult) Descriptor() ([]byte, []int) {
+	return file_pb_gnarkd_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MultiExpResult) GetPoint() []byte {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+var File_pb_gnarkd_proto protoreflect.FileDescriptor
 
 var file_pb_gnarkd_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x70, 0x62, 0x2f, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74,
@@ -711,74 +1933,272 @@ var file_pb_gnarkd_proto_rawDesc = []byte{
 	0x28, 0x0c, 0x52, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73,
 	0x73, 0x22, 0x1e, 0x0a, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c,
 	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f,
-	0x6b, 0x22, 0x54, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65,
-	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69,
-	0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
-	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x12, 0x15, 0x0a, 0x03, 0x54, 0x54, 0x4c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x54, 0x54, 0x4c, 0x88, 0x01, 0x01, 0x42,
-	0x06, 0x0a, 0x04, 0x5f, 0x54, 0x54, 0x4c, 0x22, 0x2e, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x22, 0x2d, 0x0a, 0x15, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x22, 0x18, 0x0a, 0x16, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
-	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x42, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x50,
-	0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2a, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0xf5, 0x01, 0x0a, 0x0e,
-	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
-	0x6f, 0x62, 0x49, 0x44, 0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72,
-	0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x15, 0x0a, 0x03, 0x65,
-	0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x65, 0x72, 0x72, 0x88,
-	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0c, 0x48, 0x01, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x88, 0x01, 0x01, 0x22, 0x52, 0x0a,
-	0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x13, 0x0a, 0x0f, 0x57, 0x41, 0x49, 0x54, 0x49,
-	0x4e, 0x47, 0x5f, 0x57, 0x49, 0x54, 0x4e, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
-	0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e,
-	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54,
-	0x45, 0x44, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x45, 0x44, 0x10,
-	0x04, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x72, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x22, 0x32, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
-	0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x32, 0xb6, 0x03, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x74,
-	0x68, 0x31, 0x36, 0x12, 0x32, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x12, 0x14, 0x2e, 0x67,
-	0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76,
-	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x35, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66,
-	0x79, 0x12, 0x15, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66,
-	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b,
-	0x64, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4f,
-	0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62,
-	0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50,
-	0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x4f, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f,
-	0x62, 0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
-	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62,
-	0x12, 0x1b, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72,
-	0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
-	0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65,
-	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x13, 0x53,
+	0x6b, 0x22, 0x61, 0x0a, 0x12, 0x42, 0x61, 0x74, 0x63, 0x68, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x49, 0x44, 0x12, 0x2d, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x54, 0x6f, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x06, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x73, 0x22, 0x4b, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x6f, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x24, 0x0a, 0x0d, 0x70,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73,
+	0x73, 0x22, 0x48, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64,
+	0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x42, 0x0a, 0x11, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b,
+	0x12, 0x15, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x03, 0x65, 0x72, 0x72, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x72, 0x72, 0x22,
+	0xb9, 0x01, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72,
+	0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x12, 0x15, 0x0a, 0x03, 0x54, 0x54, 0x4c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x54, 0x54, 0x4c, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x01, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x25, 0x0a, 0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x52, 0x4c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x55, 0x52, 0x4c, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x54, 0x54, 0x4c, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
+	0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x52, 0x4c, 0x22, 0x2e, 0x0a, 0x16, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x22, 0x38, 0x0a, 0x0c, 0x57,
+	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49,
+	0x44, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x17, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x57,
+	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d,
+	0x0a, 0x15, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x22, 0x18, 0x0a,
+	0x16, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x42,
+	0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72,
+	0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x04, 0x6a, 0x6f,
+	0x62, 0x73, 0x22, 0xc1, 0x02, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x12, 0x35, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x67, 0x6e,
+	0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x15, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x03, 0x65, 0x72, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72,
+	0x6b, 0x64, 0x2e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x02,
+	0x52, 0x0b, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x22, 0x52, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x13, 0x0a, 0x0f, 0x57, 0x41,
+	0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x57, 0x49, 0x54, 0x4e, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52,
+	0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x52, 0x52, 0x4f, 0x52,
+	0x45, 0x44, 0x10, 0x04, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x72, 0x72, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x65, 0x0a, 0x0b, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x72, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x77, 0x69, 0x72, 0x65, 0x73, 0x22, 0x32, 0x0a,
+	0x1a, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76,
+	0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6a,
+	0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49,
+	0x44, 0x22, 0x85, 0x02, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x12, 0x43, 0x0a, 0x08, 0x66, 0x69,
+	0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x67,
+	0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x74, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x08, 0x0a, 0x04, 0x52, 0x31, 0x43, 0x53, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x4f,
+	0x56, 0x49, 0x4e, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x56, 0x45,
+	0x52, 0x49, 0x46, 0x59, 0x49, 0x4e, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x10, 0x02, 0x12, 0x0f, 0x0a,
+	0x0b, 0x53, 0x50, 0x41, 0x52, 0x53, 0x45, 0x5f, 0x52, 0x31, 0x43, 0x53, 0x10, 0x03, 0x12, 0x15,
+	0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x43, 0x48,
+	0x45, 0x4d, 0x45, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x57, 0x49, 0x54, 0x4e, 0x45, 0x53, 0x53,
+	0x5f, 0x53, 0x43, 0x48, 0x45, 0x4d, 0x41, 0x10, 0x05, 0x22, 0xe7, 0x02, 0x0a, 0x0b, 0x43, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72,
+	0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x62, 0x43, 0x6f, 0x6e,
+	0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d,
+	0x6e, 0x62, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x30, 0x0a,
+	0x13, 0x6e, 0x62, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x6e, 0x62, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12,
+	0x2c, 0x0a, 0x11, 0x6e, 0x62, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6e, 0x62, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x2c, 0x0a,
+	0x11, 0x6e, 0x62, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c,
+	0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6e, 0x62, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x66,
+	0x75, 0x6c, 0x6c, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73,
+	0x73, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x57,
+	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x11, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x53,
+	0x69, 0x7a, 0x65, 0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x73, 0x22, 0x35, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x22, 0x56, 0x0a, 0x14, 0x55, 0x6e,
+	0x6c, 0x6f, 0x61, 0x64, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x49, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0x5d, 0x0a, 0x13, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x44,
+	0x12, 0x28, 0x0a, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4d, 0x53, 0x4d, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x08, 0x4d, 0x53,
+	0x4d, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x22, 0xad, 0x01, 0x0a, 0x0d, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x45, 0x78,
+	0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69,
+	0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x49, 0x44, 0x12, 0x2b, 0x0a, 0x03, 0x6d, 0x73, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x19, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69,
+	0x45, 0x78, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x4d, 0x53, 0x4d, 0x52, 0x03, 0x6d, 0x73,
+	0x6d, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x73, 0x22, 0x37, 0x0a, 0x03, 0x4d,
+	0x53, 0x4d, 0x12, 0x08, 0x0a, 0x04, 0x47, 0x31, 0x5f, 0x41, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04,
+	0x47, 0x31, 0x5f, 0x42, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x47, 0x31, 0x5f, 0x5a, 0x10, 0x02,
+	0x12, 0x08, 0x0a, 0x04, 0x47, 0x31, 0x5f, 0x4b, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x47, 0x32,
+	0x5f, 0x42, 0x10, 0x04, 0x22, 0x26, 0x0a, 0x0e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x45, 0x78, 0x70,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x32, 0xc4, 0x04, 0x0a,
+	0x07, 0x47, 0x72, 0x6f, 0x74, 0x68, 0x31, 0x36, 0x12, 0x32, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x76,
+	0x65, 0x12, 0x14, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64,
+	0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x35, 0x0a, 0x06,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x15, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x44, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x12, 0x1a, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x1d, 0x2e, 0x67, 0x6e,
+	0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65,
+	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6e, 0x61,
+	0x72, 0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x14, 0x2e, 0x67, 0x6e,
+	0x61, 0x72, 0x6b, 0x64, 0x2e, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x1a, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76,
+	0x65, 0x4a, 0x6f, 0x62, 0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65,
+	0x4a, 0x6f, 0x62, 0x12, 0x1b, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53,
+	0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f,
+	0x76, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x22, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x53,
 	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a,
-	0x6f, 0x62, 0x12, 0x22, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
-	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01,
-	0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
-	0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x79, 0x73, 0x2f, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x2f, 0x67,
-	0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6e, 0x61, 0x72,
+	0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x30, 0x01, 0x32, 0xfc, 0x03, 0x0a, 0x05, 0x50, 0x6c, 0x6f, 0x6e, 0x6b, 0x12, 0x32, 0x0a,
+	0x05, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x12, 0x14, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
+	0x50, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67,
+	0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x35, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x15, 0x2e, 0x67, 0x6e,
+	0x61, 0x72, 0x6b, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61,
+	0x72, 0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72,
+	0x6b, 0x64, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f,
+	0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x14, 0x2e, 0x67, 0x6e, 0x61,
+	0x72, 0x6b, 0x64, 0x2e, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x1a, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28,
+	0x01, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65,
+	0x4a, 0x6f, 0x62, 0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a,
+	0x6f, 0x62, 0x12, 0x1b, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f,
+	0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a,
+	0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76,
+	0x65, 0x4a, 0x6f, 0x62, 0x12, 0x22, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f,
+	0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b,
+	0x64, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x30, 0x01, 0x32, 0xb3, 0x02, 0x0a, 0x08, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x12,
+	0x48, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x12, 0x1e, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x28, 0x01, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x67, 0x6e, 0x61, 0x72,
+	0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e,
+	0x47, 0x65, 0x74, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x43,
+	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x4c, 0x0a, 0x0d, 0x55, 0x6e,
+	0x6c, 0x6f, 0x61, 0x64, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x12, 0x1c, 0x2e, 0x67, 0x6e,
+	0x61, 0x72, 0x6b, 0x64, 0x2e, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x67, 0x6e, 0x61, 0x72,
+	0x6b, 0x64, 0x2e, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x8d, 0x01, 0x0a, 0x09, 0x4d, 0x53, 0x4d,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x43, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x73, 0x12, 0x19, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x45, 0x78, 0x70, 0x12, 0x15, 0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64,
+	0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x45, 0x78, 0x70, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x16,
+	0x2e, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x45, 0x78, 0x70,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x28, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x79, 0x73,
+	0x2f, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x2f, 0x67, 0x6e, 0x61, 0x72, 0x6b, 0x64, 0x2f, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -793,43 +2213,103 @@ func file_pb_gnarkd_proto_rawDescGZIP() []byte {
 	return file_pb_gnarkd_proto_rawDescData
 }
 
-var file_pb_gnarkd_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pb_gnarkd_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pb_gnarkd_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pb_gnarkd_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_pb_gnarkd_proto_goTypes = []interface{}{
-	(ProveJobResult_Status)(0),         // 0: gnarkd.ProveJobResult.Status
-	(*ProveRequest)(nil),               // 1: gnarkd.ProveRequest
-	(*ProveResult)(nil),                // 2: gnarkd.ProveResult
-	(*VerifyRequest)(nil),              // 3: gnarkd.VerifyRequest
-	(*VerifyResult)(nil),               // 4: gnarkd.VerifyResult
-	(*CreateProveJobRequest)(nil),      // 5: gnarkd.CreateProveJobRequest
-	(*CreateProveJobResponse)(nil),     // 6: gnarkd.CreateProveJobResponse
-	(*CancelProveJobRequest)(nil),      // 7: gnarkd.CancelProveJobRequest
-	(*CancelProveJobResponse)(nil),     // 8: gnarkd.CancelProveJobResponse
-	(*ListProveJobRequest)(nil),        // 9: gnarkd.ListProveJobRequest
-	(*ListProveJobResponse)(nil),       // 10: gnarkd.ListProveJobResponse
-	(*ProveJobResult)(nil),             // 11: gnarkd.ProveJobResult
-	(*SubscribeToProveJobRequest)(nil), // 12: gnarkd.SubscribeToProveJobRequest
+	(ProveJobResult_Status)(0),           // 0: gnarkd.ProveJobResult.Status
+	(RegisterCircuitRequest_FileType)(0), // 1: gnarkd.RegisterCircuitRequest.FileType
+	(MultiExpChunk_MSM)(0),               // 2: gnarkd.MultiExpChunk.MSM
+	(*ProveRequest)(nil),                 // 3: gnarkd.ProveRequest
+	(*ProveResult)(nil),                  // 4: gnarkd.ProveResult
+	(*VerifyRequest)(nil),                // 5: gnarkd.VerifyRequest
+	(*VerifyResult)(nil),                 // 6: gnarkd.VerifyResult
+	(*BatchVerifyRequest)(nil),           // 7: gnarkd.BatchVerifyRequest
+	(*ProofToVerify)(nil),                // 8: gnarkd.ProofToVerify
+	(*BatchVerifyResult)(nil),            // 9: gnarkd.BatchVerifyResult
+	(*ProofVerifyResult)(nil),            // 10: gnarkd.ProofVerifyResult
+	(*CreateProveJobRequest)(nil),        // 11: gnarkd.CreateProveJobRequest
+	(*CreateProveJobResponse)(nil),       // 12: gnarkd.CreateProveJobResponse
+	(*WitnessChunk)(nil),                 // 13: gnarkd.WitnessChunk
+	(*SubmitWitnessResponse)(nil),        // 14: gnarkd.SubmitWitnessResponse
+	(*CancelProveJobRequest)(nil),        // 15: gnarkd.CancelProveJobRequest
+	(*CancelProveJobResponse)(nil),       // 16: gnarkd.CancelProveJobResponse
+	(*ListProveJobRequest)(nil),          // 17: gnarkd.ListProveJobRequest
+	(*ListProveJobResponse)(nil),         // 18: gnarkd.ListProveJobResponse
+	(*ProveJobResult)(nil),               // 19: gnarkd.ProveJobResult
+	(*SolverError)(nil),                  // 20: gnarkd.SolverError
+	(*SubscribeToProveJobRequest)(nil),   // 21: gnarkd.SubscribeToProveJobRequest
+	(*RegisterCircuitRequest)(nil),       // 22: gnarkd.RegisterCircuitRequest
+	(*CircuitInfo)(nil),                  // 23: gnarkd.CircuitInfo
+	(*ListCircuitsRequest)(nil),          // 24: gnarkd.ListCircuitsRequest
+	(*ListCircuitsResponse)(nil),         // 25: gnarkd.ListCircuitsResponse
+	(*GetCircuitInfoRequest)(nil),        // 26: gnarkd.GetCircuitInfoRequest
+	(*UnloadCircuitRequest)(nil),         // 27: gnarkd.UnloadCircuitRequest
+	(*UnloadCircuitResponse)(nil),        // 28: gnarkd.UnloadCircuitResponse
+	(*ListChunksRequest)(nil),            // 29: gnarkd.ListChunksRequest
+	(*ListChunksResponse)(nil),           // 30: gnarkd.ListChunksResponse
+	(*ProvingKeyChunkInfo)(nil),          // 31: gnarkd.ProvingKeyChunkInfo
+	(*MSMRange)(nil),                     // 32: gnarkd.MSMRange
+	(*MultiExpChunk)(nil),                // 33: gnarkd.MultiExpChunk
+	(*MultiExpResult)(nil),               // 34: gnarkd.MultiExpResult
 }
 var file_pb_gnarkd_proto_depIdxs = []int32{
-	11, // 0: gnarkd.ListProveJobResponse.jobs:type_name -> gnarkd.ProveJobResult
-	0,  // 1: gnarkd.ProveJobResult.status:type_name -> gnarkd.ProveJobResult.Status
-	1,  // 2: gnarkd.Groth16.Prove:input_type -> gnarkd.ProveRequest
-	3,  // 3: gnarkd.Groth16.Verify:input_type -> gnarkd.VerifyRequest
-	5,  // 4: gnarkd.Groth16.CreateProveJob:input_type -> gnarkd.CreateProveJobRequest
-	7,  // 5: gnarkd.Groth16.CancelProveJob:input_type -> gnarkd.CancelProveJobRequest
-	9,  // 6: gnarkd.Groth16.ListProveJob:input_type -> gnarkd.ListProveJobRequest
-	12, // 7: gnarkd.Groth16.SubscribeToProveJob:input_type -> gnarkd.SubscribeToProveJobRequest
-	2,  // 8: gnarkd.Groth16.Prove:output_type -> gnarkd.ProveResult
-	4,  // 9: gnarkd.Groth16.Verify:output_type -> gnarkd.VerifyResult
-	6,  // 10: gnarkd.Groth16.CreateProveJob:output_type -> gnarkd.CreateProveJobResponse
-	8,  // 11: gnarkd.Groth16.CancelProveJob:output_type -> gnarkd.CancelProveJobResponse
-	10, // 12: gnarkd.Groth16.ListProveJob:output_type -> gnarkd.ListProveJobResponse
-	11, // 13: gnarkd.Groth16.SubscribeToProveJob:output_type -> gnarkd.ProveJobResult
-	8,  // [8:14] is the sub-list for method output_type
-	2,  // [2:8] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	8,  // 0: gnarkd.BatchVerifyRequest.proofs:type_name -> gnarkd.ProofToVerify
+	10, // 1: gnarkd.BatchVerifyResult.results:type_name -> gnarkd.ProofVerifyResult
+	19, // 2: gnarkd.ListProveJobResponse.jobs:type_name -> gnarkd.ProveJobResult
+	0,  // 3: gnarkd.ProveJobResult.status:type_name -> gnarkd.ProveJobResult.Status
+	20, // 4: gnarkd.ProveJobResult.solverError:type_name -> gnarkd.SolverError
+	1,  // 5: gnarkd.RegisterCircuitRequest.fileType:type_name -> gnarkd.RegisterCircuitRequest.FileType
+	23, // 6: gnarkd.ListCircuitsResponse.circuits:type_name -> gnarkd.CircuitInfo
+	31, // 7: gnarkd.ListChunksResponse.chunks:type_name -> gnarkd.ProvingKeyChunkInfo
+	32, // 8: gnarkd.ProvingKeyChunkInfo.ranges:type_name -> gnarkd.MSMRange
+	2,  // 9: gnarkd.MultiExpChunk.msm:type_name -> gnarkd.MultiExpChunk.MSM
+	3,  // 10: gnarkd.Groth16.Prove:input_type -> gnarkd.ProveRequest
+	5,  // 11: gnarkd.Groth16.Verify:input_type -> gnarkd.VerifyRequest
+	7,  // 12: gnarkd.Groth16.BatchVerify:input_type -> gnarkd.BatchVerifyRequest
+	11, // 13: gnarkd.Groth16.CreateProveJob:input_type -> gnarkd.CreateProveJobRequest
+	13, // 14: gnarkd.Groth16.SubmitWitness:input_type -> gnarkd.WitnessChunk
+	15, // 15: gnarkd.Groth16.CancelProveJob:input_type -> gnarkd.CancelProveJobRequest
+	17, // 16: gnarkd.Groth16.ListProveJob:input_type -> gnarkd.ListProveJobRequest
+	21, // 17: gnarkd.Groth16.SubscribeToProveJob:input_type -> gnarkd.SubscribeToProveJobRequest
+	3,  // 18: gnarkd.Plonk.Prove:input_type -> gnarkd.ProveRequest
+	5,  // 19: gnarkd.Plonk.Verify:input_type -> gnarkd.VerifyRequest
+	11, // 20: gnarkd.Plonk.CreateProveJob:input_type -> gnarkd.CreateProveJobRequest
+	13, // 21: gnarkd.Plonk.SubmitWitness:input_type -> gnarkd.WitnessChunk
+	15, // 22: gnarkd.Plonk.CancelProveJob:input_type -> gnarkd.CancelProveJobRequest
+	17, // 23: gnarkd.Plonk.ListProveJob:input_type -> gnarkd.ListProveJobRequest
+	21, // 24: gnarkd.Plonk.SubscribeToProveJob:input_type -> gnarkd.SubscribeToProveJobRequest
+	22, // 25: gnarkd.Circuits.RegisterCircuit:input_type -> gnarkd.RegisterCircuitRequest
+	24, // 26: gnarkd.Circuits.ListCircuits:input_type -> gnarkd.ListCircuitsRequest
+	26, // 27: gnarkd.Circuits.GetCircuitInfo:input_type -> gnarkd.GetCircuitInfoRequest
+	27, // 28: gnarkd.Circuits.UnloadCircuit:input_type -> gnarkd.UnloadCircuitRequest
+	29, // 29: gnarkd.MSMWorker.ListChunks:input_type -> gnarkd.ListChunksRequest
+	33, // 30: gnarkd.MSMWorker.MultiExp:input_type -> gnarkd.MultiExpChunk
+	4,  // 31: gnarkd.Groth16.Prove:output_type -> gnarkd.ProveResult
+	6,  // 32: gnarkd.Groth16.Verify:output_type -> gnarkd.VerifyResult
+	9,  // 33: gnarkd.Groth16.BatchVerify:output_type -> gnarkd.BatchVerifyResult
+	12, // 34: gnarkd.Groth16.CreateProveJob:output_type -> gnarkd.CreateProveJobResponse
+	14, // 35: gnarkd.Groth16.SubmitWitness:output_type -> gnarkd.SubmitWitnessResponse
+	16, // 36: gnarkd.Groth16.CancelProveJob:output_type -> gnarkd.CancelProveJobResponse
+	18, // 37: gnarkd.Groth16.ListProveJob:output_type -> gnarkd.ListProveJobResponse
+	19, // 38: gnarkd.Groth16.SubscribeToProveJob:output_type -> gnarkd.ProveJobResult
+	4,  // 39: gnarkd.Plonk.Prove:output_type -> gnarkd.ProveResult
+	6,  // 40: gnarkd.Plonk.Verify:output_type -> gnarkd.VerifyResult
+	12, // 41: gnarkd.Plonk.CreateProveJob:output_type -> gnarkd.CreateProveJobResponse
+	14, // 42: gnarkd.Plonk.SubmitWitness:output_type -> gnarkd.SubmitWitnessResponse
+	16, // 43: gnarkd.Plonk.CancelProveJob:output_type -> gnarkd.CancelProveJobResponse
+	18, // 44: gnarkd.Plonk.ListProveJob:output_type -> gnarkd.ListProveJobResponse
+	19, // 45: gnarkd.Plonk.SubscribeToProveJob:output_type -> gnarkd.ProveJobResult
+	23, // 46: gnarkd.Circuits.RegisterCircuit:output_type -> gnarkd.CircuitInfo
+	25, // 47: gnarkd.Circuits.ListCircuits:output_type -> gnarkd.ListCircuitsResponse
+	23, // 48: gnarkd.Circuits.GetCircuitInfo:output_type -> gnarkd.CircuitInfo
+	28, // 49: gnarkd.Circuits.UnloadCircuit:output_type -> gnarkd.UnloadCircuitResponse
+	30, // 50: gnarkd.MSMWorker.ListChunks:output_type -> gnarkd.ListChunksResponse
+	34, // 51: gnarkd.MSMWorker.MultiExp:output_type -> gnarkd.MultiExpResult
+	31, // [31:52] is the sub-list for method output_type
+	10, // [10:31] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_pb_gnarkd_proto_init() }
@@ -887,7 +2367,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateProveJobRequest); i {
+			switch v := v.(*BatchVerifyRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -899,7 +2379,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateProveJobResponse); i {
+			switch v := v.(*ProofToVerify); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -911,7 +2391,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelProveJobRequest); i {
+			switch v := v.(*BatchVerifyResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -923,7 +2403,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelProveJobResponse); i {
+			switch v := v.(*ProofVerifyResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -935,7 +2415,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListProveJobRequest); i {
+			switch v := v.(*CreateProveJobRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -947,7 +2427,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListProveJobResponse); i {
+			switch v := v.(*CreateProveJobResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -959,7 +2439,7 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProveJobResult); i {
+			switch v := v.(*WitnessChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -971,6 +2451,90 @@ func file_pb_gnarkd_proto_init() {
 			}
 		}
 		file_pb_gnarkd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitWitnessResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelProveJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelProveJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProveJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProveJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProveJobResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SolverError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SubscribeToProveJobRequest); i {
 			case 0:
 				return &v.state
@@ -982,18 +2546,175 @@ func file_pb_gnarkd_proto_init() {
 				return nil
 			}
 		}
+		file_pb_gnarkd_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterCircuitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CircuitInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCircuitsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCircuitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCircuitInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnloadCircuitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnloadCircuitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChunksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChunksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProvingKeyChunkInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSMRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiExpChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_gnarkd_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiExpResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_pb_gnarkd_proto_msgTypes[4].OneofWrappers = []interface{}{}
-	file_pb_gnarkd_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_pb_gnarkd_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_pb_gnarkd_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_pb_gnarkd_proto_msgTypes[16].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pb_gnarkd_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   12,
+			NumEnums:      3,
+			NumMessages:   32,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   4,
 		},
 		GoTypes:           file_pb_gnarkd_proto_goTypes,
 		DependencyIndexes: file_pb_gnarkd_proto_depIdxs,
@@ -26,8 +26,15 @@ type Groth16Client interface {
 	// Verify takes circuitID, proof and public witness as parameter
 	// this is a synchronous call
 	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResult, error)
+	// BatchVerify takes circuitID and (proof, public witness) pairs as parameter, and returns the result of each proof
+	// the pairing checks are batched, which is cheaper than calling Verify for each proof
+	// this is a synchronous call
+	BatchVerify(ctx context.Context, in *BatchVerifyRequest, opts ...grpc.CallOption) (*BatchVerifyResult, error)
 	// CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
 	CreateProveJob(ctx context.Context, in *CreateProveJobRequest, opts ...grpc.CallOption) (*CreateProveJobResponse, error)
+	// SubmitWitness streams the witness of a job created by CreateProveJob, the first chunk must set the jobID
+	// once the full witness is received, the job is QUEUED
+	SubmitWitness(ctx context.Context, opts ...grpc.CallOption) (Groth16_SubmitWitnessClient, error)
 	// CancelProveJob does what it says it does.
 	CancelProveJob(ctx context.Context, in *CancelProveJobRequest, opts ...grpc.CallOption) (*CancelProveJobResponse, error)
 	// ListProveJob does what it says it does.
@@ -64,6 +71,15 @@ func (c *groth16Client) Verify(ctx context.Context, in *VerifyRequest, opts ...g
 	return out, nil
 }
 
+func (c *groth16Client) BatchVerify(ctx context.Context, in *BatchVerifyRequest, opts ...grpc.CallOption) (*BatchVerifyResult, error) {
+	out := new(BatchVerifyResult)
+	err := c.cc.Invoke(ctx, "/gnarkd.Groth16/BatchVerify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *groth16Client) CreateProveJob(ctx context.Context, in *CreateProveJobRequest, opts ...grpc.CallOption) (*CreateProveJobResponse, error) {
 	out := new(CreateProveJobResponse)
 	err := c.cc.Invoke(ctx, "/gnarkd.Groth16/CreateProveJob", in, out, opts...)
@@ -73,6 +89,40 @@ func (c *groth16Client) CreateProveJob(ctx context.Context, in *CreateProveJobRe
 	return out, nil
 }
 
+func (c *groth16Client) SubmitWitness(ctx context.Context, opts ...grpc.CallOption) (Groth16_SubmitWitnessClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Groth16_ServiceDesc.Streams[0], "/gnarkd.Groth16/SubmitWitness", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &groth16SubmitWitnessClient{stream}
+	return x, nil
+}
+
+type Groth16_SubmitWitnessClient interface {
+	Send(*WitnessChunk) error
+	CloseAndRecv() (*SubmitWitnessResponse, error)
+	grpc.ClientStream
+}
+
+type groth16SubmitWitnessClient struct {
+	grpc.ClientStream
+}
+
+func (x *groth16SubmitWitnessClient) Send(m *WitnessChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *groth16SubmitWitnessClient) CloseAndRecv() (*SubmitWitnessResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SubmitWitnessResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *groth16Client) CancelProveJob(ctx context.Context, in *CancelProveJobRequest, opts ...grpc.CallOption) (*CancelProveJobResponse, error) {
 	out := new(CancelProveJobResponse)
 	err := c.cc.Invoke(ctx, "/gnarkd.Groth16/CancelProveJob", in, out, opts...)
@@ -92,7 +142,7 @@ func (c *groth16Client) ListProveJob(ctx context.Context, in *ListProveJobReques
 }
 
 func (c *groth16Client) SubscribeToProveJob(ctx context.Context, in *SubscribeToProveJobRequest, opts ...grpc.CallOption) (Groth16_SubscribeToProveJobClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Groth16_ServiceDesc.Streams[0], "/gnarkd.Groth16/SubscribeToProveJob", opts...)
+	stream, err := c.cc.NewStream(ctx, &Groth16_ServiceDesc.Streams[1], "/gnarkd.Groth16/SubscribeToProveJob", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -135,8 +185,15 @@ type Groth16Server interface {
 	// Verify takes circuitID, proof and public witness as parameter
 	// this is a synchronous call
 	Verify(context.Context, *VerifyRequest) (*VerifyResult, error)
+	// BatchVerify takes circuitID and (proof, public witness) pairs as parameter, and returns the result of each proof
+	// the pairing checks are batched, which is cheaper than calling Verify for each proof
+	// this is a synchronous call
+	BatchVerify(context.Context, *BatchVerifyRequest) (*BatchVerifyResult, error)
 	// CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
 	CreateProveJob(context.Context, *CreateProveJobRequest) (*CreateProveJobResponse, error)
+	// SubmitWitness streams the witness of a job created by CreateProveJob, the first chunk must set the jobID
+	// once the full witness is received, the job is QUEUED
+	SubmitWitness(Groth16_SubmitWitnessServer) error
 	// CancelProveJob does what it says it does.
 	CancelProveJob(context.Context, *CancelProveJobRequest) (*CancelProveJobResponse, error)
 	// ListProveJob does what it says it does.
@@ -158,9 +215,15 @@ func (UnimplementedGroth16Server) Prove(context.Context, *ProveRequest) (*ProveR
 func (UnimplementedGroth16Server) Verify(context.Context, *VerifyRequest) (*VerifyResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
 }
+func (UnimplementedGroth16Server) BatchVerify(context.Context, *BatchVerifyRequest) (*BatchVerifyResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchVerify not implemented")
+}
 func (UnimplementedGroth16Server) CreateProveJob(context.Context, *CreateProveJobRequest) (*CreateProveJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateProveJob not implemented")
 }
+func (UnimplementedGroth16Server) SubmitWitness(Groth16_SubmitWitnessServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubmitWitness not implemented")
+}
 func (UnimplementedGroth16Server) CancelProveJob(context.Context, *CancelProveJobRequest) (*CancelProveJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelProveJob not implemented")
 }
@@ -219,6 +282,24 @@ func _Groth16_Verify_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Groth16_BatchVerify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchVerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Groth16Server).BatchVerify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Groth16/BatchVerify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Groth16Server).BatchVerify(ctx, req.(*BatchVerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Groth16_CreateProveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateProveJobRequest)
 	if err := dec(in); err != nil {
@@ -237,6 +318,32 @@ func _Groth16_CreateProveJob_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Groth16_SubmitWitness_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(Groth16Server).SubmitWitness(&groth16SubmitWitnessServer{stream})
+}
+
+type Groth16_SubmitWitnessServer interface {
+	SendAndClose(*SubmitWitnessResponse) error
+	Recv() (*WitnessChunk, error)
+	grpc.ServerStream
+}
+
+type groth16SubmitWitnessServer struct {
+	grpc.ServerStream
+}
+
+func (x *groth16SubmitWitnessServer) SendAndClose(m *SubmitWitnessResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *groth16SubmitWitnessServer) Recv() (*WitnessChunk, error) {
+	m := new(WitnessChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Groth16_CancelProveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CancelProveJobRequest)
 	if err := dec(in); err != nil {
@@ -309,6 +416,10 @@ var Groth16_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Verify",
 			Handler:    _Groth16_Verify_Handler,
 		},
+		{
+			MethodName: "BatchVerify",
+			Handler:    _Groth16_BatchVerify_Handler,
+		},
 		{
 			MethodName: "CreateProveJob",
 			Handler:    _Groth16_CreateProveJob_Handler,
@@ -323,6 +434,11 @@ var Groth16_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitWitness",
+			Handler:       _Groth16_SubmitWitness_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "SubscribeToProveJob",
 			Handler:       _Groth16_SubscribeToProveJob_Handler,
@@ -331,3 +447,803 @@ var Groth16_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "pb/gnarkd.proto",
 }
+
+// PlonkClient is the client API for Plonk service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlonkClient interface {
+	// Prove takes circuitID and witness as parameter
+	// this is a synchronous call and bypasses the job queue
+	// it is meant to be used for small circuits, for larger circuits (proving time) and witnesses,
+	// use CreateProveJob instead
+	Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProveResult, error)
+	// Verify takes circuitID, proof and public witness as parameter
+	// this is a synchronous call
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResult, error)
+	// CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
+	CreateProveJob(ctx context.Context, in *CreateProveJobRequest, opts ...grpc.CallOption) (*CreateProveJobResponse, error)
+	// SubmitWitness streams the witness of a job created by CreateProveJob, the first chunk must set the jobID
+	// once the full witness is received, the job is QUEUED
+	SubmitWitness(ctx context.Context, opts ...grpc.CallOption) (Plonk_SubmitWitnessClient, error)
+	// CancelProveJob does what it says it does.
+	CancelProveJob(ctx context.Context, in *CancelProveJobRequest, opts ...grpc.CallOption) (*CancelProveJobResponse, error)
+	// ListProveJob does what it says it does.
+	ListProveJob(ctx context.Context, in *ListProveJobRequest, opts ...grpc.CallOption) (*ListProveJobResponse, error)
+	// SubscribeToProveJob enables a client to get job status changes from the server
+	// at connection start, server sends current job status
+	// when job is done (ok or errored), server closes connection
+	SubscribeToProveJob(ctx context.Context, in *SubscribeToProveJobRequest, opts ...grpc.CallOption) (Plonk_SubscribeToProveJobClient, error)
+}
+
+type plonkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlonkClient(cc grpc.ClientConnInterface) PlonkClient {
+	return &plonkClient{cc}
+}
+
+func (c *plonkClient) Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProveResult, error) {
+	out := new(ProveResult)
+	err := c.cc.Invoke(ctx, "/gnarkd.Plonk/Prove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plonkClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResult, error) {
+	out := new(VerifyResult)
+	err := c.cc.Invoke(ctx, "/gnarkd.Plonk/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plonkClient) CreateProveJob(ctx context.Context, in *CreateProveJobRequest, opts ...grpc.CallOption) (*CreateProveJobResponse, error) {
+	out := new(CreateProveJobResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.Plonk/CreateProveJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plonkClient) SubmitWitness(ctx context.Context, opts ...grpc.CallOption) (Plonk_SubmitWitnessClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Plonk_ServiceDesc.Streams[0], "/gnarkd.Plonk/SubmitWitness", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &plonkSubmitWitnessClient{stream}
+	return x, nil
+}
+
+type Plonk_SubmitWitnessClient interface {
+	Send(*WitnessChunk) error
+	CloseAndRecv() (*SubmitWitnessResponse, error)
+	grpc.ClientStream
+}
+
+type plonkSubmitWitnessClient struct {
+	grpc.ClientStream
+}
+
+func (x *plonkSubmitWitnessClient) Send(m *WitnessChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *plonkSubmitWitnessClient) CloseAndRecv() (*SubmitWitnessResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SubmitWitnessResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *plonkClient) CancelProveJob(ctx context.Context, in *CancelProveJobRequest, opts ...grpc.CallOption) (*CancelProveJobResponse, error) {
+	out := new(CancelProveJobResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.Plonk/CancelProveJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plonkClient) ListProveJob(ctx context.Context, in *ListProveJobRequest, opts ...grpc.CallOption) (*ListProveJobResponse, error) {
+	out := new(ListProveJobResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.Plonk/ListProveJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plonkClient) SubscribeToProveJob(ctx context.Context, in *SubscribeToProveJobRequest, opts ...grpc.CallOption) (Plonk_SubscribeToProveJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Plonk_ServiceDesc.Streams[1], "/gnarkd.Plonk/SubscribeToProveJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &plonkSubscribeToProveJobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Plonk_SubscribeToProveJobClient interface {
+	Recv() (*ProveJobResult, error)
+	grpc.ClientStream
+}
+
+type plonkSubscribeToProveJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *plonkSubscribeToProveJobClient) Recv() (*ProveJobResult, error) {
+	m := new(ProveJobResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PlonkServer is the server API for Plonk service.
+// All implementations must embed UnimplementedPlonkServer
+// for forward compatibility
+type PlonkServer interface {
+	// Prove takes circuitID and witness as parameter
+	// this is a synchronous call and bypasses the job queue
+	// it is meant to be used for small circuits, for larger circuits (proving time) and witnesses,
+	// use CreateProveJob instead
+	Prove(context.Context, *ProveRequest) (*ProveResult, error)
+	// Verify takes circuitID, proof and public witness as parameter
+	// this is a synchronous call
+	Verify(context.Context, *VerifyRequest) (*VerifyResult, error)
+	// CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
+	CreateProveJob(context.Context, *CreateProveJobRequest) (*CreateProveJobResponse, error)
+	// SubmitWitness streams the witness of a job created by CreateProveJob, the first chunk must set the jobID
+	// once the full witness is received, the job is QUEUED
+	SubmitWitness(Plonk_SubmitWitnessServer) error
+	// CancelProveJob does what it says it does.
+	CancelProveJob(context.Context, *CancelProveJobRequest) (*CancelProveJobResponse, error)
+	// ListProveJob does what it says it does.
+	ListProveJob(context.Context, *ListProveJobRequest) (*ListProveJobResponse, error)
+	// SubscribeToProveJob enables a client to get job status changes from the server
+	// at connection start, server sends current job status
+	// when job is done (ok or errored), server closes connection
+	SubscribeToProveJob(*SubscribeToProveJobRequest, Plonk_SubscribeToProveJobServer) error
+	mustEmbedUnimplementedPlonkServer()
+}
+
+// UnimplementedPlonkServer must be embedded to have forward compatible implementations.
+type UnimplementedPlonkServer struct {
+}
+
+func (UnimplementedPlonkServer) Prove(context.Context, *ProveRequest) (*ProveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prove not implemented")
+}
+func (UnimplementedPlonkServer) Verify(context.Context, *VerifyRequest) (*VerifyResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedPlonkServer) CreateProveJob(context.Context, *CreateProveJobRequest) (*CreateProveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProveJob not implemented")
+}
+func (UnimplementedPlonkServer) SubmitWitness(Plonk_SubmitWitnessServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubmitWitness not implemented")
+}
+func (UnimplementedPlonkServer) CancelProveJob(context.Context, *CancelProveJobRequest) (*CancelProveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelProveJob not implemented")
+}
+func (UnimplementedPlonkServer) ListProveJob(context.Context, *ListProveJobRequest) (*ListProveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProveJob not implemented")
+}
+func (UnimplementedPlonkServer) SubscribeToProveJob(*SubscribeToProveJobRequest, Plonk_SubscribeToProveJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToProveJob not implemented")
+}
+func (UnimplementedPlonkServer) mustEmbedUnimplementedPlonkServer() {}
+
+// UnsafePlonkServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlonkServer will
+// result in compilation errors.
+type UnsafePlonkServer interface {
+	mustEmbedUnimplementedPlonkServer()
+}
+
+func RegisterPlonkServer(s grpc.ServiceRegistrar, srv PlonkServer) {
+	s.RegisterService(&Plonk_ServiceDesc, srv)
+}
+
+func _Plonk_Prove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlonkServer).Prove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Plonk/Prove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlonkServer).Prove(ctx, req.(*ProveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plonk_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlonkServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Plonk/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlonkServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plonk_CreateProveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlonkServer).CreateProveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Plonk/CreateProveJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlonkServer).CreateProveJob(ctx, req.(*CreateProveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plonk_SubmitWitness_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PlonkServer).SubmitWitness(&plonkSubmitWitnessServer{stream})
+}
+
+type Plonk_SubmitWitnessServer interface {
+	SendAndClose(*SubmitWitnessResponse) error
+	Recv() (*WitnessChunk, error)
+	grpc.ServerStream
+}
+
+type plonkSubmitWitnessServer struct {
+	grpc.ServerStream
+}
+
+func (x *plonkSubmitWitnessServer) SendAndClose(m *SubmitWitnessResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *plonkSubmitWitnessServer) Recv() (*WitnessChunk, error) {
+	m := new(WitnessChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Plonk_CancelProveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelProveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlonkServer).CancelProveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Plonk/CancelProveJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlonkServer).CancelProveJob(ctx, req.(*CancelProveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plonk_ListProveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlonkServer).ListProveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Plonk/ListProveJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlonkServer).ListProveJob(ctx, req.(*ListProveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plonk_SubscribeToProveJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToProveJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlonkServer).SubscribeToProveJob(m, &plonkSubscribeToProveJobServer{stream})
+}
+
+type Plonk_SubscribeToProveJobServer interface {
+	Send(*ProveJobResult) error
+	grpc.ServerStream
+}
+
+type plonkSubscribeToProveJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *plonkSubscribeToProveJobServer) Send(m *ProveJobResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Plonk_ServiceDesc is the grpc.ServiceDesc for Plonk service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Plonk_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gnarkd.Plonk",
+	HandlerType: (*PlonkServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Prove",
+			Handler:    _Plonk_Prove_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _Plonk_Verify_Handler,
+		},
+		{
+			MethodName: "CreateProveJob",
+			Handler:    _Plonk_CreateProveJob_Handler,
+		},
+		{
+			MethodName: "CancelProveJob",
+			Handler:    _Plonk_CancelProveJob_Handler,
+		},
+		{
+			MethodName: "ListProveJob",
+			Handler:    _Plonk_ListProveJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitWitness",
+			Handler:       _Plonk_SubmitWitness_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubscribeToProveJob",
+			Handler:       _Plonk_SubscribeToProveJob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/gnarkd.proto",
+}
+
+// CircuitsClient is the client API for Circuits service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CircuitsClient interface {
+	// RegisterCircuit uploads the files of a circuit (.r1cs, .pk and .vk for Groth16, .spr and .pcs for PLONK,
+	// and optionally a .schema listing the names of the witness variables)
+	// the files are streamed in chunks, the first chunk must set the circuitID
+	// the circuit is stored in circuit_dir and loaded, it fails if a circuit with the same ID exists
+	RegisterCircuit(ctx context.Context, opts ...grpc.CallOption) (Circuits_RegisterCircuitClient, error)
+	// ListCircuits returns the loaded circuits
+	ListCircuits(ctx context.Context, in *ListCircuitsRequest, opts ...grpc.CallOption) (*ListCircuitsResponse, error)
+	// GetCircuitInfo describes a loaded circuit
+	GetCircuitInfo(ctx context.Context, in *GetCircuitInfoRequest, opts ...grpc.CallOption) (*CircuitInfo, error)
+	// UnloadCircuit removes a circuit from the server, and its files from circuit_dir if deleteFiles is set
+	// jobs of the circuit which are not running yet will fail
+	UnloadCircuit(ctx context.Context, in *UnloadCircuitRequest, opts ...grpc.CallOption) (*UnloadCircuitResponse, error)
+}
+
+type circuitsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCircuitsClient(cc grpc.ClientConnInterface) CircuitsClient {
+	return &circuitsClient{cc}
+}
+
+func (c *circuitsClient) RegisterCircuit(ctx context.Context, opts ...grpc.CallOption) (Circuits_RegisterCircuitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Circuits_ServiceDesc.Streams[0], "/gnarkd.Circuits/RegisterCircuit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &circuitsRegisterCircuitClient{stream}
+	return x, nil
+}
+
+type Circuits_RegisterCircuitClient interface {
+	Send(*RegisterCircuitRequest) error
+	CloseAndRecv() (*CircuitInfo, error)
+	grpc.ClientStream
+}
+
+type circuitsRegisterCircuitClient struct {
+	grpc.ClientStream
+}
+
+func (x *circuitsRegisterCircuitClient) Send(m *RegisterCircuitRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *circuitsRegisterCircuitClient) CloseAndRecv() (*CircuitInfo, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CircuitInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *circuitsClient) ListCircuits(ctx context.Context, in *ListCircuitsRequest, opts ...grpc.CallOption) (*ListCircuitsResponse, error) {
+	out := new(ListCircuitsResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.Circuits/ListCircuits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circuitsClient) GetCircuitInfo(ctx context.Context, in *GetCircuitInfoRequest, opts ...grpc.CallOption) (*CircuitInfo, error) {
+	out := new(CircuitInfo)
+	err := c.cc.Invoke(ctx, "/gnarkd.Circuits/GetCircuitInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *circuitsClient) UnloadCircuit(ctx context.Context, in *UnloadCircuitRequest, opts ...grpc.CallOption) (*UnloadCircuitResponse, error) {
+	out := new(UnloadCircuitResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.Circuits/UnloadCircuit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CircuitsServer is the server API for Circuits service.
+// All implementations must embed UnimplementedCircuitsServer
+// for forward compatibility
+type CircuitsServer interface {
+	// RegisterCircuit uploads the files of a circuit (.r1cs, .pk and .vk for Groth16, .spr and .pcs for PLONK,
+	// and optionally a .schema listing the names of the witness variables)
+	// the files are streamed in chunks, the first chunk must set the circuitID
+	// the circuit is stored in circuit_dir and loaded, it fails if a circuit with the same ID exists
+	RegisterCircuit(Circuits_RegisterCircuitServer) error
+	// ListCircuits returns the loaded circuits
+	ListCircuits(context.Context, *ListCircuitsRequest) (*ListCircuitsResponse, error)
+	// GetCircuitInfo describes a loaded circuit
+	GetCircuitInfo(context.Context, *GetCircuitInfoRequest) (*CircuitInfo, error)
+	// UnloadCircuit removes a circuit from the server, and its files from circuit_dir if deleteFiles is set
+	// jobs of the circuit which are not running yet will fail
+	UnloadCircuit(context.Context, *UnloadCircuitRequest) (*UnloadCircuitResponse, error)
+	mustEmbedUnimplementedCircuitsServer()
+}
+
+// UnimplementedCircuitsServer must be embedded to have forward compatible implementations.
+type UnimplementedCircuitsServer struct {
+}
+
+func (UnimplementedCircuitsServer) RegisterCircuit(Circuits_RegisterCircuitServer) error {
+	return status.Errorf(codes.Unimplemented, "method RegisterCircuit not implemented")
+}
+func (UnimplementedCircuitsServer) ListCircuits(context.Context, *ListCircuitsRequest) (*ListCircuitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCircuits not implemented")
+}
+func (UnimplementedCircuitsServer) GetCircuitInfo(context.Context, *GetCircuitInfoRequest) (*CircuitInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCircuitInfo not implemented")
+}
+func (UnimplementedCircuitsServer) UnloadCircuit(context.Context, *UnloadCircuitRequest) (*UnloadCircuitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnloadCircuit not implemented")
+}
+func (UnimplementedCircuitsServer) mustEmbedUnimplementedCircuitsServer() {}
+
+// UnsafeCircuitsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CircuitsServer will
+// result in compilation errors.
+type UnsafeCircuitsServer interface {
+	mustEmbedUnimplementedCircuitsServer()
+}
+
+func RegisterCircuitsServer(s grpc.ServiceRegistrar, srv CircuitsServer) {
+	s.RegisterService(&Circuits_ServiceDesc, srv)
+}
+
+func _Circuits_RegisterCircuit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CircuitsServer).RegisterCircuit(&circuitsRegisterCircuitServer{stream})
+}
+
+type Circuits_RegisterCircuitServer interface {
+	SendAndClose(*CircuitInfo) error
+	Recv() (*RegisterCircuitRequest, error)
+	grpc.ServerStream
+}
+
+type circuitsRegisterCircuitServer struct {
+	grpc.ServerStream
+}
+
+func (x *circuitsRegisterCircuitServer) SendAndClose(m *CircuitInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *circuitsRegisterCircuitServer) Recv() (*RegisterCircuitRequest, error) {
+	m := new(RegisterCircuitRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Circuits_ListCircuits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCircuitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CircuitsServer).ListCircuits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Circuits/ListCircuits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CircuitsServer).ListCircuits(ctx, req.(*ListCircuitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circuits_GetCircuitInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCircuitInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CircuitsServer).GetCircuitInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Circuits/GetCircuitInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CircuitsServer).GetCircuitInfo(ctx, req.(*GetCircuitInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Circuits_UnloadCircuit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnloadCircuitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CircuitsServer).UnloadCircuit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.Circuits/UnloadCircuit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CircuitsServer).UnloadCircuit(ctx, req.(*UnloadCircuitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Circuits_ServiceDesc is the grpc.ServiceDesc for Circuits service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Circuits_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gnarkd.Circuits",
+	HandlerType: (*CircuitsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCircuits",
+			Handler:    _Circuits_ListCircuits_Handler,
+		},
+		{
+			MethodName: "GetCircuitInfo",
+			Handler:    _Circuits_GetCircuitInfo_Handler,
+		},
+		{
+			MethodName: "UnloadCircuit",
+			Handler:    _Circuits_UnloadCircuit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RegisterCircuit",
+			Handler:       _Circuits_RegisterCircuit_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pb/gnarkd.proto",
+}
+
+// MSMWorkerClient is the client API for MSMWorker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MSMWorkerClient interface {
+	// ListChunks describes the proving key chunks held by the worker
+	ListChunks(ctx context.Context, in *ListChunksRequest, opts ...grpc.CallOption) (*ListChunksResponse, error)
+	// MultiExp streams the scalars of a multi-exponentiation over the points of a chunk, the first message must set
+	// the circuitID and msm; the scalars must match the range of points of the chunk
+	MultiExp(ctx context.Context, opts ...grpc.CallOption) (MSMWorker_MultiExpClient, error)
+}
+
+type mSMWorkerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMSMWorkerClient(cc grpc.ClientConnInterface) MSMWorkerClient {
+	return &mSMWorkerClient{cc}
+}
+
+func (c *mSMWorkerClient) ListChunks(ctx context.Context, in *ListChunksRequest, opts ...grpc.CallOption) (*ListChunksResponse, error) {
+	out := new(ListChunksResponse)
+	err := c.cc.Invoke(ctx, "/gnarkd.MSMWorker/ListChunks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mSMWorkerClient) MultiExp(ctx context.Context, opts ...grpc.CallOption) (MSMWorker_MultiExpClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MSMWorker_ServiceDesc.Streams[0], "/gnarkd.MSMWorker/MultiExp", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mSMWorkerMultiExpClient{stream}
+	return x, nil
+}
+
+type MSMWorker_MultiExpClient interface {
+	Send(*MultiExpChunk) error
+	CloseAndRecv() (*MultiExpResult, error)
+	grpc.ClientStream
+}
+
+type mSMWorkerMultiExpClient struct {
+	grpc.ClientStream
+}
+
+func (x *mSMWorkerMultiExpClient) Send(m *MultiExpChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mSMWorkerMultiExpClient) CloseAndRecv() (*MultiExpResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(MultiExpResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MSMWorkerServer is the server API for MSMWorker service.
+// All implementations must embed UnimplementedMSMWorkerServer
+// for forward compatibility
+type MSMWorkerServer interface {
+	// ListChunks describes the proving key chunks held by the worker
+	ListChunks(context.Context, *ListChunksRequest) (*ListChunksResponse, error)
+	// MultiExp streams the scalars of a multi-exponentiation over the points of a chunk, the first message must set
+	// the circuitID and msm; the scalars must match the range of points of the chunk
+	MultiExp(MSMWorker_MultiExpServer) error
+	mustEmbedUnimplementedMSMWorkerServer()
+}
+
+// UnimplementedMSMWorkerServer must be embedded to have forward compatible implementations.
+type UnimplementedMSMWorkerServer struct {
+}
+
+func (UnimplementedMSMWorkerServer) ListChunks(context.Context, *ListChunksRequest) (*ListChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChunks not implemented")
+}
+func (UnimplementedMSMWorkerServer) MultiExp(MSMWorker_MultiExpServer) error {
+	return status.Errorf(codes.Unimplemented, "method MultiExp not implemented")
+}
+func (UnimplementedMSMWorkerServer) mustEmbedUnimplementedMSMWorkerServer() {}
+
+// UnsafeMSMWorkerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MSMWorkerServer will
+// result in compilation errors.
+type UnsafeMSMWorkerServer interface {
+	mustEmbedUnimplementedMSMWorkerServer()
+}
+
+func RegisterMSMWorkerServer(s grpc.ServiceRegistrar, srv MSMWorkerServer) {
+	s.RegisterService(&MSMWorker_ServiceDesc, srv)
+}
+
+func _MSMWorker_ListChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MSMWorkerServer).ListChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gnarkd.MSMWorker/ListChunks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MSMWorkerServer).ListChunks(ctx, req.(*ListChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MSMWorker_MultiExp_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MSMWorkerServer).MultiExp(&mSMWorkerMultiExpServer{stream})
+}
+
+type MSMWorker_MultiExpServer interface {
+	SendAndClose(*MultiExpResult) error
+	Recv() (*MultiExpChunk, error)
+	grpc.ServerStream
+}
+
+type mSMWorkerMultiExpServer struct {
+	grpc.ServerStream
+}
+
+func (x *mSMWorkerMultiExpServer) SendAndClose(m *MultiExpResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mSMWorkerMultiExpServer) Recv() (*MultiExpChunk, error) {
+	m := new(MultiExpChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MSMWorker_ServiceDesc is the grpc.ServiceDesc for MSMWorker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MSMWorker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gnarkd.MSMWorker",
+	HandlerType: (*MSMWorkerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListChunks",
+			Handler:    _MSMWorker_ListChunks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MultiExp",
+			Handler:       _MSMWorker_MultiExp_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pb/gnarkd.proto",
+}
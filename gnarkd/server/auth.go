@@ -0,0 +1,237 @@
+package server
+
+import (
+	context "context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AuthConfig maps the identities of the clients to the circuits and RPCs they may use
+//
+// a client is identified by the common name of its (verified) TLS client certificate,
+// or by the bearer token in the "authorization" metadata of its calls
+type AuthConfig struct {
+	// Tokens maps bearer tokens to identities
+	Tokens map[string]string `json:"tokens"`
+
+	// Identities maps identities to their permissions, unknown identities can't call any RPC
+	Identities map[string]Permissions `json:"identities"`
+}
+
+// Permissions of an identity
+type Permissions struct {
+	// Circuits the identity may use (circuitIDs), "*" for all the circuits
+	Circuits []string `json:"circuits"`
+
+	// RPCs the identity may call (method names, e.g. "Prove" or "ListProveJob"), "*" for all the RPCs
+	RPCs []string `json:"rpcs"`
+
+	// Limits of the proofs of the identity, enforced if the Server shares the Quotas of the Authorizer
+	Limits Limits `json:"limits"`
+}
+
+// allows returns true if s contains value or "*"
+func allows(s []string, value string) bool {
+	for _, v := range s {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadAuthConfig reads a json encoded AuthConfig
+func LoadAuthConfig(path string) (AuthConfig, error) {
+	var config AuthConfig
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid auth config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Authorizer authenticates the clients of the gRPC services and enforces their permissions
+//
+// the RPCs and the call rates are checked by the interceptors, and the circuits by inspecting the requests with a circuitID.
+// Prove jobs are only visible to the identity which created them.
+type Authorizer struct {
+	config AuthConfig
+	quotas *Quotas
+}
+
+// NewAuthorizer returns an Authorizer enforcing config
+func NewAuthorizer(config AuthConfig) (*Authorizer, error) {
+	for token, identity := range config.Tokens {
+		if token == "" {
+			return nil, fmt.Errorf("empty token for identity %s", identity)
+		}
+		if _, ok := config.Identities[identity]; !ok {
+			return nil, fmt.Errorf("token of unknown identity %s", identity)
+		}
+	}
+	return &Authorizer{config: config, quotas: newQuotas(config)}, nil
+}
+
+// UnaryInterceptor returns a grpc.UnaryServerInterceptor authorizing the unary calls
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.Authorize(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Authorize authenticates the client of ctx and checks it may call fullMethod (e.g. "/gnarkd.Groth16/Prove") with req
+// it returns the context the Server expects in its handlers, for servers calling the handlers directly
+func (a *Authorizer) Authorize(ctx context.Context, fullMethod string, req interface{}) (context.Context, error) {
+	identity, err := a.authorize(ctx, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkCircuit(identity, req); err != nil {
+		return nil, err
+	}
+	if err := a.quotas.checkRate(identity, path.Base(fullMethod)); err != nil {
+		return nil, err
+	}
+	return a.withIdentity(ctx, identity), nil
+}
+
+// Quotas returns the Quotas tracking the Limits of the identities, to be shared with the Server (see WithQuotas)
+func (a *Authorizer) Quotas() *Quotas {
+	return a.quotas
+}
+
+// StreamInterceptor returns a grpc.StreamServerInterceptor authorizing the streaming calls
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		identity, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if err := a.quotas.checkRate(identity, path.Base(info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{
+			ServerStream: ss,
+			ctx:          a.withIdentity(ss.Context(), identity),
+			identity:     identity,
+			a:            a,
+		})
+	}
+}
+
+// authorize authenticates the client and checks it may call the method
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) (string, error) {
+	identity := a.identity(ctx)
+	if identity == "" {
+		return "", status.Errorf(codes.Unauthenticated, "missing or invalid credentials")
+	}
+	permissions, ok := a.config.Identities[identity]
+	if !ok {
+		return "", status.Errorf(codes.PermissionDenied, "unknown identity %s", identity)
+	}
+	method := path.Base(fullMethod)
+	if !allows(permissions.RPCs, method) {
+		return "", status.Errorf(codes.PermissionDenied, "%s can't call %s", identity, method)
+	}
+	return identity, nil
+}
+
+// checkCircuit checks the identity may use the circuit of the request, if it has one
+func (a *Authorizer) checkCircuit(identity string, req interface{}) error {
+	r, ok := req.(interface{ GetCircuitID() string })
+	if !ok || r.GetCircuitID() == "" {
+		return nil
+	}
+	if !allows(a.config.Identities[identity].Circuits, r.GetCircuitID()) {
+		return status.Errorf(codes.PermissionDenied, "%s can't use circuit %s", identity, r.GetCircuitID())
+	}
+	return nil
+}
+
+// identity returns the identity of the client, or "" if it isn't authenticated
+// the verified client certificate has precedence over the bearer token
+func (a *Authorizer) identity(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if chains := tlsInfo.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+				return chains[0][0].Subject.CommonName
+			}
+		}
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, auth := range md.Get("authorization") {
+		if !strings.HasPrefix(auth, "Bearer ") {
+			continue
+		}
+		token := []byte(strings.TrimPrefix(auth, "Bearer "))
+		for t, identity := range a.config.Tokens {
+			if subtle.ConstantTimeCompare([]byte(t), token) == 1 {
+				return identity
+			}
+		}
+	}
+	return ""
+}
+
+// authorizedStream checks the circuits of the messages received on a stream
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	identity string
+	a        *Authorizer
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.a.checkCircuit(s.identity, m)
+}
+
+type identityKey struct{}
+
+type permissionsKey struct{}
+
+// withIdentity returns the context of a call of the identity, see identityFromContext and circuitAllowed
+func (a *Authorizer) withIdentity(ctx context.Context, identity string) context.Context {
+	ctx = context.WithValue(ctx, identityKey{}, identity)
+	return context.WithValue(ctx, permissionsKey{}, a.config.Identities[identity])
+}
+
+// identityFromContext returns the identity set by the Authorizer, or "" if authorization is disabled
+func identityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+	return identity
+}
+
+// circuitAllowed returns true if the identity set by the Authorizer may use the circuit, or if authorization is disabled
+// it is used by the RPCs returning circuits without a circuitID in their request, e.g. ListCircuits
+func circuitAllowed(ctx context.Context, circuitID string) bool {
+	permissions, ok := ctx.Value(permissionsKey{}).(Permissions)
+	return !ok || allows(permissions.Circuits, circuitID)
+}
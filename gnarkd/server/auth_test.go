@@ -0,0 +1,125 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"net"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestAuthorization(t *testing.T) {
+	assert := require.New(t)
+
+	authorizer, err := NewAuthorizer(AuthConfig{
+		Tokens: map[string]string{
+			"alice-token": "alice",
+			"bob-token":   "bob",
+		},
+		Identities: map[string]Permissions{
+			"alice": {Circuits: []string{"bn254/cubic"}, RPCs: []string{"Prove", "CreateProveJob", "SubmitWitness", "ListProveJob", "CancelProveJob", "ListCircuits"}},
+			"bob":   {Circuits: []string{"*"}, RPCs: []string{"*"}},
+		},
+	})
+	assert.NoError(err)
+
+	_, err = NewAuthorizer(AuthConfig{Tokens: map[string]string{"token": "unknown"}})
+	assert.Error(err, "token of an unknown identity should be rejected")
+
+	// gRPC server with authorization, sharing the circuits and jobs of the test server
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(authorizer.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(authorizer.StreamInterceptor()),
+	)
+	pb.RegisterGroth16Server(s, gnarkdServer)
+	pb.RegisterPlonkServer(s, gnarkdServer.Plonk())
+	pb.RegisterCircuitsServer(s, gnarkdServer.Circuits())
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+	client := pb.NewGroth16Client(conn)
+
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	alice, bob := withToken("alice-token"), withToken("bob-token")
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	proveRequest := &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()}
+
+	// authentication
+	_, err = client.Prove(context.Background(), proveRequest)
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+	_, err = client.Prove(withToken("eve-token"), proveRequest)
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	// RPCs and circuits
+	_, err = client.Prove(alice, proveRequest)
+	assert.NoError(err)
+	_, err = client.Verify(alice, &pb.VerifyRequest{CircuitID: "bn254/cubic"})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = pb.NewPlonkClient(conn).Prove(alice, &pb.ProveRequest{CircuitID: "bn254/cubic_plonk"})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = pb.NewPlonkClient(conn).CreateProveJob(alice, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic_plonk"})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+
+	// the circuits are listed if they may be used
+	circuits, err := pb.NewCircuitsClient(conn).ListCircuits(alice, &pb.ListCircuitsRequest{})
+	assert.NoError(err)
+	assert.Len(circuits.Circuits, 1)
+	assert.Equal("bn254/cubic", circuits.Circuits[0].CircuitID)
+	circuits, err = pb.NewCircuitsClient(conn).ListCircuits(bob, &pb.ListCircuitsRequest{})
+	assert.NoError(err)
+	assert.Greater(len(circuits.Circuits), 1)
+
+	// jobs are visible to their owner only
+	r, err := client.CreateProveJob(alice, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+
+	listed := func(ctx context.Context) bool {
+		list, err := client.ListProveJob(ctx, &pb.ListProveJobRequest{})
+		assert.NoError(err)
+		for _, j := range list.Jobs {
+			if j.JobID == r.JobID {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(listed(alice))
+	assert.False(listed(bob))
+
+	_, err = client.CancelProveJob(bob, &pb.CancelProveJobRequest{JobID: r.JobID})
+	assert.Equal(codes.NotFound, status.Code(err))
+	wStream, err := client.SubmitWitness(bob)
+	assert.NoError(err)
+	err = sendWitness(wStream, r.JobID, bWitness.Bytes())
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	_, err = client.CancelProveJob(alice, &pb.CancelProveJobRequest{JobID: r.JobID})
+	assert.NoError(err)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	callbackAttempts = 5                // attempts to deliver a job result before giving up
+	callbackBackoff  = time.Second      // default delay before the first retry, doubled at each retry
+	callbackTimeout  = 30 * time.Second // timeout of an attempt
+
+	// SignatureHeader is set on the callback requests, if the Server has a callback secret (see WithCallbackSecret)
+	// its value is "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp, a dot and the request body,
+	// see SignCallback
+	SignatureHeader = "X-Gnarkd-Signature"
+
+	// TimestampHeader is set on the signed callback requests, its value is the Unix time (in seconds) of the attempt
+	// the signature covers it: receivers should refuse the requests whose timestamp is too old (e.g. 5 minutes)
+	// and the signatures already seen within this window, so that a captured request can't be replayed
+	TimestampHeader = "X-Gnarkd-Timestamp"
+)
+
+// SignCallback returns the SignatureHeader of a callback request with the given TimestampHeader and body
+// receivers compare it to the received signature with hmac.Equal
+func SignCallback(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WithCallbackSecret sets the key signing the job results POSTed to the callback URLs (see SignatureHeader)
+// default is no signature
+func WithCallbackSecret(secret []byte) Option {
+	return func(s *Server) {
+		s.callbackSecret = secret
+	}
+}
+
+// WithCallbacks enables the job callbacks (see CreateProveJobRequest.callbackURL), to the given hosts only
+//
+// an http(s) callback URL must name one of hosts, either as host (any port) or as host:port; a unix callback URL
+// must name a socket in socketDir (not in its sub-directories). Other URLs are refused with InvalidArgument.
+// default is no callbacks: a job with a callback URL is refused
+func WithCallbacks(hosts []string, socketDir string) Option {
+	return func(s *Server) {
+		s.callbackHosts = hosts
+		if socketDir != "" {
+			s.callbackSocketDir = filepath.Clean(socketDir)
+		}
+	}
+}
+
+// parseCallbackURL checks the callback URL of a CreateProveJobRequest against the allowed hosts and socket directory
+func (s *Server) parseCallbackURL(callbackURL string) (*url.URL, error) {
+	if len(s.callbackHosts) == 0 && s.callbackSocketDir == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "job callbacks are disabled")
+	}
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL: %s", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL %s: missing host", callbackURL)
+		}
+		if !allows(s.callbackHosts, u.Host) && !allows(s.callbackHosts, u.Hostname()) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL %s: host %s is not allowed", callbackURL, u.Host)
+		}
+	case "unix":
+		if u.Path == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL %s: missing socket path", callbackURL)
+		}
+		if s.callbackSocketDir == "" || filepath.Dir(filepath.Clean(u.Path)) != s.callbackSocketDir {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL %s: socket is not in the callback socket directory", callbackURL)
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid callback URL %s: scheme must be http, https or unix", callbackURL)
+	}
+	return u, nil
+}
+
+// notifyFinished releases the quota of a finished job, and POSTs its result to its callback URL if it has one
+// the result is sent asynchronously, with retries; it isn't sent once the Server is drained, see Shutdown.
+// must be called under lock
+func (s *Server) notifyFinished(job *proveJob) {
+	s.quotas.release(job.owner)
+	if job.callbackURL == "" {
+		return
+	}
+	s.drainingLock.RLock()
+	defer s.drainingLock.RUnlock()
+	if s.callbacksClosed {
+		s.log.Warnw("server shut down, job result not delivered", "jobID", job.id)
+		return
+	}
+	s.callbacks.Add(1)
+	go s.sendCallback(job.callbackURL, job.result())
+}
+
+// sendCallback POSTs result to callbackURL until it succeeds (2xx response), the attempts are exhausted
+// or the server stops
+func (s *Server) sendCallback(callbackURL string, result *pb.ProveJobResult) {
+	defer s.callbacks.Done()
+	body, err := protojson.Marshal(result)
+	if err != nil {
+		s.log.Errorw("couldn't encode job result", "jobID", result.JobID, "err", err)
+		return
+	}
+	// the allowed hosts may have changed since the job was created (restored jobs)
+	u, err := s.parseCallbackURL(callbackURL)
+	if err != nil {
+		s.log.Errorw("invalid callback URL", "jobID", result.JobID, "err", err)
+		return
+	}
+	client, target := s.callbackClient(u)
+
+	backoff := s.callbackBackoff
+	for attempt := 1; ; attempt++ {
+		err = s.postCallback(client, target, body)
+		if err == nil {
+			s.log.Infow("job result delivered", "jobID", result.JobID, "attempt", attempt)
+			return
+		}
+		if attempt == callbackAttempts {
+			s.log.Errorw("couldn't deliver job result, giving up", "jobID", result.JobID, "attempts", attempt, "err", err)
+			return
+		}
+		s.log.Warnw("couldn't deliver job result, retrying", "jobID", result.JobID, "attempt", attempt, "err", err)
+
+		select {
+		case <-s.ctx.Done():
+			s.log.Warnw("server stopping, job result not delivered", "jobID", result.JobID)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// callbackClient returns the client and the URL to POST to u
+// callbacks to a unix socket are sent to http://unix/ over the socket; redirects aren't followed, they could lead
+// to a host which isn't allowed
+func (s *Server) callbackClient(u *url.URL) (*http.Client, string) {
+	if u.Scheme != "unix" {
+		return &http.Client{Timeout: callbackTimeout, CheckRedirect: refuseRedirect}, u.String()
+	}
+	socket := filepath.Clean(u.Path)
+	var dialer net.Dialer
+	return &http.Client{
+		Timeout:       callbackTimeout,
+		CheckRedirect: refuseRedirect,
+		Transport: &http.Transport{
+			DisableKeepAlives: true, // the transport isn't reused after the callback
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}, "http://unix/"
+}
+
+func refuseRedirect(req *http.Request, via []*http.Request) error {
+	return fmt.Errorf("callback redirected to %s", req.URL.Redacted())
+}
+
+func (s *Server) postCallback(client *http.Client, target string, body []byte) error {
+	ctx, cancel := context.WithTimeout(s.ctx, callbackTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.callbackSecret != nil {
+		// each attempt is signed with its own timestamp
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, SignCallback(s.callbackSecret, timestamp, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
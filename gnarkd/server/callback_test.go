@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type callbackRequest struct {
+	body      []byte
+	timestamp string
+	signature string
+}
+
+func TestJobCallback(t *testing.T) {
+	assert := require.New(t)
+
+	secret := []byte("callback secret")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	socketDir := t.TempDir()
+	s, err := NewServer(ctx, log, "../circuits", WithCallbackSecret(secret), WithCallbacks([]string{"127.0.0.1"}, socketDir))
+	assert.NoError(err)
+	s.callbackBackoff = 10 * time.Millisecond
+
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGroth16Server(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+	client := pb.NewGroth16Client(conn)
+
+	// callback endpoints: http, failing on the first attempt, and unix socket
+	received := make(chan callbackRequest, 10)
+	handler := func(fail bool) http.HandlerFunc {
+		attempts := 0
+		return func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if fail && attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			received <- callbackRequest{body: body, timestamp: r.Header.Get(TimestampHeader), signature: r.Header.Get(SignatureHeader)}
+		}
+	}
+	httpServer := httptest.NewServer(handler(true))
+	defer httpServer.Close()
+
+	socket := filepath.Join(socketDir, "callback.sock")
+	unixLis, err := net.Listen("unix", socket)
+	assert.NoError(err)
+	go http.Serve(unixLis, handler(false))
+	defer unixLis.Close()
+
+	nextResult := func() *pb.ProveJobResult {
+		select {
+		case r := <-received:
+			// the signature covers the timestamp and the body
+			timestamp, err := strconv.ParseInt(r.timestamp, 10, 64)
+			assert.NoError(err)
+			assert.WithinDuration(time.Now(), time.Unix(timestamp, 0), time.Minute)
+			mac := hmac.New(sha256.New, secret)
+			mac.Write([]byte(r.timestamp + "."))
+			mac.Write(r.body)
+			assert.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), r.signature)
+			assert.Equal(r.signature, SignCallback(secret, r.timestamp, r.body))
+			assert.NotEqual(r.signature, SignCallback(secret, strconv.FormatInt(timestamp+1, 10), r.body))
+			var result pb.ProveJobResult
+			assert.NoError(protojson.Unmarshal(r.body, &result))
+			return &result
+		case <-time.After(10 * time.Second):
+			assert.FailNow("callback not received")
+			return nil
+		}
+	}
+
+	// invalid callback URLs
+	for _, u := range []string{
+		"ftp://host/path", "http://", "unix://", "::",
+		// not allowed
+		"http://localhost/jobs", "https://10.0.0.1:8080/jobs", "unix:///var/run/docker.sock",
+		"unix://" + socketDir + "/../callback.sock", "unix://" + socketDir + "/sub/callback.sock",
+	} {
+		_, err = client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic", CallbackURL: &u})
+		assert.Equal(codes.InvalidArgument, status.Code(err), u)
+	}
+
+	// completed job, delivered on retry
+	callbackURL := httpServer.URL + "/jobs"
+	r, err := client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic", CallbackURL: &callbackURL})
+	assert.NoError(err)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	stream, err := client.SubmitWitness(ctx)
+	assert.NoError(err)
+	assert.NoError(sendWitness(stream, r.JobID, bWitness.Bytes()))
+
+	result := nextResult()
+	assert.Equal(r.JobID, result.JobID)
+	assert.Equal(pb.ProveJobResult_COMPLETED, result.Status)
+	assert.NotEmpty(result.Proof)
+
+	// cancelled job, unix socket
+	callbackURL = "unix://" + socket
+	r, err = client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic", CallbackURL: &callbackURL})
+	assert.NoError(err)
+	_, err = client.CancelProveJob(ctx, &pb.CancelProveJobRequest{JobID: r.JobID})
+	assert.NoError(err)
+
+	result = nextResult()
+	assert.Equal(r.JobID, result.JobID)
+	assert.Equal(pb.ProveJobResult_ERRORED, result.Status)
+	assert.Equal(errJobCancelled.Error(), result.GetErr())
+}
+
+func TestJobCallbackDisabled(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+
+	_, err = s.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic", CallbackURL: proto.String("http://127.0.0.1/jobs")})
+	assert.Equal(codes.InvalidArgument, status.Code(err), "callbacks are disabled without WithCallbacks")
+}
@@ -1,20 +1,236 @@
 package server
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/polynomial"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
 )
 
 const (
 	pkExt   = ".pk"
 	vkExt   = ".vk"
 	r1csExt = ".r1cs"
+	sprExt  = ".spr" // sparse R1CS, for PLONK
+	pcsExt  = ".pcs" // polynomial commitment scheme, for PLONK
+
+	schemaExt = ".schema" // optional witness schema, see manifest.WitnessSchema
+
+	// pkMemoryFactor estimates the memory of a proving key from the size of its file:
+	// the points are stored compressed, and take twice their size in memory
+	pkMemoryFactor = 2
 )
 
+// circuit holds the metadata of a loaded circuit
+// the proving keys and constraint systems are read on demand, see provingData and circuitCache
 type circuit struct {
-	pk                groth16.ProvingKey
-	vk                groth16.VerifyingKey
-	r1cs              frontend.CompiledConstraintSystem
+	backendID backend.ID
+	curveID   ecc.ID
+	dir       string // directory of the circuit files
+
+	// names of the files of the circuit
+	pkFile, r1csFile, vkFile string // groth16
+	sprFile, pcsFile         string // plonk
+	schemaFile               string // optional
+
+	// size and SHA-256 of the files, from the manifest; nil if the circuit has no manifest
+	// the files are checked as they are read, see readFile
+	files map[string]manifest.File
+
+	vk     groth16.VerifyingKey    // groth16, small enough to stay in memory
+	schema *manifest.WitnessSchema // nil if the circuit has no witness schema
+
+	nbConstraints       int
+	nbInternalVariables int
+	nbSecretVariables   int
+	nbPublicVariables   int
+
 	fullWitnessSize   int
 	publicWitnessSize int
+
+	estimatedMemory int64 // estimated memory needed to prove a job, see WithMemoryLimit
+	provingDataSize int64 // estimated memory of the provingData, see WithCircuitCacheSize
+	csSize          int64 // estimated memory of the constraint system alone, see Server.constraintSystem
+}
+
+// provingData are the large objects of a circuit, needed to prove (and to verify PLONK proofs)
+type provingData struct {
+	// groth16
+	pk   groth16.ProvingKey
+	r1cs frontend.CompiledConstraintSystem
+
+	// plonk
+	spr    frontend.CompiledConstraintSystem
+	scheme polynomial.CommitmentScheme
+}
+
+// cs returns the constraint system of the circuit
+func (data *provingData) cs() frontend.CompiledConstraintSystem {
+	if data.spr != nil {
+		return data.spr
+	}
+	return data.r1cs
+}
+
+// readSizes sets the number of constraints and variables of the circuit, and the size of its witnesses
+// they are read from the manifest written by gnarkd/build; the constraint system is only read (and discarded)
+// if the circuit has no manifest
+func (c *circuit) readSizes() error {
+	m, err := manifest.Read(c.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if m != nil {
+		if err := c.checkManifest(m); err != nil {
+			return err
+		}
+		c.files = m.Files
+		c.setSizes(m.NbConstraints, m.NbInternalVariables, m.NbSecretVariables, m.NbPublicVariables)
+		return nil
+	}
+	cs := c.newCS()
+	if err := c.loadFile(cs, c.csFile()); err != nil {
+		return err
+	}
+	nbInternalVariables, nbSecretVariables, nbPublicVariables := cs.GetNbVariables()
+	c.setSizes(cs.GetNbConstraints(), nbInternalVariables, nbSecretVariables, nbPublicVariables)
+	return nil
+}
+
+// checkManifest checks that the manifest describes the circuit: curve, backend, and size of the files
+// the SHA-256 of the files is checked when they are read (see readFile), so that the large proving keys,
+// read on demand, aren't read at startup; the constraint system is also checked once read, see checkSizes
+func (c *circuit) checkManifest(m *manifest.Manifest) error {
+	backendName := "groth16"
+	if c.backendID == backend.PLONK {
+		backendName = "plonk"
+	}
+	if m.Curve != c.curveID.String() || m.Backend != backendName {
+		return fmt.Errorf("%s: the manifest describes a %s %s circuit", c.dir, m.Curve, m.Backend)
+	}
+	return m.CheckSizes(c.dir)
+}
+
+// setSizes sets the number of constraints and variables of the circuit, and the size of its witnesses
+func (c *circuit) setSizes(nbConstraints, nbInternalVariables, nbSecretVariables, nbPublicVariables int) {
+	c.nbConstraints = nbConstraints
+	c.nbInternalVariables, c.nbSecretVariables, c.nbPublicVariables = nbInternalVariables, nbSecretVariables, nbPublicVariables
+
+	// the R1CS public variables include the ONE_WIRE, the sparse R1CS ones don't
+	nbPublic := nbPublicVariables
+	if c.backendID == backend.GROTH16 {
+		nbPublic--
+	}
+	frSize := frModulusSize(c.curveID)
+	c.publicWitnessSize = 4 + nbPublic*frSize
+	c.fullWitnessSize = 4 + (nbPublic+nbSecretVariables)*frSize
+}
+
+// checkSizes returns an error if the constraint system doesn't match the sizes of the circuit
+func (c *circuit) checkSizes(cs frontend.CompiledConstraintSystem) error {
+	nbInternalVariables, nbSecretVariables, nbPublicVariables := cs.GetNbVariables()
+	if cs.GetNbConstraints() != c.nbConstraints || nbInternalVariables != c.nbInternalVariables ||
+		nbSecretVariables != c.nbSecretVariables || nbPublicVariables != c.nbPublicVariables {
+		return fmt.Errorf("%s: the constraint system doesn't match the manifest", c.dir)
+	}
+	return nil
+}
+
+// newCS returns an empty constraint system of the circuit backend
+func (c *circuit) newCS() frontend.CompiledConstraintSystem {
+	if c.backendID == backend.PLONK {
+		return plonk.NewCS(c.curveID)
+	}
+	return groth16.NewCS(c.curveID)
+}
+
+// csFile returns the name of the constraint system file of the circuit
+func (c *circuit) csFile() string {
+	if c.backendID == backend.PLONK {
+		return c.sprFile
+	}
+	return c.r1csFile
+}
+
+// readFile reads the file of the circuit with read
+// if the circuit has a manifest, the size and SHA-256 of the file are checked as it is read: an object read
+// from a file which doesn't match the manifest is refused
+func (c *circuit) readFile(name string, read func(r io.Reader) error) error {
+	path := filepath.Join(c.dir, name)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	expected, ok := c.files[name]
+	if !ok {
+		return read(f)
+	}
+	r := manifest.NewReader(f)
+	if err := read(r); err != nil {
+		return err
+	}
+	if err := r.Check(expected); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// loadFile reads o from the file of the circuit, see readFile
+func (c *circuit) loadFile(o io.ReaderFrom, name string) error {
+	return c.readFile(name, func(r io.Reader) error {
+		_, err := o.ReadFrom(r)
+		return err
+	})
+}
+
+// readConstraintSystem reads the constraint system of the circuit, the other fields of the provingData are nil
+func (c *circuit) readConstraintSystem() (*provingData, error) {
+	cs := c.newCS()
+	if err := c.loadFile(cs, c.csFile()); err != nil {
+		return nil, err
+	}
+	if err := c.checkSizes(cs); err != nil {
+		return nil, err
+	}
+	if c.backendID == backend.PLONK {
+		return &provingData{spr: cs}, nil
+	}
+	return &provingData{r1cs: cs}, nil
+}
+
+// readProvingData reads the provingData of the circuit from its files
+// if cs is not nil, it is the constraint system of the circuit, already read (see readConstraintSystem)
+func (c *circuit) readProvingData(cs frontend.CompiledConstraintSystem) (*provingData, error) {
+	var data *provingData
+	if cs == nil {
+		var err error
+		if data, err = c.readConstraintSystem(); err != nil {
+			return nil, err
+		}
+	} else if c.backendID == backend.PLONK {
+		data = &provingData{spr: cs}
+	} else {
+		data = &provingData{r1cs: cs}
+	}
+	if c.backendID == backend.PLONK {
+		data.scheme = plonk.NewCommitmentScheme(c.curveID)
+		if err := c.loadFile(data.scheme, c.pcsFile); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	data.pk = groth16.NewProvingKey(c.curveID)
+	if err := c.loadFile(data.pk, c.pkFile); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
@@ -0,0 +1,273 @@
+package server
+
+import (
+	context "context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// circuitsServer implements CircuitsServer
+// it manages the circuits of the Server it was created from
+type circuitsServer struct {
+	pb.UnimplementedCircuitsServer
+	s *Server
+}
+
+// Circuits returns a CircuitsServer registering and unloading the circuits of s
+func (s *Server) Circuits() pb.CircuitsServer {
+	return &circuitsServer{s: s}
+}
+
+var fileExtensions = map[pb.RegisterCircuitRequest_FileType]string{
+	pb.RegisterCircuitRequest_R1CS:              r1csExt,
+	pb.RegisterCircuitRequest_PROVING_KEY:       pkExt,
+	pb.RegisterCircuitRequest_VERIFYING_KEY:     vkExt,
+	pb.RegisterCircuitRequest_SPARSE_R1CS:       sprExt,
+	pb.RegisterCircuitRequest_COMMITMENT_SCHEME: pcsExt,
+	pb.RegisterCircuitRequest_WITNESS_SCHEMA:    schemaExt,
+}
+
+const (
+	defaultMaxCircuitFileSize   = 4 << 30 // bytes of a file received by RegisterCircuit, see WithCircuitUploadLimits
+	defaultMaxCircuitUploadSize = 8 << 30 // bytes of all the files received by a RegisterCircuit call
+)
+
+// WithCircuitUploadLimits sets the maximum size of each file of a circuit received by RegisterCircuit,
+// and of all its files; RegisterCircuit fails with ResourceExhausted beyond them
+// defaults are 4GiB per file and 8GiB per circuit
+func WithCircuitUploadLimits(maxFileSize, maxUploadSize int64) Option {
+	return func(s *Server) {
+		s.maxCircuitFileSize = maxFileSize
+		s.maxCircuitUploadSize = maxUploadSize
+	}
+}
+
+// RegisterCircuit receives the files of a circuit, stores them in circuitDir/curve/name and loads the circuit
+// the files are written in a temporary directory first, which is moved once the circuit is successfully read
+func (c *circuitsServer) RegisterCircuit(stream pb.Circuits_RegisterCircuitServer) error {
+	s := c.s
+
+	tmpDir, err := ioutil.TempDir(s.circuitDir, ".register-")
+	if err != nil {
+		s.log.Errorw("couldn't create temporary circuit directory", "err", err)
+		return status.Errorf(codes.Internal, "couldn't store circuit files")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var (
+		circuitID string
+		curveID   ecc.ID
+		name      string
+		files     = make(map[pb.RegisterCircuitRequest_FileType]*os.File)
+		fileSizes = make(map[pb.RegisterCircuitRequest_FileType]int64)
+		total     int64
+	)
+	closeFiles := func() error {
+		var err error
+		for fileType, f := range files {
+			if errClose := f.Close(); errClose != nil && err == nil {
+				err = errClose
+			}
+			delete(files, fileType)
+		}
+		return err
+	}
+	defer closeFiles()
+
+	// receive the files
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.log.Errorw("RegisterCircuit stream failed", "circuitID", circuitID, "err", err)
+			return err
+		}
+		if circuitID == "" {
+			curveID, name, err = parseCircuitID(chunk.CircuitID)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, err.Error())
+			}
+			circuitID = chunk.CircuitID
+			if _, ok := s.lookupCircuit(circuitID); ok {
+				return status.Errorf(codes.AlreadyExists, "circuit %s already exists", circuitID)
+			}
+			s.log.Debugw("RegisterCircuit", "circuitID", circuitID)
+		} else if chunk.CircuitID != "" && chunk.CircuitID != circuitID {
+			return status.Errorf(codes.InvalidArgument, "received files of circuits %s and %s", circuitID, chunk.CircuitID)
+		}
+
+		ext, ok := fileExtensions[chunk.FileType]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown file type %d", chunk.FileType)
+		}
+		if (chunk.FileType == pb.RegisterCircuitRequest_SPARSE_R1CS || chunk.FileType == pb.RegisterCircuitRequest_COMMITMENT_SCHEME) && !s.insecurePlonk {
+			return status.Errorf(codes.FailedPrecondition, "PLONK circuits are disabled, see -insecure_plonk")
+		}
+		fileSizes[chunk.FileType] += int64(len(chunk.Data))
+		total += int64(len(chunk.Data))
+		if fileSizes[chunk.FileType] > s.maxCircuitFileSize {
+			return status.Errorf(codes.ResourceExhausted, "%s file is larger than %d bytes", ext, s.maxCircuitFileSize)
+		}
+		if total > s.maxCircuitUploadSize {
+			return status.Errorf(codes.ResourceExhausted, "circuit files are larger than %d bytes", s.maxCircuitUploadSize)
+		}
+		f, ok := files[chunk.FileType]
+		if !ok {
+			if f, err = os.Create(filepath.Join(tmpDir, name+ext)); err != nil {
+				s.log.Errorw("couldn't create circuit file", "circuitID", circuitID, "err", err)
+				return status.Errorf(codes.Internal, "couldn't store circuit files")
+			}
+			files[chunk.FileType] = f
+		}
+		if _, err := f.Write(chunk.Data); err != nil {
+			s.log.Errorw("couldn't write circuit file", "circuitID", circuitID, "err", err)
+			return status.Errorf(codes.Internal, "couldn't store circuit files")
+		}
+	}
+	if circuitID == "" {
+		return status.Errorf(codes.InvalidArgument, "didn't receive any circuit files")
+	}
+	if err := closeFiles(); err != nil {
+		s.log.Errorw("couldn't write circuit file", "circuitID", circuitID, "err", err)
+		return status.Errorf(codes.Internal, "couldn't store circuit files")
+	}
+
+	// read the circuit
+	circuit, err := readCircuit(curveID, tmpDir)
+	if err != nil {
+		s.log.Errorw("couldn't read registered circuit", "circuitID", circuitID, "err", err)
+		return status.Errorf(codes.InvalidArgument, "invalid circuit files: %s", strings.ReplaceAll(err.Error(), tmpDir, circuitID))
+	}
+	// the proving data is checked now, and cached as the circuit is likely to be used soon
+	data, err := circuit.readProvingData(nil)
+	if err != nil {
+		s.log.Errorw("couldn't read registered circuit", "circuitID", circuitID, "err", err)
+		return status.Errorf(codes.InvalidArgument, "invalid circuit files: %s", strings.ReplaceAll(err.Error(), tmpDir, circuitID))
+	}
+
+	// move the files to circuitDir and load the circuit
+	s.circuitsLock.Lock()
+	if _, ok := s.circuits[circuitID]; ok {
+		s.circuitsLock.Unlock()
+		return status.Errorf(codes.AlreadyExists, "circuit %s already exists", circuitID)
+	}
+	baseDir := filepath.Join(s.circuitDir, curveID.String(), name)
+	if _, err := os.Stat(baseDir); err == nil {
+		s.circuitsLock.Unlock()
+		return status.Errorf(codes.AlreadyExists, "files of circuit %s already exist in the circuit directory", circuitID)
+	}
+	if err := os.MkdirAll(filepath.Dir(baseDir), 0700); err != nil {
+		s.circuitsLock.Unlock()
+		s.log.Errorw("couldn't create curve directory", "circuitID", circuitID, "err", err)
+		return status.Errorf(codes.Internal, "couldn't store circuit files")
+	}
+	if err := os.Rename(tmpDir, baseDir); err != nil {
+		s.circuitsLock.Unlock()
+		s.log.Errorw("couldn't move circuit files", "circuitID", circuitID, "err", err)
+		return status.Errorf(codes.Internal, "couldn't store circuit files")
+	}
+	circuit.dir = baseDir
+	s.circuits[circuitID] = circuit
+	s.cache.put(circuitID, circuit.provingDataSize, data)
+	s.circuitsLock.Unlock()
+
+	s.log.Infow("successfully registered circuit", "circuitID", circuitID)
+	return stream.SendAndClose(circuitInfo(circuitID, circuit))
+}
+
+// ListCircuits returns the loaded circuits, sorted by ID
+func (c *circuitsServer) ListCircuits(ctx context.Context, request *pb.ListCircuitsRequest) (*pb.ListCircuitsResponse, error) {
+	s := c.s
+	response := &pb.ListCircuitsResponse{}
+	s.circuitsLock.RLock()
+	for circuitID, circuit := range s.circuits {
+		if circuitAllowed(ctx, circuitID) {
+			response.Circuits = append(response.Circuits, circuitInfo(circuitID, circuit))
+		}
+	}
+	s.circuitsLock.RUnlock()
+	sort.Slice(response.Circuits, func(i, j int) bool {
+		return response.Circuits[i].CircuitID < response.Circuits[j].CircuitID
+	})
+	return response, nil
+}
+
+// GetCircuitInfo describes a loaded circuit
+func (c *circuitsServer) GetCircuitInfo(ctx context.Context, request *pb.GetCircuitInfoRequest) (*pb.CircuitInfo, error) {
+	circuit, ok := c.s.lookupCircuit(request.CircuitID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", request.CircuitID)
+	}
+	return circuitInfo(request.CircuitID, circuit), nil
+}
+
+// UnloadCircuit removes a circuit from the Server, and its directory from circuitDir if request.DeleteFiles is set
+// jobs of the circuit fail when a worker picks them up, running jobs complete
+func (c *circuitsServer) UnloadCircuit(ctx context.Context, request *pb.UnloadCircuitRequest) (*pb.UnloadCircuitResponse, error) {
+	s := c.s
+	s.log.Debugw("UnloadCircuit", "circuitID", request.CircuitID)
+
+	s.circuitsLock.Lock()
+	circuit, ok := s.circuits[request.CircuitID]
+	if !ok {
+		s.circuitsLock.Unlock()
+		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", request.CircuitID)
+	}
+	delete(s.circuits, request.CircuitID)
+	s.cache.remove(request.CircuitID)
+	s.cache.remove(csCacheKey(request.CircuitID))
+	s.circuitsLock.Unlock()
+	s.log.Infow("unloaded circuit", "circuitID", request.CircuitID)
+
+	if request.DeleteFiles {
+		// the directory the circuit was loaded from, deleted without holding circuitsLock
+		if err := os.RemoveAll(circuit.dir); err != nil {
+			s.log.Errorw("couldn't delete circuit files", "circuitID", request.CircuitID, "err", err)
+			return nil, status.Errorf(codes.Internal, "circuit %s unloaded, but its files couldn't be deleted", request.CircuitID)
+		}
+	}
+
+	return &pb.UnloadCircuitResponse{}, nil
+}
+
+// parseCircuitID splits a circuitID in its curve and its name, the circuit files are in circuitDir/curve/name
+func parseCircuitID(circuitID string) (ecc.ID, string, error) {
+	if i := strings.IndexByte(circuitID, '/'); i != -1 {
+		curve, name := circuitID[:i], circuitID[i+1:]
+		// names starting with a dot would allow ".." and hidden directories
+		if name != "" && name[0] != '.' && !strings.ContainsAny(name, `/\`) {
+			for _, curveID := range curves {
+				if curveID.String() == curve {
+					return curveID, name, nil
+				}
+			}
+		}
+	}
+	return ecc.UNKNOWN, "", fmt.Errorf("invalid circuitID %s, expected curve/name", circuitID)
+}
+
+func circuitInfo(circuitID string, circuit circuit) *pb.CircuitInfo {
+	return &pb.CircuitInfo{
+		CircuitID:           circuitID,
+		Curve:               circuit.curveID.String(),
+		Backend:             backendNames[circuit.backendID],
+		NbConstraints:       uint64(circuit.nbConstraints),
+		NbInternalVariables: uint64(circuit.nbInternalVariables),
+		NbSecretVariables:   uint64(circuit.nbSecretVariables),
+		NbPublicVariables:   uint64(circuit.nbPublicVariables),
+		FullWitnessSize:     uint64(circuit.fullWitnessSize),
+		PublicWitnessSize:   uint64(circuit.publicWitnessSize),
+	}
+}
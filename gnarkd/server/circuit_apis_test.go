@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestCircuitManagement(t *testing.T) {
+	assert := require.New(t)
+
+	// server with its own circuit directory, containing a copy of bn254/cubic
+	circuitDir, err := ioutil.TempDir("", "gnarkd-circuits")
+	assert.NoError(err)
+	defer os.RemoveAll(circuitDir)
+	assert.NoError(os.MkdirAll(filepath.Join(circuitDir, "bn254", "cubic"), 0700))
+	for _, ext := range []string{r1csExt, pkExt, vkExt} {
+		data, err := ioutil.ReadFile(filepath.Join("../circuits/bn254/cubic", "cubic"+ext))
+		assert.NoError(err)
+		assert.NoError(ioutil.WriteFile(filepath.Join(circuitDir, "bn254", "cubic", "cubic"+ext), data, 0600))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vkInfo, err := os.Stat(filepath.Join(circuitDir, "bn254", "cubic", "cubic"+vkExt))
+	assert.NoError(err)
+	s, err := NewServer(ctx, log, circuitDir, WithCircuitUploadLimits(1<<20, 1<<21))
+	assert.NoError(err)
+
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGroth16Server(grpcServer, s)
+	pb.RegisterCircuitsServer(grpcServer, s.Circuits())
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+	client := pb.NewCircuitsClient(conn)
+
+	// register the cubic circuit under another name, in small chunks
+	register := func(circuitID string, fileTypes ...pb.RegisterCircuitRequest_FileType) (*pb.CircuitInfo, error) {
+		stream, err := client.RegisterCircuit(ctx)
+		assert.NoError(err)
+		for _, fileType := range fileTypes {
+			source := "../circuits/bn254/cubic/cubic"
+			if fileType == pb.RegisterCircuitRequest_SPARSE_R1CS {
+				source = "../circuits/bn254/cubic_plonk/cubic_plonk"
+			}
+			data, err := ioutil.ReadFile(source + fileExtensions[fileType])
+			assert.NoError(err)
+			for len(data) > 0 {
+				n := 1000
+				if n > len(data) {
+					n = len(data)
+				}
+				err = stream.Send(&pb.RegisterCircuitRequest{CircuitID: circuitID, FileType: fileType, Data: data[:n]})
+				if err == io.EOF {
+					// server closed the stream, error is returned by CloseAndRecv
+					return stream.CloseAndRecv()
+				}
+				assert.NoError(err)
+				data = data[n:]
+			}
+		}
+		return stream.CloseAndRecv()
+	}
+
+	_, err = register("bn254/cubic2", pb.RegisterCircuitRequest_R1CS, pb.RegisterCircuitRequest_PROVING_KEY)
+	assert.Equal(codes.InvalidArgument, status.Code(err), "registering a circuit without verifying key should fail")
+	_, err = register("bn254/cubic", pb.RegisterCircuitRequest_R1CS)
+	assert.Equal(codes.AlreadyExists, status.Code(err))
+	_, err = register("bn254/../cubic2", pb.RegisterCircuitRequest_R1CS)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = register("bn254/cubic2", pb.RegisterCircuitRequest_SPARSE_R1CS)
+	assert.Equal(codes.FailedPrecondition, status.Code(err), "PLONK circuits are refused without WithInsecurePlonk")
+
+	// the files of a circuit are limited in size
+	s.maxCircuitFileSize = vkInfo.Size() - 1
+	_, err = register("bn254/cubic2", pb.RegisterCircuitRequest_VERIFYING_KEY)
+	assert.Equal(codes.ResourceExhausted, status.Code(err))
+	s.maxCircuitFileSize, s.maxCircuitUploadSize = 1<<20, vkInfo.Size()+1
+	_, err = register("bn254/cubic2", pb.RegisterCircuitRequest_VERIFYING_KEY, pb.RegisterCircuitRequest_R1CS)
+	assert.Equal(codes.ResourceExhausted, status.Code(err))
+	s.maxCircuitUploadSize = 1 << 21
+
+	info, err := register("bn254/cubic2", pb.RegisterCircuitRequest_R1CS, pb.RegisterCircuitRequest_PROVING_KEY, pb.RegisterCircuitRequest_VERIFYING_KEY)
+	assert.NoError(err)
+	assert.Equal("bn254/cubic2", info.CircuitID)
+	assert.Equal(ecc.BN254.String(), info.Curve)
+	assert.Equal("Groth16", info.Backend)
+	assert.Equal(uint64(2), info.NbPublicVariables, "Y and the ONE_WIRE")
+	_, err = os.Stat(filepath.Join(circuitDir, "bn254", "cubic2", "cubic2"+pkExt))
+	assert.NoError(err, "registered circuit should be stored in the circuit directory")
+
+	list, err := client.ListCircuits(ctx, &pb.ListCircuitsRequest{})
+	assert.NoError(err)
+	assert.Equal(2, len(list.Circuits))
+	assert.Equal("bn254/cubic", list.Circuits[0].CircuitID)
+	assert.Equal("bn254/cubic2", list.Circuits[1].CircuitID)
+
+	// the registered circuit can be used right away
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	_, err = pb.NewGroth16Client(conn).Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic2", Witness: bWitness.Bytes()})
+	assert.NoError(err)
+
+	// unload it
+	_, err = client.UnloadCircuit(ctx, &pb.UnloadCircuitRequest{CircuitID: "bn254/cubic2", DeleteFiles: true})
+	assert.NoError(err)
+	_, err = client.GetCircuitInfo(ctx, &pb.GetCircuitInfoRequest{CircuitID: "bn254/cubic2"})
+	assert.Equal(codes.NotFound, status.Code(err))
+	_, err = os.Stat(filepath.Join(circuitDir, "bn254", "cubic2"))
+	assert.True(os.IsNotExist(err), "circuit files should be deleted")
+	_, err = pb.NewGroth16Client(conn).Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic2", Witness: bWitness.Bytes()})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	// no temporary directories left behind
+	entries, err := ioutil.ReadDir(circuitDir)
+	assert.NoError(err)
+	assert.Equal(1, len(entries))
+}
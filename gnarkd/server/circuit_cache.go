@@ -0,0 +1,145 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+)
+
+// circuitCache keeps the provingData of the most recently used circuits in memory
+//
+// the data of a circuit is read on first use; when the size of the cached data exceeds maxSize,
+// the least recently used circuits are evicted (the data still used by a running job is freed once the job is done).
+// The size of the provingData is estimated from the size of its files, see circuit.provingDataSize.
+type circuitCache struct {
+	sync.Mutex
+	maxSize int64 // 0 for no limit
+	size    int64 // size of the loaded entries
+	entries map[string]*list.Element
+	lru     *list.List // of *cacheEntry, most recently used first
+}
+
+type cacheEntry struct {
+	circuitID string
+	ready     chan struct{} // closed once data or err is set
+	data      *provingData
+	err       error
+	size      int64 // 0 while loading
+}
+
+func newCircuitCache(maxSize int64) *circuitCache {
+	return &circuitCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns the provingData of the circuit, read with load if it isn't cached
+// concurrent calls for the same circuit share the same load, failed loads are not cached
+func (c *circuitCache) get(circuitID string, size int64, load func() (*provingData, error)) (*provingData, error) {
+	c.Lock()
+	if e, ok := c.entries[circuitID]; ok {
+		c.lru.MoveToFront(e)
+		entry := e.Value.(*cacheEntry)
+		c.Unlock()
+		<-entry.ready
+		return entry.data, entry.err
+	}
+	entry := &cacheEntry{circuitID: circuitID, ready: make(chan struct{})}
+	e := c.lru.PushFront(entry)
+	c.entries[circuitID] = e
+	c.Unlock()
+
+	data, err := load()
+
+	c.Lock()
+	entry.data, entry.err = data, err
+	if c.entries[circuitID] == e { // not removed while loading
+		if err != nil {
+			c.removeElement(e)
+		} else {
+			entry.size = size
+			c.size += size
+			c.evict(e)
+		}
+	}
+	c.Unlock()
+	close(entry.ready)
+	return data, err
+}
+
+// peek returns the provingData of the circuit if it is cached and loaded, nil otherwise; it never loads it
+func (c *circuitCache) peek(circuitID string) *provingData {
+	c.Lock()
+	defer c.Unlock()
+	e, ok := c.entries[circuitID]
+	if !ok {
+		return nil
+	}
+	entry := e.Value.(*cacheEntry)
+	select {
+	case <-entry.ready:
+		if entry.err != nil {
+			return nil
+		}
+		c.lru.MoveToFront(e)
+		return entry.data
+	default:
+		return nil
+	}
+}
+
+// put adds the provingData of a circuit, replacing the cached one if any
+func (c *circuitCache) put(circuitID string, size int64, data *provingData) {
+	c.Lock()
+	defer c.Unlock()
+	if e, ok := c.entries[circuitID]; ok {
+		c.removeElement(e)
+	}
+	entry := &cacheEntry{circuitID: circuitID, ready: make(chan struct{}), data: data, size: size}
+	close(entry.ready)
+	e := c.lru.PushFront(entry)
+	c.entries[circuitID] = e
+	c.size += size
+	c.evict(e)
+}
+
+// remove evicts the provingData of a circuit
+func (c *circuitCache) remove(circuitID string) {
+	c.Lock()
+	defer c.Unlock()
+	if e, ok := c.entries[circuitID]; ok {
+		c.removeElement(e)
+	}
+}
+
+// cachedSize returns the size of the cached provingData
+func (c *circuitCache) cachedSize() int64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.size
+}
+
+// evict removes the least recently used loaded entries until the cache fits in maxSize
+// keep is never evicted, even if it doesn't fit alone.
+// must be called under lock
+func (c *circuitCache) evict(keep *list.Element) {
+	if c.maxSize == 0 {
+		return
+	}
+	for e := c.lru.Back(); e != nil && c.size > c.maxSize; {
+		prev := e.Prev()
+		if e != keep && e.Value.(*cacheEntry).size != 0 {
+			c.removeElement(e)
+		}
+		e = prev
+	}
+}
+
+// must be called under lock
+func (c *circuitCache) removeElement(e *list.Element) {
+	entry := e.Value.(*cacheEntry)
+	c.lru.Remove(e)
+	delete(c.entries, entry.circuitID)
+	c.size -= entry.size
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitCacheEviction(t *testing.T) {
+	assert := require.New(t)
+
+	cache := newCircuitCache(100)
+	loads := make(map[string]int)
+	get := func(circuitID string, size int64) *provingData {
+		data, err := cache.get(circuitID, size, func() (*provingData, error) {
+			loads[circuitID]++
+			return &provingData{}, nil
+		})
+		assert.NoError(err)
+		return data
+	}
+
+	a := get("a", 40)
+	assert.True(a == get("a", 40), "cached data should be reused")
+	get("b", 40)
+	assert.Equal(int64(80), cache.cachedSize())
+
+	// a is used more recently than b, b is evicted
+	get("a", 40)
+	get("c", 40)
+	assert.Equal(int64(80), cache.cachedSize())
+	get("a", 40)
+	assert.Equal(1, loads["a"])
+	get("b", 40)
+	assert.Equal(2, loads["b"])
+
+	// a circuit larger than the budget is kept alone
+	get("d", 200)
+	assert.Equal(int64(200), cache.cachedSize())
+	get("d", 200)
+	assert.Equal(1, loads["d"])
+
+	cache.remove("d")
+	assert.Equal(int64(0), cache.cachedSize())
+	get("d", 200)
+	assert.Equal(2, loads["d"])
+}
+
+func TestCircuitCacheLoad(t *testing.T) {
+	assert := require.New(t)
+
+	cache := newCircuitCache(0)
+
+	// failed loads aren't cached
+	errLoad := errors.New("can't read file")
+	_, err := cache.get("a", 10, func() (*provingData, error) {
+		return nil, errLoad
+	})
+	assert.Equal(errLoad, err)
+	assert.Equal(int64(0), cache.cachedSize())
+
+	// concurrent gets share the same load
+	var (
+		wg      sync.WaitGroup
+		lock    sync.Mutex
+		nbLoads int
+	)
+	start := make(chan struct{})
+	results := make([]*provingData, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i], _ = cache.get("a", 10, func() (*provingData, error) {
+				lock.Lock()
+				nbLoads++
+				lock.Unlock()
+				return &provingData{}, nil
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	assert.Equal(1, nbLoads)
+	for _, data := range results {
+		assert.True(data == results[0])
+	}
+
+	// data removed while loading isn't cached
+	loading := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		cache.get("b", 10, func() (*provingData, error) {
+			close(loading)
+			<-done
+			return &provingData{}, nil
+		})
+		close(done)
+	}()
+	<-loading
+	cache.remove("b")
+	done <- struct{}{}
+	<-done
+	assert.Equal(int64(10), cache.cachedSize(), "only a should be cached")
+}
+
+func TestConstraintSystemCache(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+	circuit, ok := s.lookupCircuit("bn254/cubic")
+	assert.True(ok)
+
+	// validating a witness only reads the constraint system, not the proving key
+	cs, err := s.constraintSystem("bn254/cubic", circuit)
+	assert.NoError(err)
+	assert.Nil(s.cache.peek("bn254/cubic"))
+	assert.Equal(circuit.csSize, s.cache.cachedSize())
+
+	// the proving data reuses it, and replaces it in the cache
+	data, err := s.provingData("bn254/cubic", circuit)
+	assert.NoError(err)
+	assert.True(cs == data.r1cs)
+	assert.Nil(s.cache.peek(csCacheKey("bn254/cubic")))
+	assert.Equal(circuit.provingDataSize, s.cache.cachedSize())
+	cs, err = s.constraintSystem("bn254/cubic", circuit)
+	assert.NoError(err)
+	assert.True(cs == data.r1cs)
+}
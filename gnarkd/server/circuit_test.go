@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/gnarkd/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCircuitSizes(t *testing.T) {
+	assert := require.New(t)
+
+	// copy of bn254/cubic, with its manifest
+	dir := filepath.Join(t.TempDir(), "cubic")
+	assert.NoError(os.MkdirAll(dir, 0700))
+	for _, ext := range []string{r1csExt, pkExt, vkExt, schemaExt, manifest.Ext} {
+		data, err := ioutil.ReadFile(filepath.Join("../circuits/bn254/cubic", "cubic"+ext))
+		assert.NoError(err)
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, "cubic"+ext), data, 0600))
+	}
+	m, err := manifest.Read(dir)
+	assert.NoError(err)
+	writeManifest := func(m *manifest.Manifest) {
+		data, err := json.Marshal(m)
+		assert.NoError(err)
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, "cubic"+manifest.Ext), data, 0600))
+	}
+
+	// sizes are read from the manifest
+	c, err := readCircuit(ecc.BN254, dir)
+	assert.NoError(err)
+	assert.Equal(m.NbConstraints, c.nbConstraints)
+	assert.Equal(m.NbSecretVariables, c.nbSecretVariables)
+	assert.Equal(m.NbPublicVariables, c.nbPublicVariables)
+	_, err = c.readProvingData(nil)
+	assert.NoError(err)
+
+	pk, err := os.Stat(filepath.Join(dir, "cubic"+pkExt))
+	assert.NoError(err)
+	r1cs, err := os.Stat(filepath.Join(dir, "cubic"+r1csExt))
+	assert.NoError(err)
+	assert.Equal(pkMemoryFactor*pk.Size()+r1cs.Size(), c.provingDataSize)
+
+	// without manifest, sizes are read from the constraint system
+	assert.NoError(os.Remove(filepath.Join(dir, "cubic"+manifest.Ext)))
+	c2, err := readCircuit(ecc.BN254, dir)
+	assert.NoError(err)
+	assert.Equal(c.nbConstraints, c2.nbConstraints)
+	assert.Equal(c.nbInternalVariables, c2.nbInternalVariables)
+	assert.Equal(c.fullWitnessSize, c2.fullWitnessSize)
+	assert.Equal(c.publicWitnessSize, c2.publicWitnessSize)
+
+	// a stale manifest is detected when the constraint system is read
+	stale := *m
+	stale.NbConstraints++
+	writeManifest(&stale)
+	c, err = readCircuit(ecc.BN254, dir)
+	assert.NoError(err)
+	_, err = c.readProvingData(nil)
+	assert.Error(err)
+
+	// files of another size than in the manifest are rejected
+	stale = *m
+	stale.Files = map[string]manifest.File{"cubic" + pkExt: {Size: 1}}
+	writeManifest(&stale)
+	_, err = readCircuit(ecc.BN254, dir)
+	assert.Error(err)
+
+	// so are files of the same size but another content, when they are read
+	for _, ext := range []string{pkExt, vkExt} {
+		stale = *m
+		stale.Files = make(map[string]manifest.File)
+		for name, file := range m.Files {
+			stale.Files[name] = file
+		}
+		file := stale.Files["cubic"+ext]
+		file.SHA256 = strings.Repeat("0", len(file.SHA256))
+		stale.Files["cubic"+ext] = file
+		writeManifest(&stale)
+		c, err = readCircuit(ecc.BN254, dir)
+		if ext == vkExt {
+			// the verifying key is read with the circuit
+			assert.Error(err)
+			continue
+		}
+		assert.NoError(err)
+		_, err = c.readProvingData(nil)
+		assert.Error(err)
+	}
+
+	// so is a manifest of another backend
+	stale = *m
+	stale.Backend = "plonk"
+	writeManifest(&stale)
+	_, err = readCircuit(ecc.BN254, dir)
+	assert.Error(err)
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// cpuMeter measures the CPU time of the proofs computed by the Server, to account it in the Quotas
+//
+// the process CPU time is sampled each time a proof starts or ends; the CPU time used between two samples is
+// split between the proofs running in the meantime, in proportion of their weight (see proofWeight): a small
+// proof running next to a large one is charged for its share of the work, not for half of the large one.
+// Where the process CPU time isn't available, the wall-clock time multiplied by GOMAXPROCS is used instead.
+//
+// This is an approximation: the CPU time of the process while proofs run also includes the verifications,
+// the gRPC calls and the GC, which are billed to the running proofs, and proofs of the same weight may
+// use a different CPU time (e.g. a proof waiting for the MSM workers, whose CPU time isn't counted, is charged
+// its share of the other proofs' time). CPU time used while no proof runs isn't billed to anyone.
+type cpuMeter struct {
+	lock        sync.Mutex
+	last        time.Duration // process CPU time at the last sample
+	lastAt      time.Time     // wall-clock time of the last sample
+	running     map[*meteredProof]struct{}
+	totalWeight int64 // of the running proofs
+}
+
+// meteredProof is a proof measured by a cpuMeter, see cpuMeter.start
+type meteredProof struct {
+	cpu    time.Duration
+	weight int64
+}
+
+func newCPUMeter() *cpuMeter {
+	return &cpuMeter{running: make(map[*meteredProof]struct{})}
+}
+
+// start starts measuring a proof of the given weight (see proofWeight), to be stopped with stop
+func (m *cpuMeter) start(weight int64) *meteredProof {
+	if weight < 1 {
+		weight = 1
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.sample()
+	p := &meteredProof{weight: weight}
+	m.running[p] = struct{}{}
+	m.totalWeight += weight
+	return p
+}
+
+// stop returns the CPU time used by the proof since start
+func (m *cpuMeter) stop(p *meteredProof) time.Duration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.sample()
+	delete(m.running, p)
+	m.totalWeight -= p.weight
+	return p.cpu
+}
+
+// sample splits the CPU time used since the last sample between the running proofs, by weight
+// must be called under lock
+func (m *cpuMeter) sample() {
+	now := time.Now()
+	cpu, ok := processCPUTime()
+	if !ok {
+		cpu = m.last + now.Sub(m.lastAt)*time.Duration(runtime.GOMAXPROCS(0))
+	}
+	if m.totalWeight > 0 && cpu > m.last {
+		used := float64(cpu - m.last)
+		for p := range m.running {
+			p.cpu += time.Duration(used * float64(p.weight) / float64(m.totalWeight))
+		}
+	}
+	m.last, m.lastAt = cpu, now
+}
+
+// proofWeight estimates the relative cost of a proof of the circuit: the multi-exponentiations and FFTs of the
+// provers are (quasi) linear in the number of constraints and variables
+func proofWeight(c circuit) int64 {
+	return int64(c.nbConstraints + c.nbInternalVariables + c.nbSecretVariables + c.nbPublicVariables)
+}
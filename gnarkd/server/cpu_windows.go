@@ -0,0 +1,8 @@
+package server
+
+import "time"
+
+// processCPUTime isn't available on windows, see cpuMeter
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
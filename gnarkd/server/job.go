@@ -5,22 +5,28 @@ import (
 	"sync"
 	"time"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/gnarkd/pb"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 )
 
-const jobIDSize = len(uuid.UUID{})
-
 type jobID = uuid.UUID
 type proveJob struct {
 	sync.RWMutex
+	saveLock sync.Mutex // serializes the saves of the job, see Server.saveJob
 
 	id          jobID
 	circuitID   string
+	backendID   backend.ID
+	priority    int32
+	owner       string // identity which created the job, empty if authorization is disabled
+	callbackURL string // the result is POSTed there when the job finishes, see Server.notifyFinished
 	status      pb.ProveJobResult_Status
 	expiration  time.Time
 	witness     []byte
 	err         error
+	solverErr   *pb.SolverError // set if the witness doesn't solve the circuit
 	proof       []byte
 	subscribers []chan struct{}
 }
@@ -87,3 +93,62 @@ func (job *proveJob) unsubscribe(ch chan struct{}) {
 func (job *proveJob) isFinished() bool {
 	return (job.status == pb.ProveJobResult_COMPLETED) || (job.status == pb.ProveJobResult_ERRORED)
 }
+
+// must be called under lock
+func (job *proveJob) result() *pb.ProveJobResult {
+	result := &pb.ProveJobResult{JobID: job.id.String(), Status: job.status, Proof: job.proof, SolverError: job.solverErr}
+	if job.err != nil {
+		errMsg := job.err.Error()
+		result.Err = &errMsg
+	}
+	return result
+}
+
+// must be called under lock
+func (job *proveJob) record() *JobRecord {
+	r := &JobRecord{
+		ID:          job.id,
+		CircuitID:   job.circuitID,
+		BackendID:   job.backendID,
+		Priority:    job.priority,
+		Owner:       job.owner,
+		CallbackURL: job.callbackURL,
+		Status:      job.status,
+		Expiration:  job.expiration,
+		Witness:     job.witness,
+		Proof:       job.proof,
+	}
+	if job.err != nil {
+		r.Err = job.err.Error()
+	}
+	if job.solverErr != nil {
+		r.SolverError, _ = proto.Marshal(job.solverErr)
+	}
+	return r
+}
+
+// newProveJob restores a job from its record
+func newProveJob(r *JobRecord) *proveJob {
+	job := &proveJob{
+		id:          r.ID,
+		circuitID:   r.CircuitID,
+		backendID:   r.BackendID,
+		priority:    r.Priority,
+		owner:       r.Owner,
+		callbackURL: r.CallbackURL,
+		status:      r.Status,
+		expiration:  r.Expiration,
+		witness:     r.Witness,
+		proof:       r.Proof,
+	}
+	if r.Err != "" {
+		job.err = errors.New(r.Err)
+	}
+	if len(r.SolverError) != 0 {
+		job.solverErr = &pb.SolverError{}
+		if err := proto.Unmarshal(r.SolverError, job.solverErr); err != nil {
+			job.solverErr = nil
+		}
+	}
+	return job
+}
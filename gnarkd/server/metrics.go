@@ -0,0 +1,134 @@
+package server
+
+import (
+	"time"
+
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "gnarkd"
+
+// metrics of a Server, see WithMetrics
+type metrics struct {
+	proveDuration   *prometheus.HistogramVec
+	verifyDuration  *prometheus.HistogramVec
+	batchVerify     *prometheus.HistogramVec
+	witnessFailures *prometheus.CounterVec
+}
+
+// reasons of the witness failures
+const (
+	witnessInvalidJob   = "invalid_job"  // unknown job, or job not waiting for a witness
+	witnessInvalidSize  = "invalid_size" // truncated or oversized witness
+	witnessStreamFailed = "stream"       // client stream failed
+	witnessUnsatisfied  = "unsatisfied"  // witness doesn't solve the circuit
+)
+
+func newMetrics() *metrics {
+	// proving time goes from milliseconds to hours depending on the circuit
+	buckets := prometheus.ExponentialBuckets(0.01, 4, 10)
+	return &metrics{
+		proveDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "prove_duration_seconds",
+			Help:      "Time spent computing proofs, sync and async.",
+			Buckets:   buckets,
+		}, []string{"circuit"}),
+		verifyDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "verify_duration_seconds",
+			Help:      "Time spent verifying proofs.",
+			Buckets:   buckets,
+		}, []string{"circuit"}),
+		batchVerify: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "batch_verify_duration_seconds",
+			Help:      "Time spent verifying batches of proofs (BatchVerify).",
+			Buckets:   buckets,
+		}, []string{"circuit"}),
+		witnessFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "witness_failures_total",
+			Help:      "Witnesses rejected by SubmitWitness.",
+		}, []string{"reason"}),
+	}
+}
+
+// registerMetrics registers the metrics of s in reg
+func (s *Server) registerMetrics(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		s.metrics.proveDuration,
+		s.metrics.verifyDuration,
+		s.metrics.batchVerify,
+		s.metrics.witnessFailures,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "queued_jobs",
+			Help:      "Jobs waiting for a worker.",
+		}, func() float64 {
+			return float64(s.scheduler.len())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "running_jobs_memory_bytes",
+			Help:      "Estimated memory of the running jobs, see -memory_limit.",
+		}, func() float64 {
+			return float64(s.scheduler.runningMemory())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "circuit_cache_bytes",
+			Help:      "Estimated memory of the proving keys and constraint systems in memory, see -circuit_cache_size.",
+		}, func() float64 {
+			return float64(s.cache.cachedSize())
+		}),
+		&jobsCollector{s: s, desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "jobs"),
+			"Jobs kept by the server, by status.",
+			[]string{"status"}, nil,
+		)},
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *metrics) observeProve(circuitID string, start time.Time) {
+	m.proveDuration.WithLabelValues(circuitID).Observe(time.Since(start).Seconds())
+}
+
+func (m *metrics) observeVerify(circuitID string, start time.Time) {
+	m.verifyDuration.WithLabelValues(circuitID).Observe(time.Since(start).Seconds())
+}
+
+func (m *metrics) observeBatchVerify(circuitID string, start time.Time) {
+	m.batchVerify.WithLabelValues(circuitID).Observe(time.Since(start).Seconds())
+}
+
+// jobsCollector counts the jobs by status when metrics are collected
+type jobsCollector struct {
+	s    *Server
+	desc *prometheus.Desc
+}
+
+func (c *jobsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *jobsCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[pb.ProveJobResult_Status]int)
+	c.s.jobs.Range(func(k, v interface{}) bool {
+		job := v.(*proveJob)
+		job.RLock()
+		counts[job.status]++
+		job.RUnlock()
+		return true
+	})
+	for status, name := range pb.ProveJobResult_Status_name {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[pb.ProveJobResult_Status(status)]), name)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := prometheus.NewRegistry()
+	s, err := NewServer(ctx, log, "../circuits", WithMetrics(registry))
+	assert.NoError(err)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+
+	_, err = s.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.NoError(err)
+	_, err = s.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+
+	families, err := registry.Gather()
+	assert.NoError(err)
+	found := make(map[string]bool)
+	for _, f := range families {
+		found[f.GetName()] = true
+		switch f.GetName() {
+		case "gnarkd_prove_duration_seconds":
+			assert.Equal(1, len(f.Metric))
+			assert.Equal(uint64(1), f.Metric[0].GetHistogram().GetSampleCount())
+			assert.Equal("bn254/cubic", f.Metric[0].Label[0].GetValue())
+		case "gnarkd_jobs":
+			for _, m := range f.Metric {
+				expected := 0.0
+				if m.Label[0].GetValue() == pb.ProveJobResult_WAITING_WITNESS.String() {
+					expected = 1
+				}
+				assert.Equal(expected, m.GetGauge().GetValue(), m.Label[0].GetValue())
+			}
+		case "gnarkd_queued_jobs":
+			assert.Equal(0.0, f.Metric[0].GetGauge().GetValue())
+		}
+	}
+	for _, name := range []string{"gnarkd_prove_duration_seconds", "gnarkd_jobs", "gnarkd_queued_jobs", "gnarkd_running_jobs_memory_bytes"} {
+		assert.True(found[name], name)
+	}
+}
@@ -0,0 +1,296 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/internal/backend/bn254/cs"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	witness_bn254 "github.com/consensys/gnark/internal/backend/bn254/witness"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// msmChunkSize bounds the scalars sent in a MultiExpChunk message
+const msmChunkSize = 1 << 20
+
+const (
+	msmListTimeout  = 10 * time.Second // bounds the ListChunks call to a worker
+	msmListInterval = time.Minute      // the chunks held by the workers are listed again after msmListInterval
+)
+
+// WithMSMWorkers computes the multi-exponentiations of the bn254 Groth16 proofs with gnarkd-worker processes
+//
+// a circuit whose .pk file is a split proving key (see gnarkd-worker split) is proved with the workers holding
+// its chunks; the other circuits are proved locally. The chunks held by the workers are listed when the server
+// starts, then in the background.
+func WithMSMWorkers(conns ...grpc.ClientConnInterface) Option {
+	return func(s *Server) {
+		s.msmWorkers = &msmWorkers{refresh: make(chan struct{}, 1)}
+		for _, conn := range conns {
+			s.msmWorkers.clients = append(s.msmWorkers.clients, pb.NewMSMWorkerClient(conn))
+		}
+	}
+}
+
+// msmWorkers dispatches the multi-exponentiations of the proofs to the workers holding the proving key chunks
+type msmWorkers struct {
+	clients []pb.MSMWorkerClient
+	refresh chan struct{} // lists the chunks again before msmListInterval
+
+	lock   sync.RWMutex
+	chunks map[string][]workerChunk // by circuitID, set by list
+	errs   map[string]error         // by circuitID, the circuits whose chunks don't cover the points
+}
+
+// workerChunk is a proving key chunk held by a worker
+type workerChunk struct {
+	client     pb.MSMWorkerClient
+	start, end [groth16_bn254.NbMSM]int
+	total      [groth16_bn254.NbMSM]int
+}
+
+// run lists the chunks held by the workers every msmListInterval, or when a proof with them failed, until ctx is done
+func (m *msmWorkers) run(ctx context.Context, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(msmListInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.refresh:
+		}
+		m.list(ctx, log)
+	}
+}
+
+// list asks each worker the chunks it holds, concurrently, and replaces the chunks known by find
+// a worker that doesn't answer within msmListTimeout is logged and its chunks are missing until the next listing
+func (m *msmWorkers) list(ctx context.Context, log *zap.SugaredLogger) {
+	results := make([]*pb.ListChunksResponse, len(m.clients))
+	var wg sync.WaitGroup
+	for i, client := range m.clients {
+		wg.Add(1)
+		go func(i int, client pb.MSMWorkerClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, msmListTimeout)
+			defer cancel()
+			r, err := client.ListChunks(ctx, &pb.ListChunksRequest{})
+			if err != nil {
+				log.Errorw("couldn't list the chunks of an MSM worker", "worker", i, "err", err)
+				return
+			}
+			results[i] = r
+		}(i, client)
+	}
+	wg.Wait()
+
+	chunks := make(map[string][]workerChunk)
+	errs := make(map[string]error)
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		for _, info := range r.Chunks {
+			if len(info.Ranges) != int(groth16_bn254.NbMSM) {
+				errs[info.CircuitID] = fmt.Errorf("chunk of %s with %d ranges, expected %d", info.CircuitID, len(info.Ranges), groth16_bn254.NbMSM)
+				continue
+			}
+			c := workerChunk{client: m.clients[i]}
+			for msm, r := range info.Ranges {
+				c.start[msm], c.end[msm], c.total[msm] = int(r.Start), int(r.End), int(r.Total)
+			}
+			chunks[info.CircuitID] = append(chunks[info.CircuitID], c)
+		}
+	}
+	for circuitID, c := range chunks {
+		if err := checkChunks(circuitID, c); err != nil {
+			errs[circuitID] = err
+		}
+	}
+	for circuitID, err := range errs {
+		log.Warnw("the MSM workers can't prove the circuit", "circuitID", circuitID, "err", err)
+		delete(chunks, circuitID)
+	}
+
+	m.lock.Lock()
+	m.chunks, m.errs = chunks, errs
+	m.lock.Unlock()
+}
+
+// checkChunks checks the chunks cover all the points of each msm
+func checkChunks(circuitID string, chunks []workerChunk) error {
+	for msm := groth16_bn254.MSM(0); msm < groth16_bn254.NbMSM; msm++ {
+		sort.Slice(chunks, func(i, j int) bool { return chunks[i].start[msm] < chunks[j].start[msm] })
+		next := 0
+		for _, c := range chunks {
+			if c.start[msm] != next || c.total[msm] != chunks[0].total[msm] {
+				return fmt.Errorf("the chunks of %s held by the workers don't cover the points of %s (missing %d)", circuitID, msm, next)
+			}
+			next = c.end[msm]
+		}
+		if next != chunks[0].total[msm] {
+			return fmt.Errorf("the chunks of %s held by the workers don't cover the points of %s (missing %d)", circuitID, msm, next)
+		}
+	}
+	return nil
+}
+
+// find returns the chunks of the circuit held by the workers, as of the last listing
+// it errors if the workers don't hold all the chunks of the circuit
+func (m *msmWorkers) find(circuitID string) ([]workerChunk, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if chunks, ok := m.chunks[circuitID]; ok {
+		return chunks, nil
+	}
+	if err, ok := m.errs[circuitID]; ok {
+		return nil, err
+	}
+	return nil, fmt.Errorf("no MSM worker holds the chunks of %s", circuitID)
+}
+
+// relist lists the chunks held by the workers again, in the background
+func (m *msmWorkers) relist() {
+	select {
+	case m.refresh <- struct{}{}:
+	default: // a listing is already pending
+	}
+}
+
+// isSplit returns true if the proving key was split by groth16_bn254.SplitProvingKey
+// the split key has no multi-exponentiation points, while a full key has one pk.G1.Z point per domain element
+func isSplit(pk *groth16_bn254.ProvingKey) bool {
+	return len(pk.G1.Z) == 0
+}
+
+// prove computes a bn254 Groth16 proof, with the multi-exponentiations computed by the workers holding chunks
+// the proof is verified with vk before it is returned: a faulty worker or an outdated chunk fails the proof
+// instead of returning an invalid one. The calls to the workers are cancelled with ctx.
+func (m *msmWorkers) prove(ctx context.Context, circuitID string, chunks []workerChunk, data *provingData, vk *groth16_bn254.VerifyingKey, witness []byte) (io.WriterTo, error) {
+	r1cs := data.r1cs.(*cs.R1CS)
+	pk := data.pk.(*groth16_bn254.ProvingKey)
+	_, nbSecret, nbPublic := r1cs.GetNbVariables()
+	var w witness_bn254.Witness
+	if _, err := w.LimitReadFrom(bytes.NewReader(witness), nbSecret+nbPublic-1); err != nil {
+		return nil, err
+	}
+	proof, err := groth16_bn254.ProveWith(r1cs, pk, w, false, &remoteMultiExper{ctx: ctx, circuitID: circuitID, chunks: chunks})
+	if err != nil {
+		m.relist()
+		return nil, err
+	}
+	if err := groth16_bn254.Verify(proof, vk, w[:nbPublic-1]); err != nil {
+		m.relist()
+		return nil, fmt.Errorf("the proof of %s computed with the MSM workers doesn't verify: %w", circuitID, err)
+	}
+	return proof, nil
+}
+
+// remoteMultiExper implements groth16_bn254.MultiExper with the chunks of the workers
+type remoteMultiExper struct {
+	ctx       context.Context
+	circuitID string
+	chunks    []workerChunk
+}
+
+func (r *remoteMultiExper) MultiExpG1(msm groth16_bn254.MSM, scalars []fr.Element) (curve.G1Jac, error) {
+	var res curve.G1Jac
+	points, err := r.multiExp(msm, scalars)
+	if err != nil {
+		return res, err
+	}
+	for _, buf := range points {
+		var p curve.G1Affine
+		if err := p.Unmarshal(buf); err != nil {
+			return res, fmt.Errorf("invalid %s point from a worker: %w", msm, err)
+		}
+		res.AddMixed(&p)
+	}
+	return res, nil
+}
+
+func (r *remoteMultiExper) MultiExpG2(scalars []fr.Element) (curve.G2Jac, error) {
+	var res curve.G2Jac
+	points, err := r.multiExp(groth16_bn254.MSMG2B, scalars)
+	if err != nil {
+		return res, err
+	}
+	for _, buf := range points {
+		var p curve.G2Affine
+		if err := p.Unmarshal(buf); err != nil {
+			return res, fmt.Errorf("invalid %s point from a worker: %w", groth16_bn254.MSMG2B, err)
+		}
+		res.AddMixed(&p)
+	}
+	return res, nil
+}
+
+// multiExp sends the scalars of each chunk to its worker, concurrently, and returns the encoded points they computed
+func (r *remoteMultiExper) multiExp(msm groth16_bn254.MSM, scalars []fr.Element) ([][]byte, error) {
+	points := make([][]byte, len(r.chunks))
+	errs := make([]error, len(r.chunks))
+	// the chunks cover consecutive ranges of the points (see find): they must end with the last scalar,
+	// otherwise the points of the proving key and the scalars of the proof don't match
+	nbPoints := 0
+	for _, c := range r.chunks {
+		if c.end[msm] > nbPoints {
+			nbPoints = c.end[msm]
+		}
+	}
+	if nbPoints != len(scalars) {
+		return nil, fmt.Errorf("the chunks of %s hold %d points in %s for %d scalars", r.circuitID, nbPoints, msm, len(scalars))
+	}
+	var wg sync.WaitGroup
+	for i, c := range r.chunks {
+		wg.Add(1)
+		go func(i int, c workerChunk) {
+			defer wg.Done()
+			points[i], errs[i] = r.send(c.client, msm, scalars[c.start[msm]:c.end[msm]])
+		}(i, c)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("multi-exponentiation %s of %s failed on a worker: %w", msm, r.circuitID, err)
+		}
+	}
+	return points, nil
+}
+
+// send streams the scalars to a worker and returns its result
+func (r *remoteMultiExper) send(client pb.MSMWorkerClient, msm groth16_bn254.MSM, scalars []fr.Element) ([]byte, error) {
+	stream, err := client.MultiExp(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	buf := groth16_bn254.MarshalScalars(scalars)
+	msg := &pb.MultiExpChunk{CircuitID: r.circuitID, Msm: pb.MultiExpChunk_MSM(msm)}
+	for first := true; first || len(buf) > 0; first = false {
+		n := len(buf)
+		if n > msmChunkSize {
+			n = msmChunkSize
+		}
+		msg.Scalars, buf = buf[:n], buf[n:]
+		if err := stream.Send(msg); err == io.EOF {
+			break // the worker failed, its error is returned by CloseAndRecv
+		} else if err != nil {
+			return nil, err
+		}
+		msg = &pb.MultiExpChunk{}
+	}
+	result, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return result.Point, nil
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/consensys/gnark/gnarkd/worker"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMSMWorkers(t *testing.T) {
+	assert := require.New(t)
+	const nbWorkers = 2
+
+	// split the proving key of bn254/cubic: the circuit dir of gnarkd holds the split key,
+	// each worker holds one chunk
+	dir := t.TempDir()
+	circuitDir := filepath.Join(dir, "circuits", "bn254", "cubic")
+	assert.NoError(os.MkdirAll(circuitDir, 0700))
+	for _, ext := range []string{".r1cs", ".vk", ".schema"} {
+		b, err := ioutil.ReadFile(filepath.Join("../circuits/bn254/cubic", "cubic"+ext))
+		assert.NoError(err)
+		assert.NoError(ioutil.WriteFile(filepath.Join(circuitDir, "cubic"+ext), b, 0600))
+	}
+	pkFile, err := os.Open("../circuits/bn254/cubic/cubic.pk")
+	assert.NoError(err)
+	defer pkFile.Close()
+	var bChunks [nbWorkers]bytes.Buffer
+	var bSplit bytes.Buffer
+	assert.NoError(groth16_bn254.SplitProvingKey(pkFile, &bSplit, []io.Writer{&bChunks[0], &bChunks[1]}))
+	assert.NoError(ioutil.WriteFile(filepath.Join(circuitDir, "cubic.pk"), bSplit.Bytes(), 0600))
+
+	// start the workers, and a faulty copy of the first one
+	conns := make([]grpc.ClientConnInterface, nbWorkers+1)
+	for i := range conns {
+		chunkDir := filepath.Join(dir, fmt.Sprintf("worker%d", i))
+		assert.NoError(os.MkdirAll(filepath.Join(chunkDir, "bn254", "cubic"), 0700))
+		assert.NoError(ioutil.WriteFile(filepath.Join(chunkDir, "bn254", "cubic", "cubic"+worker.ChunkExtension), bChunks[i%nbWorkers].Bytes(), 0600))
+		w, err := worker.New(log, chunkDir)
+		assert.NoError(err)
+
+		lis := bufconn.Listen(bufSize)
+		s := grpc.NewServer()
+		if i == nbWorkers {
+			pb.RegisterMSMWorkerServer(s, faultyWorker{w})
+		} else {
+			pb.RegisterMSMWorkerServer(s, w)
+		}
+		go s.Serve(lis)
+		defer s.Stop()
+		conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+			func(c context.Context, s string) (net.Conn, error) {
+				return lis.Dial()
+			}), grpc.WithInsecure())
+		assert.NoError(err)
+		defer conn.Close()
+		conns[i] = conn
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gnarkd, err := NewServer(ctx, log, filepath.Join(dir, "circuits"), WithMSMWorkers(conns[:nbWorkers]...))
+	assert.NoError(err)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+		bPublic  bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &w)
+	assert.NoError(err)
+
+	// the proof computed with the workers is valid
+	proveResult, err := gnarkd.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.NoError(err)
+	verifyResult, err := gnarkd.Verify(ctx, &pb.VerifyRequest{CircuitID: "bn254/cubic", Proof: proveResult.Proof, PublicWitness: bPublic.Bytes()})
+	assert.NoError(err)
+	assert.True(verifyResult.Ok)
+
+	// without all the chunks, or without workers, the split key can't prove
+	partial, err := NewServer(ctx, log, filepath.Join(dir, "circuits"), WithMSMWorkers(conns[0]))
+	assert.NoError(err)
+	_, err = partial.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Error(err)
+	// a worker returning wrong results fails the proof, instead of returning an invalid one
+	faulty, err := NewServer(ctx, log, filepath.Join(dir, "circuits"), WithMSMWorkers(conns[nbWorkers], conns[1]))
+	assert.NoError(err)
+	_, err = faulty.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Error(err)
+	local, err := NewServer(ctx, log, filepath.Join(dir, "circuits"))
+	assert.NoError(err)
+	_, err = local.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Error(err)
+}
+
+func TestMSMWorkerUnreachable(t *testing.T) {
+	assert := require.New(t)
+
+	// the worker listener is closed: listing its chunks fails
+	lis := bufconn.Listen(bufSize)
+	assert.NoError(lis.Close())
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gnarkd, err := NewServer(ctx, log, "../circuits", WithMSMWorkers(conn))
+	assert.NoError(err)
+
+	// the proving key of bn254/cubic isn't split, it is proved locally
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	_, err = gnarkd.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.NoError(err)
+}
+
+func TestMSMChunksCoverScalars(t *testing.T) {
+	assert := require.New(t)
+
+	// the chunks hold the points [0, 4) of each msm, they can't compute a multi-exponentiation of 3 or 5 scalars
+	var c0, c1 workerChunk
+	for msm := range c0.end {
+		c0.end[msm], c1.start[msm], c1.end[msm] = 2, 2, 4
+	}
+	r := &remoteMultiExper{ctx: context.Background(), circuitID: "bn254/cubic", chunks: []workerChunk{c0, c1}}
+	for _, nbScalars := range []int{3, 5} {
+		_, err := r.MultiExpG1(groth16_bn254.MSMA, make([]fr.Element, nbScalars))
+		assert.Error(err)
+		_, err = r.MultiExpG2(make([]fr.Element, nbScalars))
+		assert.Error(err)
+	}
+}
+
+// faultyWorker computes the multi-exponentiations of its chunks, and returns the point at infinity instead
+type faultyWorker struct {
+	pb.MSMWorkerServer
+}
+
+func (w faultyWorker) MultiExp(stream pb.MSMWorker_MultiExpServer) error {
+	return w.MSMWorkerServer.MultiExp(faultyStream{stream})
+}
+
+type faultyStream struct {
+	pb.MSMWorker_MultiExpServer
+}
+
+func (s faultyStream) SendAndClose(result *pb.MultiExpResult) error {
+	var g1 curve.G1Affine
+	var g2 curve.G2Affine
+	if b := g1.Marshal(); len(b) == len(result.Point) {
+		result.Point = b
+	} else {
+		result.Point = g2.Marshal()
+	}
+	return s.MSMWorker_MultiExpServer.SendAndClose(result)
+}
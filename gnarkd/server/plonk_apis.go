@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// plonkServer implements PlonkServer
+// it shares the circuits and the job queue of the Server it was created from
+type plonkServer struct {
+	pb.UnimplementedPlonkServer
+	s *Server
+}
+
+// Plonk returns a PlonkServer serving the PLONK circuits of s, loaded with WithInsecurePlonk only
+// PLONK proofs use a mock polynomial commitment scheme: they are NOT sound, a forged proof verifies
+func (s *Server) Plonk() pb.PlonkServer {
+	return &plonkServer{s: s}
+}
+
+// Prove takes circuitID and witness as parameter
+// this is a synchronous call and bypasses the job queue
+// it is meant to be used for small circuits, for larger circuits (proving time) and witnesses,
+// use CreateProveJob instead
+func (p *plonkServer) Prove(ctx context.Context, request *pb.ProveRequest) (*pb.ProveResult, error) {
+	return p.s.syncProve(ctx, request, backend.PLONK)
+}
+
+// Verify takes circuitID, proof and public witness as parameter
+// this is a synchronous call
+func (p *plonkServer) Verify(ctx context.Context, request *pb.VerifyRequest) (*pb.VerifyResult, error) {
+	s := p.s
+	s.log.Debugw("Verify", "circuitID", request.CircuitID)
+
+	// get circuit
+	circuit, err := s.getCircuit(request.CircuitID, backend.PLONK)
+	if err != nil {
+		s.log.Errorw("Verify called with invalid circuitID", "circuitID", request.CircuitID, "err", err)
+		return nil, err
+	}
+
+	data, err := s.provingData(request.CircuitID, circuit)
+	if err != nil {
+		s.log.Errorw("couldn't read circuit proving data", "circuitID", request.CircuitID, "err", err)
+		return nil, status.Errorf(codes.Internal, "couldn't read circuit %s", request.CircuitID)
+	}
+
+	// call plonk.Verify with witness
+	proof := plonk.NewProof(circuit.curveID)
+	if _, err := proof.ReadFrom(bytes.NewReader(request.Proof)); err != nil {
+		s.log.Error(err)
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	start := time.Now()
+	err = plonk.ReadAndVerify(proof, data.spr, data.scheme, bytes.NewReader(request.PublicWitness))
+	s.metrics.observeVerify(request.CircuitID, start)
+	if err != nil {
+		s.log.Error(err)
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	s.log.Infow("successfully verified proof", "circuitID", request.CircuitID)
+	return &pb.VerifyResult{Ok: true}, nil
+}
+
+// CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
+func (p *plonkServer) CreateProveJob(ctx context.Context, request *pb.CreateProveJobRequest) (*pb.CreateProveJobResponse, error) {
+	return p.s.createProveJob(ctx, request, backend.PLONK)
+}
+
+// SubmitWitness receives the witness of a job created by CreateProveJob and queues the job
+func (p *plonkServer) SubmitWitness(stream pb.Plonk_SubmitWitnessServer) error {
+	return p.s.submitWitness(stream, backend.PLONK)
+}
+
+// CancelProveJob does what it says it does.
+func (p *plonkServer) CancelProveJob(ctx context.Context, request *pb.CancelProveJobRequest) (*pb.CancelProveJobResponse, error) {
+	return p.s.cancelProveJob(ctx, request, backend.PLONK)
+}
+
+// ListProveJob does what it says it does
+func (p *plonkServer) ListProveJob(ctx context.Context, request *pb.ListProveJobRequest) (*pb.ListProveJobResponse, error) {
+	return p.s.listProveJob(ctx, backend.PLONK), nil
+}
+
+// SubscribeToProveJob enables a client to get job status changes from the Server
+// at connection start, Server sends current job status
+// when job is done (ok or errored), Server closes connection
+func (p *plonkServer) SubscribeToProveJob(request *pb.SubscribeToProveJobRequest, stream pb.Plonk_SubscribeToProveJobServer) error {
+	return p.s.subscribeToProveJob(request, stream, backend.PLONK)
+}
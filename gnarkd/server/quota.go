@@ -0,0 +1,226 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits bounds the proofs of an identity, see Permissions
+// zero values mean no limit
+type Limits struct {
+	// MaxConcurrentJobs bounds the prove jobs not finished yet (waiting for their witness, queued or running)
+	// and the synchronous Prove calls being served
+	MaxConcurrentJobs int `json:"maxConcurrentJobs"`
+
+	// JobsPerMinute bounds the Prove and CreateProveJob calls over the last minute
+	JobsPerMinute int `json:"jobsPerMinute"`
+
+	// ProveCPUSecondsPerDay bounds the CPU time spent proving (sync and async) per UTC day; once exceeded,
+	// new proofs are rejected until the end of the day, the jobs already created still run.
+	// The CPU time of a proof is measured by the Server (see cpuMeter), the MSM workers' isn't counted.
+	ProveCPUSecondsPerDay float64 `json:"proveCPUSecondsPerDay"`
+}
+
+func (l Limits) isZero() bool {
+	return l == Limits{}
+}
+
+// Quotas tracks the usage of the identities against their Limits
+//
+// the Authorizer checks the call rate and the proving time in its interceptors (see Authorizer.Quotas),
+// the Server checks the concurrent jobs and accounts the proving time (see WithQuotas).
+// The proving time of the day is persisted in the JobStore of the Server, and restored when it starts;
+// the call rate isn't persisted.
+// A nil *Quotas doesn't limit anything.
+type Quotas struct {
+	limits map[string]Limits
+	now    func() time.Time
+
+	lock  sync.Mutex
+	usage map[string]*usage
+
+	store     JobStore   // persists the proving time, set by the Server, see restore
+	storeLock sync.Mutex // serializes the saves
+}
+
+// usage of an identity with limits
+type usage struct {
+	calls           []time.Time // Prove and CreateProveJob calls of the last minute
+	running         int         // unfinished jobs and sync proofs
+	day             time.Time   // UTC day of proveCPUSeconds
+	proveCPUSeconds float64
+}
+
+func newQuotas(config AuthConfig) *Quotas {
+	q := &Quotas{
+		limits: make(map[string]Limits),
+		now:    time.Now,
+		usage:  make(map[string]*usage),
+	}
+	for identity, permissions := range config.Identities {
+		if !permissions.Limits.isZero() {
+			q.limits[identity] = permissions.Limits
+		}
+	}
+	return q
+}
+
+// get returns the limits and usage of identity, ok is false if it has no limits
+// must be called under lock
+func (q *Quotas) get(identity string) (limits Limits, u *usage, ok bool) {
+	if q == nil {
+		return
+	}
+	if limits, ok = q.limits[identity]; !ok {
+		return
+	}
+	if u = q.usage[identity]; u == nil {
+		u = &usage{}
+		q.usage[identity] = u
+	}
+	if day := q.now().UTC().Truncate(24 * time.Hour); !day.Equal(u.day) {
+		u.day = day
+		u.proveCPUSeconds = 0
+	}
+	return
+}
+
+// checkRate checks identity may call method (e.g. "Prove") now, and records the call
+// only the Prove and CreateProveJob calls are limited
+func (q *Quotas) checkRate(identity, method string) error {
+	if q == nil || (method != "Prove" && method != "CreateProveJob") {
+		return nil
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	limits, u, ok := q.get(identity)
+	if !ok {
+		return nil
+	}
+	if limits.ProveCPUSecondsPerDay > 0 && u.proveCPUSeconds >= limits.ProveCPUSecondsPerDay {
+		return status.Errorf(codes.ResourceExhausted, "%s used its %gs of proving CPU time for today", identity, limits.ProveCPUSecondsPerDay)
+	}
+	if limits.JobsPerMinute > 0 {
+		now := q.now()
+		i := 0
+		for i < len(u.calls) && now.Sub(u.calls[i]) >= time.Minute {
+			i++
+		}
+		u.calls = u.calls[i:]
+		if len(u.calls) >= limits.JobsPerMinute {
+			return status.Errorf(codes.ResourceExhausted, "%s is limited to %d jobs per minute", identity, limits.JobsPerMinute)
+		}
+		u.calls = append(u.calls, now)
+	}
+	return nil
+}
+
+// acquire reserves one of the concurrent jobs of identity, to be released with release
+func (q *Quotas) acquire(identity string) error {
+	if q == nil {
+		return nil
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	limits, u, ok := q.get(identity)
+	if !ok {
+		return nil
+	}
+	if limits.MaxConcurrentJobs > 0 && u.running >= limits.MaxConcurrentJobs {
+		return status.Errorf(codes.ResourceExhausted, "%s is limited to %d concurrent jobs", identity, limits.MaxConcurrentJobs)
+	}
+	u.running++
+	return nil
+}
+
+// hold reserves one of the concurrent jobs of identity, even if the limit is reached (restored jobs)
+func (q *Quotas) hold(identity string) {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, u, ok := q.get(identity); ok {
+		u.running++
+	}
+}
+
+// release frees a job reserved by acquire or hold
+func (q *Quotas) release(identity string) {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, u, ok := q.get(identity); ok && u.running > 0 {
+		u.running--
+	}
+}
+
+// addProveTime accounts CPU time spent proving for identity, and persists the proving time of the identities
+func (q *Quotas) addProveTime(identity string, cpu time.Duration) error {
+	if q == nil {
+		return nil
+	}
+	q.lock.Lock()
+	_, u, ok := q.get(identity)
+	if ok {
+		u.proveCPUSeconds += cpu.Seconds()
+	}
+	q.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	return q.save()
+}
+
+// QuotaUsage is the persisted usage of an identity, see JobStore
+type QuotaUsage struct {
+	Day             time.Time // UTC day of ProveCPUSeconds
+	ProveCPUSeconds float64
+}
+
+// restore loads the usage persisted in store, and persists it there from now on
+func (q *Quotas) restore(store JobStore) error {
+	if q == nil {
+		return nil
+	}
+	records, err := store.LoadUsage()
+	if err != nil {
+		return err
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.store = store
+	for identity, r := range records {
+		if _, ok := q.limits[identity]; !ok {
+			continue
+		}
+		if u := q.usage[identity]; u == nil {
+			q.usage[identity] = &usage{}
+		}
+		q.usage[identity].day, q.usage[identity].proveCPUSeconds = r.Day, r.ProveCPUSeconds
+	}
+	return nil
+}
+
+// save persists the usage of the identities in q.store, if set
+// the saves are serialized, and each one records the usage as it is once the previous one is done
+func (q *Quotas) save() error {
+	q.storeLock.Lock()
+	defer q.storeLock.Unlock()
+	q.lock.Lock()
+	store := q.store
+	records := make(map[string]QuotaUsage, len(q.usage))
+	for identity, u := range q.usage {
+		records[identity] = QuotaUsage{Day: u.day, ProveCPUSeconds: u.proveCPUSeconds}
+	}
+	q.lock.Unlock()
+	if store == nil {
+		return nil
+	}
+	return store.SaveUsage(records)
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestQuotas(t *testing.T) {
+	assert := require.New(t)
+
+	q := newQuotas(AuthConfig{Identities: map[string]Permissions{
+		"alice": {Limits: Limits{MaxConcurrentJobs: 2, JobsPerMinute: 3, ProveCPUSecondsPerDay: 60}},
+		"bob":   {},
+	}})
+	now := time.Date(2021, 12, 1, 23, 57, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	// jobs per minute, over a sliding window
+	for i := 0; i < 3; i++ {
+		assert.NoError(q.checkRate("alice", "CreateProveJob"))
+		now = now.Add(10 * time.Second)
+	}
+	assert.Equal(codes.ResourceExhausted, status.Code(q.checkRate("alice", "Prove")))
+	assert.NoError(q.checkRate("alice", "ListProveJob"), "only prove calls are limited")
+	now = now.Add(30 * time.Second)
+	assert.NoError(q.checkRate("alice", "Prove"))
+
+	// concurrent jobs
+	assert.NoError(q.acquire("alice"))
+	assert.NoError(q.acquire("alice"))
+	assert.Equal(codes.ResourceExhausted, status.Code(q.acquire("alice")))
+	q.release("alice")
+	assert.NoError(q.acquire("alice"))
+
+	// proving time, until the end of the UTC day
+	assert.NoError(q.addProveTime("alice", time.Minute))
+	now = now.Add(time.Minute)
+	assert.Equal(codes.ResourceExhausted, status.Code(q.checkRate("alice", "Prove")))
+	now = now.Add(time.Minute)
+	assert.NoError(q.checkRate("alice", "Prove"))
+
+	// the proving time is persisted, and restored
+	store := NewMemoryJobStore()
+	q2 := newQuotas(AuthConfig{Identities: map[string]Permissions{"alice": {Limits: Limits{ProveCPUSecondsPerDay: 60}}}})
+	q2.now = q.now
+	assert.NoError(q2.restore(store))
+	assert.NoError(q2.addProveTime("alice", time.Minute))
+	q3 := newQuotas(AuthConfig{Identities: map[string]Permissions{"alice": {Limits: Limits{ProveCPUSecondsPerDay: 60}}}})
+	q3.now = q.now
+	assert.NoError(q3.restore(store))
+	assert.Equal(codes.ResourceExhausted, status.Code(q3.checkRate("alice", "Prove")))
+
+	// identities without limits, and nil Quotas
+	for i := 0; i < 10; i++ {
+		assert.NoError(q.checkRate("bob", "Prove"))
+		assert.NoError(q.acquire("bob"))
+	}
+	var nilQuotas *Quotas
+	assert.NoError(nilQuotas.checkRate("alice", "Prove"))
+	assert.NoError(nilQuotas.acquire("alice"))
+}
+
+func TestQuotasServer(t *testing.T) {
+	assert := require.New(t)
+
+	authorizer, err := NewAuthorizer(AuthConfig{
+		Tokens: map[string]string{"alice-token": "alice"},
+		Identities: map[string]Permissions{
+			"alice": {Circuits: []string{"*"}, RPCs: []string{"*"}, Limits: Limits{MaxConcurrentJobs: 1, JobsPerMinute: 3}},
+		},
+	})
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gnarkd, err := NewServer(ctx, log, "../circuits", WithQuotas(authorizer.Quotas()))
+	assert.NoError(err)
+
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(authorizer.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(authorizer.StreamInterceptor()),
+	)
+	pb.RegisterGroth16Server(s, gnarkd)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+	client := pb.NewGroth16Client(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer alice-token")
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+
+	// a job waiting for its witness holds the only concurrent job of alice
+	r, err := client.CreateProveJob(alice, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+	_, err = client.CreateProveJob(alice, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.Equal(codes.ResourceExhausted, status.Code(err))
+
+	// once the job is finished, alice can prove again, within her rate
+	_, err = client.CancelProveJob(alice, &pb.CancelProveJobRequest{JobID: r.JobID})
+	assert.NoError(err)
+	_, err = client.Prove(alice, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.NoError(err)
+	_, err = client.Prove(alice, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Equal(codes.ResourceExhausted, status.Code(err), "4th prove call of the minute")
+}
+
+// serverStream is a grpc.ServerStream of a call with the given context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestQuotasStreamInterceptor(t *testing.T) {
+	assert := require.New(t)
+
+	authorizer, err := NewAuthorizer(AuthConfig{
+		Tokens: map[string]string{"alice-token": "alice"},
+		Identities: map[string]Permissions{
+			"alice": {Circuits: []string{"*"}, RPCs: []string{"*"}, Limits: Limits{JobsPerMinute: 1}},
+		},
+	})
+	assert.NoError(err)
+	interceptor := authorizer.StreamInterceptor()
+	ss := &serverStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer alice-token"))}
+	calls := 0
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		calls++
+		return nil
+	}
+
+	// the streaming calls are rate limited as the unary ones
+	info := &grpc.StreamServerInfo{FullMethod: "/gnarkd.Groth16/CreateProveJob"}
+	assert.NoError(interceptor(nil, ss, info, handler))
+	assert.Equal(codes.ResourceExhausted, status.Code(interceptor(nil, ss, info, handler)))
+	assert.Equal(1, calls)
+	assert.NoError(interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/gnarkd.Groth16/SubmitWitness"}, handler))
+	assert.Equal(2, calls)
+}
+
+func TestCPUMeter(t *testing.T) {
+	assert := require.New(t)
+
+	m := newCPUMeter()
+	p1 := m.start(1)
+	p2 := m.start(1)
+	start, _ := processCPUTime()
+	busy(50 * time.Millisecond)
+	cpu1 := m.stop(p1)
+	end, _ := processCPUTime()
+	cpu2 := m.stop(p2)
+
+	// the CPU time is split evenly between running proofs of the same weight
+	assert.True(cpu1 > 0)
+	assert.True(cpu1 <= end-start+time.Millisecond)
+	assert.InEpsilon(float64(cpu1), float64(cpu2), 0.1, "%s %s", cpu1, cpu2)
+
+	// by weight: a small proof running next to a large one is charged a small share
+	small, large := m.start(1), m.start(99)
+	busy(50 * time.Millisecond)
+	cpuSmall, cpuLarge := m.stop(small), m.stop(large)
+	assert.True(cpuLarge > 0)
+	assert.True(cpuSmall*50 <= cpuLarge, "small proof charged %s, large proof %s", cpuSmall, cpuLarge)
+}
+
+// busy uses the CPU for d
+func busy(d time.Duration) {
+	for x, deadline := 0, time.Now().Add(d); time.Now().Before(deadline); x++ {
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+// queuedJob is a job waiting in the scheduler
+type queuedJob struct {
+	id        jobID
+	circuitID string
+	priority  int32
+	seq       uint64 // insertion order, jobs with the same priority run in FIFO order
+	memory    int64  // estimated memory needed to prove the job
+}
+
+// scheduler holds the queued jobs and decides which one a worker runs next
+//
+// the next job is the one with the highest priority among the queued jobs that can be admitted:
+// its circuit must run less than its concurrency limit and its estimated memory must fit in the memory limit
+// next to the running jobs (a job always fits if no other job is running).
+// A job that can't be admitted doesn't block the jobs queued after it, except for the memory: the first job
+// (by priority, then FIFO) held back only by the memory limit reserves its memory, the jobs after it are
+// admitted if they leave room for it. Otherwise, a large job could wait forever behind a stream of small ones.
+type scheduler struct {
+	sync.Mutex
+	cond *sync.Cond
+
+	queue   []*queuedJob // by priority, then FIFO
+	seq     uint64
+	closed  bool
+	running map[string]int // circuitID -> number of running jobs
+	memory  int64          // estimated memory of the running jobs
+
+	maxMemory     int64          // 0 means no limit
+	maxJobs       int            // default max number of concurrent jobs per circuit, 0 means no limit
+	maxJobsPerCID map[string]int // max number of concurrent jobs for a given circuit
+}
+
+func newScheduler(maxMemory int64, maxJobs int, maxJobsPerCID map[string]int) *scheduler {
+	sc := &scheduler{
+		running:       make(map[string]int),
+		maxMemory:     maxMemory,
+		maxJobs:       maxJobs,
+		maxJobsPerCID: maxJobsPerCID,
+	}
+	sc.cond = sync.NewCond(sc)
+	return sc
+}
+
+// push queues a job
+func (sc *scheduler) push(job *queuedJob) {
+	sc.Lock()
+	job.seq = sc.seq
+	sc.seq++
+	// jobs with the same priority keep their insertion order
+	i := sort.Search(len(sc.queue), func(i int) bool { return sc.queue[i].priority < job.priority })
+	sc.queue = append(sc.queue, nil)
+	copy(sc.queue[i+1:], sc.queue[i:])
+	sc.queue[i] = job
+	sc.Unlock()
+	sc.cond.Broadcast()
+}
+
+// remove removes a job from the queue, returns false if the job wasn't queued
+func (sc *scheduler) remove(id jobID) bool {
+	sc.Lock()
+	defer sc.Unlock()
+	for i, job := range sc.queue {
+		if job.id == id {
+			sc.queue = append(sc.queue[:i], sc.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// next blocks until a job can be started, and marks it as running
+// returns false if the scheduler is closed
+func (sc *scheduler) next() (*queuedJob, bool) {
+	sc.Lock()
+	defer sc.Unlock()
+	for {
+		if sc.closed {
+			return nil, false
+		}
+		if i := sc.selectJob(); i != -1 {
+			job := sc.queue[i]
+			sc.queue = append(sc.queue[:i], sc.queue[i+1:]...)
+			sc.running[job.circuitID]++
+			sc.memory += job.memory
+			return job, true
+		}
+		sc.cond.Wait()
+	}
+}
+
+// done marks a job returned by next as finished
+func (sc *scheduler) done(job *queuedJob) {
+	sc.Lock()
+	sc.running[job.circuitID]--
+	if sc.running[job.circuitID] == 0 {
+		delete(sc.running, job.circuitID)
+	}
+	sc.memory -= job.memory
+	sc.Unlock()
+	sc.cond.Broadcast()
+}
+
+// close wakes up the workers waiting in next
+func (sc *scheduler) close() {
+	sc.Lock()
+	sc.closed = true
+	sc.Unlock()
+	sc.cond.Broadcast()
+}
+
+// len returns the number of queued jobs
+func (sc *scheduler) len() int {
+	sc.Lock()
+	defer sc.Unlock()
+	return len(sc.queue)
+}
+
+// runningMemory returns the estimated memory of the running jobs
+func (sc *scheduler) runningMemory() int64 {
+	sc.Lock()
+	defer sc.Unlock()
+	return sc.memory
+}
+
+// selectJob returns the index of the next job to run in sc.queue, or -1 if no job can be admitted
+// must be called under lock
+func (sc *scheduler) selectJob() int {
+	var reserved int64 // memory of the first job held back by the memory limit
+	reserving := false
+	for i, job := range sc.queue {
+		if !sc.belowConcurrencyLimit(job) {
+			continue
+		}
+		if sc.fitsMemory(job, reserved) {
+			return i
+		}
+		if !reserving {
+			reserved, reserving = job.memory, true
+		}
+	}
+	return -1
+}
+
+// must be called under lock
+func (sc *scheduler) belowConcurrencyLimit(job *queuedJob) bool {
+	maxJobs := sc.maxJobs
+	if m, ok := sc.maxJobsPerCID[job.circuitID]; ok {
+		maxJobs = m
+	}
+	return maxJobs <= 0 || sc.running[job.circuitID] < maxJobs
+}
+
+// fitsMemory returns true if the job fits in the memory limit next to the running jobs and the reserved memory
+// must be called under lock
+func (sc *scheduler) fitsMemory(job *queuedJob, reserved int64) bool {
+	if sc.maxMemory <= 0 || len(sc.running) == 0 {
+		return true
+	}
+	return sc.memory+reserved+job.memory <= sc.maxMemory
+}
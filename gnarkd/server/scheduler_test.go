@@ -0,0 +1,134 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPriority(t *testing.T) {
+	assert := require.New(t)
+
+	sc := newScheduler(0, 0, nil)
+	low1 := &queuedJob{id: uuid.New(), circuitID: "a"}
+	high := &queuedJob{id: uuid.New(), circuitID: "a", priority: 2}
+	low2 := &queuedJob{id: uuid.New(), circuitID: "b"}
+	sc.push(low1)
+	sc.push(high)
+	sc.push(low2)
+
+	// highest priority first, then FIFO
+	for _, expected := range []*queuedJob{high, low1, low2} {
+		job, ok := sc.next()
+		assert.True(ok)
+		assert.Equal(expected.id, job.id)
+	}
+	assert.Equal(0, sc.len())
+}
+
+func TestSchedulerAdmission(t *testing.T) {
+	assert := require.New(t)
+
+	sc := newScheduler(100, 1, map[string]int{"small": 2})
+	big1 := &queuedJob{id: uuid.New(), circuitID: "big", memory: 80, priority: 1}
+	big2 := &queuedJob{id: uuid.New(), circuitID: "big", memory: 80, priority: 1}
+	small1 := &queuedJob{id: uuid.New(), circuitID: "small", memory: 10}
+	small2 := &queuedJob{id: uuid.New(), circuitID: "small", memory: 10}
+	small3 := &queuedJob{id: uuid.New(), circuitID: "small", memory: 10}
+	for _, job := range []*queuedJob{big1, big2, small1, small2, small3} {
+		sc.push(job)
+	}
+
+	next := func() *queuedJob {
+		job, ok := sc.next()
+		assert.True(ok)
+		return job
+	}
+
+	// big2 can't run next to big1 (concurrency and memory), the small jobs don't wait for it
+	assert.Equal(big1.id, next().id)
+	assert.Equal(small1.id, next().id)
+	assert.Equal(small2.id, next().id)
+
+	// small3 exceeds the concurrency limit of its circuit, big2 still doesn't fit
+	chNext := make(chan *queuedJob, 1)
+	go func() {
+		chNext <- next()
+	}()
+	select {
+	case job := <-chNext:
+		t.Fatalf("job %s shouldn't be admitted", job.circuitID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// once a small job is done, small3 can run
+	sc.done(small1)
+	assert.Equal(small3.id, (<-chNext).id)
+
+	// big2 starts once big1 is done
+	go func() {
+		chNext <- next()
+	}()
+	sc.done(big1)
+	assert.Equal(big2.id, (<-chNext).id)
+
+	// a closed scheduler releases the waiting workers
+	go func() {
+		_, ok := sc.next()
+		assert.False(ok)
+		chNext <- nil
+	}()
+	sc.close()
+	assert.Nil(<-chNext)
+}
+
+func TestSchedulerMemoryReservation(t *testing.T) {
+	assert := require.New(t)
+
+	sc := newScheduler(100, 0, nil)
+	small1 := &queuedJob{id: uuid.New(), circuitID: "small", memory: 30}
+	sc.push(small1)
+	job, ok := sc.next()
+	assert.True(ok)
+	assert.Equal(small1.id, job.id)
+
+	// big doesn't fit next to small1, it reserves its memory: small2 would leave no room for it
+	big := &queuedJob{id: uuid.New(), circuitID: "big", memory: 80, priority: 1}
+	small2 := &queuedJob{id: uuid.New(), circuitID: "small", memory: 30}
+	sc.push(big)
+	sc.push(small2)
+
+	chNext := make(chan *queuedJob, 1)
+	go func() {
+		job, _ := sc.next()
+		chNext <- job
+	}()
+	select {
+	case job := <-chNext:
+		t.Fatalf("job %s shouldn't be admitted", job.circuitID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// big starts once small1 is done, then small2 waits for it
+	sc.done(small1)
+	assert.Equal(big.id, (<-chNext).id)
+	go func() {
+		job, _ := sc.next()
+		chNext <- job
+	}()
+	sc.done(big)
+	assert.Equal(small2.id, (<-chNext).id)
+}
+
+func TestSchedulerRemove(t *testing.T) {
+	assert := require.New(t)
+
+	sc := newScheduler(0, 0, nil)
+	job := &queuedJob{id: uuid.New(), circuitID: "a"}
+	sc.push(job)
+	assert.True(sc.remove(job.id))
+	assert.False(sc.remove(job.id))
+	assert.Equal(0, sc.len())
+}
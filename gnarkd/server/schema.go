@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/gnarkd/manifest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EncodeWitness returns the binary witness of the named values, using the witness schema of the circuit
+// full == false returns a public witness. Errors are gRPC status errors
+func (s *Server) EncodeWitness(circuitID string, values map[string]string, full bool) ([]byte, error) {
+	circuit, ok := s.lookupCircuit(circuitID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", circuitID)
+	}
+	if circuit.schema == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "circuit %s has no witness schema", circuitID)
+	}
+	w, err := encodeWitness(circuit.schema, circuit.curveID, values, full)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return w, nil
+}
+
+// encodeWitness returns the binary witness (see backend/witness) of the named values of the schema
+// all the variables of the schema must be set, values are parsed by witness.ParseValue: decimal or 0x prefixed
+// hexadecimal integers in [0, modulus), values out of range are refused. full == false returns a public witness
+func encodeWitness(schema *manifest.WitnessSchema, curveID ecc.ID, values map[string]string, full bool) ([]byte, error) {
+	frSize := frModulusSize(curveID)
+	names := schema.Public
+	if full {
+		names = append(append([]string{}, schema.Public...), schema.Secret...)
+	}
+	if len(values) != len(names) {
+		for name := range values {
+			if !contains(names, name) {
+				return nil, fmt.Errorf("unknown variable %s", name)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.BigEndian, uint32(len(names))); err != nil {
+		return nil, err
+	}
+	b := make([]byte, frSize)
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("variable %s not assigned", name)
+		}
+		v, err := witness.ParseValue(curveID, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		v.FillBytes(b)
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+func contains(s []string, value string) bool {
+	for _, v := range s {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// frModulusSize returns the size of an encoded field element
+func frModulusSize(curveID ecc.ID) int {
+	return len(witness.FrModulus(curveID).Bytes())
+}
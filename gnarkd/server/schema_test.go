@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+type nestedCircuit struct {
+	X      frontend.Variable `gnark:"x"`
+	Y      frontend.Variable `gnark:",public"`
+	Path   [2]struct{ Sibling frontend.Variable }
+	Proofs [2][2]frontend.Variable `gnark:",public"`
+}
+
+func (c *nestedCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return nil
+}
+
+func TestWitnessSchemaNames(t *testing.T) {
+	assert := require.New(t)
+
+	// the schema names the variables as the JSON witnesses do
+	schema, err := manifest.NewWitnessSchema(&nestedCircuit{})
+	assert.NoError(err)
+	assert.Equal([]string{"Y", "Proofs[0][0]", "Proofs[0][1]", "Proofs[1][0]", "Proofs[1][1]"}, schema.Public)
+	assert.Equal([]string{"x", "Path[0].Sibling", "Path[1].Sibling"}, schema.Secret)
+
+	var assignment nestedCircuit
+	assignment.X.Assign(3)
+	assignment.Y.Assign(35)
+	assignment.Path[0].Sibling.Assign(1)
+	assignment.Path[1].Sibling.Assign(2)
+	for i := range assignment.Proofs {
+		for j := range assignment.Proofs[i] {
+			assignment.Proofs[i][j].Assign(10*i + j)
+		}
+	}
+	var expected bytes.Buffer
+	_, err = witness.WriteFullTo(&expected, ecc.BN254, &assignment)
+	assert.NoError(err)
+
+	values := map[string]string{
+		"x": "3", "Y": "35", "Path[0].Sibling": "1", "Path[1].Sibling": "2",
+		"Proofs[0][0]": "0", "Proofs[0][1]": "1", "Proofs[1][0]": "10", "Proofs[1][1]": "11",
+	}
+	encoded, err := encodeWitness(&schema, ecc.BN254, values, true)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), encoded)
+
+	// values out of [0, modulus) are refused, not reduced
+	for _, invalid := range []string{"-1", "21888242871839275222246405745257275088548364400416034343698204186575808495618", "0b11", "1_1"} {
+		values["x"] = invalid
+		_, err = encodeWitness(&schema, ecc.BN254, values, true)
+		assert.Error(err, invalid)
+	}
+}
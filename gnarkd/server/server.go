@@ -22,45 +22,166 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/consensys/gnark-crypto/ecc"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/manifest"
 	"github.com/consensys/gnark/gnarkd/pb"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
 )
 
 const (
-	gcTicker     = time.Minute * 2 // gc running periodically
-	defaultTTL   = time.Hour * 3   // default TTL for keeping jobs in Server.jobs
-	jobQueueSize = 10
+	gcTicker   = time.Minute * 2 // gc running periodically
+	defaultTTL = time.Hour * 3   // default TTL for keeping jobs in Server.jobs
 )
 
 var (
-	errJobExpired   = errors.New("job expired")
-	errJobCancelled = errors.New("job cancelled")
+	errJobExpired      = errors.New("job expired")
+	errJobCancelled    = errors.New("job cancelled")
+	errCircuitUnloaded = errors.New("circuit unloaded")
 )
 
-// Server implements Groth16Server
+// curves supported by gnarkd, circuits are stored in circuitDir/curve/name
+var curves = []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761}
+
+// Server implements Groth16Server, see Server.Plonk() for the PlonkServer
 type Server struct {
 	pb.UnimplementedGroth16Server
-	circuits   map[string]circuit // not thread safe as it is loaded once only
-	jobs       sync.Map           // key == uuid[string], value == proveJob
-	chJobQueue chan jobID
-	log        *zap.SugaredLogger
-	circuitDir string
-	ctx        context.Context
+	circuits      map[string]circuit // circuits can be registered and unloaded at runtime, see Server.Circuits()
+	circuitsLock  sync.RWMutex       // protects circuits
+	jobs          sync.Map           // key == uuid[string], value == proveJob
+	store         JobStore           // persists the jobs, s.jobs is restored from it at start
+	scheduler     *scheduler         // queued jobs, run by the workers
+	log           *zap.SugaredLogger
+	circuitDir    string
+	ctx           context.Context
+	metrics       *metrics
+	registerer    prometheus.Registerer // registers s.metrics, if set
+	cache         *circuitCache         // proving data of the recently used circuits
+	cacheSize     int64
+	quotas        *Quotas     // limits of the job owners, if set
+	cpu           *cpuMeter   // CPU time of the proofs, accounted in quotas
+	msmWorkers    *msmWorkers // computes the multi-exponentiations of the bn254 Groth16 proofs, if set
+	insecurePlonk bool        // PLONK circuits are loaded, see WithInsecurePlonk
+
+	// size limits of the circuits received by RegisterCircuit, see WithCircuitUploadLimits
+	maxCircuitFileSize   int64
+	maxCircuitUploadSize int64
+
+	// graceful shutdown, see Server.Shutdown
+	shutdownOnce    sync.Once
+	drainingLock    sync.RWMutex   // held to close draining and set callbacksClosed, read locked by startSyncProof and notifyFinished
+	draining        chan struct{}  // closed when the Server stops accepting jobs
+	drained         chan struct{}  // closed once the running jobs are done
+	workers         sync.WaitGroup // running workers
+	syncProofs      sync.WaitGroup // running synchronous proofs
+	callbacks       sync.WaitGroup // pending callbacks
+	callbacksClosed bool           // set once Shutdown waits for callbacks, no callback is started after
+
+	// job results callbacks
+	callbackSecret    []byte        // signs the callback requests, if set
+	callbackBackoff   time.Duration // delay before the first retry of a callback
+	callbackHosts     []string      // hosts of the allowed http(s) callback URLs, see WithCallbacks
+	callbackSocketDir string        // directory of the allowed unix callback sockets
+
+	// worker pool configuration
+	nbWorkers     int
+	maxMemory     int64
+	maxJobs       int
+	maxJobsPerCID map[string]int
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithJobStore sets the store persisting the jobs of the Server
+// default store keeps the jobs in memory (see NewMemoryJobStore)
+func WithJobStore(store JobStore) Option {
+	return func(s *Server) {
+		s.store = store
+	}
+}
+
+// WithMetrics registers the prometheus metrics of the Server in reg
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(s *Server) {
+		s.registerer = reg
+	}
+}
+
+// WithCircuitCacheSize sets the memory budget of the proving keys and constraint systems kept in memory
+// they are read from the circuit files when needed; beyond the budget, the least recently used are evicted.
+// The memory of a circuit is estimated by the size of its files (.pk and .r1cs, or .spr and .pcs), the proving key
+// counting twice: its points are stored compressed.
+// default is 0 (no limit, a circuit stays in memory once used)
+func WithCircuitCacheSize(bytes int64) Option {
+	return func(s *Server) {
+		s.cacheSize = bytes
+	}
+}
+
+// WithWorkers sets the number of workers proving jobs concurrently, default is 1
+func WithWorkers(nbWorkers int) Option {
+	return func(s *Server) {
+		s.nbWorkers = nbWorkers
+	}
+}
+
+// WithMemoryLimit sets the memory available to the workers: a job doesn't start if its estimated memory
+// and the one of the running jobs exceed the limit (a job always starts if no other job is running)
+// the estimated memory of a job is the size of the proving key file of its circuit (.pk for Groth16, .spr for PLONK)
+// default is 0 (no limit)
+func WithMemoryLimit(bytes int64) Option {
+	return func(s *Server) {
+		s.maxMemory = bytes
+	}
+}
+
+// WithMaxConcurrentJobs sets the maximum number of jobs of a circuit running concurrently
+// if circuitID is empty, sets the default for all circuits. Default is 0 (no limit)
+func WithMaxConcurrentJobs(circuitID string, maxJobs int) Option {
+	return func(s *Server) {
+		if circuitID == "" {
+			s.maxJobs = maxJobs
+			return
+		}
+		if s.maxJobsPerCID == nil {
+			s.maxJobsPerCID = make(map[string]int)
+		}
+		s.maxJobsPerCID[circuitID] = maxJobs
+	}
+}
+
+// WithQuotas enforces the concurrent jobs of the Limits tracked by quotas, and accounts their proving time
+// quotas is shared with the Authorizer of the gRPC services, see Authorizer.Quotas
+func WithQuotas(quotas *Quotas) Option {
+	return func(s *Server) {
+		s.quotas = quotas
+	}
+}
+
+// WithInsecurePlonk loads the PLONK circuits, served by the Plonk service (see Server.Plonk)
+// gnark-crypto only provides a mock polynomial commitment scheme for now, whose opening proofs aren't checked:
+// PLONK proofs are NOT sound, a forged proof verifies. Without this option, PLONK circuits are refused.
+func WithInsecurePlonk() Option {
+	return func(s *Server) {
+		s.insecurePlonk = true
+	}
 }
 
 // NewServer returns a server implementing the service as defined in pb/gnarkd.proto
-func NewServer(ctx context.Context, log *zap.SugaredLogger, circuitDir string) (*Server, error) {
+func NewServer(ctx context.Context, log *zap.SugaredLogger, circuitDir string, options ...Option) (*Server, error) {
 	if log == nil {
 		return nil, errors.New("please provide a logger")
 	}
@@ -68,25 +189,104 @@ func NewServer(ctx context.Context, log *zap.SugaredLogger, circuitDir string) (
 		ctx:        ctx,
 		log:        log,
 		circuitDir: circuitDir,
+		nbWorkers:  1,
+		metrics:    newMetrics(),
+		cpu:        newCPUMeter(),
+		draining:   make(chan struct{}),
+		drained:    make(chan struct{}),
+
+		callbackBackoff: callbackBackoff,
+
+		maxCircuitFileSize:   defaultMaxCircuitFileSize,
+		maxCircuitUploadSize: defaultMaxCircuitUploadSize,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.store == nil {
+		s.store = NewMemoryJobStore()
 	}
+	if err := s.quotas.restore(s.store); err != nil {
+		return nil, err
+	}
+	s.cache = newCircuitCache(s.cacheSize)
 	if err := s.loadCircuits(); err != nil {
 		return nil, err
 	}
-	s.chJobQueue = make(chan jobID, jobQueueSize)
-	go s.startWorker(ctx)
+	if s.nbWorkers < 1 {
+		return nil, errors.New("the number of workers must be positive")
+	}
+	s.scheduler = newScheduler(s.maxMemory, s.maxJobs, s.maxJobsPerCID)
+	if s.registerer != nil {
+		if err := s.registerMetrics(s.registerer); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.restoreJobs(); err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		s.scheduler.close()
+	}()
+	s.workers.Add(s.nbWorkers)
+	for i := 0; i < s.nbWorkers; i++ {
+		go s.startWorker(i)
+	}
 	go s.startGC(ctx)
+	if s.msmWorkers != nil {
+		s.msmWorkers.list(ctx, s.log)
+		go s.msmWorkers.run(ctx, s.log)
+	}
 	return s, nil
 }
 
-// StartWitnessListener listen on given socket for incoming connection
-// and read and try to interpret stream of bytes as a circuit witness
-func (s *Server) StartWitnessListener(l net.Listener) {
-	for {
-		c, err := l.Accept()
-		if err != nil {
-			s.log.Fatalw("couldn't accept connection on witness tcp socket", "err", err)
+// restoreJobs loads the jobs from s.store
+// jobs that were QUEUED or RUNNING when the server stopped are queued again
+func (s *Server) restoreJobs() error {
+	records, err := s.store.LoadAll()
+	if err != nil {
+		return err
+	}
+	nbQueued := 0
+	for _, r := range records {
+		if _, ok := s.lookupCircuit(r.CircuitID); !ok {
+			s.log.Warnw("dropping stored job of an unknown circuit", "jobID", r.ID.String(), "circuitID", r.CircuitID)
+			if err := s.store.Delete(r.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		job := newProveJob(r)
+		if job.status == pb.ProveJobResult_RUNNING {
+			// the proof computation was interrupted
+			job.status = pb.ProveJobResult_QUEUED
+		}
+		s.jobs.Store(job.id, job)
+		if !job.isFinished() {
+			s.quotas.hold(job.owner)
+		}
+		if job.status == pb.ProveJobResult_QUEUED {
+			s.queueJob(job)
+			nbQueued++
 		}
-		go s.receiveWitness(c)
+	}
+	s.log.Infow("restored jobs", "nbJobs", len(records), "nbQueued", nbQueued)
+	return nil
+}
+
+// saveJob persists the job in s.store, errors are logged
+// the saves of a job are serialized, and each one records the job as it is once the previous one is done:
+// the last record written is the latest state of the job.
+// will lock job.
+func (s *Server) saveJob(job *proveJob) {
+	job.saveLock.Lock()
+	defer job.saveLock.Unlock()
+	job.RLock()
+	r := job.record()
+	job.RUnlock()
+	if err := s.store.Save(r); err != nil {
+		s.log.Errorw("couldn't save job", "jobID", r.ID.String(), "err", err)
 	}
 }
 
@@ -106,6 +306,9 @@ func (s *Server) startGC(ctx context.Context) {
 				if s.isExpired(job) {
 					s.log.Infow("job TTL expired", "jobID", job.id.String())
 					s.jobs.Delete(job.id)
+					if err := s.store.Delete(job.id); err != nil {
+						s.log.Errorw("couldn't delete job", "jobID", job.id.String(), "err", err)
+					}
 				}
 				return true
 			})
@@ -113,168 +316,220 @@ func (s *Server) startGC(ctx context.Context) {
 	}
 }
 
-// worker executes groth16 prove async calls (listens to s.chJobQueue)
-func (s *Server) startWorker(ctx context.Context) {
-	s.log.Info("starting worker")
-	var buf bytes.Buffer
+// queueJob adds a QUEUED job to the scheduler
+// job fields read here are immutable
+func (s *Server) queueJob(job *proveJob) {
+	// if the circuit was unloaded, the job fails when a worker picks it up
+	circuit, _ := s.lookupCircuit(job.circuitID)
+	s.scheduler.push(&queuedJob{
+		id:        job.id,
+		circuitID: job.circuitID,
+		priority:  job.priority,
+		memory:    circuit.estimatedMemory,
+	})
+}
+
+// worker executes groth16 and plonk prove async calls (runs the jobs selected by s.scheduler)
+func (s *Server) startWorker(id int) {
+	defer s.workers.Done()
+	s.log.Infow("starting worker", "worker", id)
 	for {
-		select {
-		case <-ctx.Done():
-			s.log.Info("stopping worker (context is Done())")
+		queued, ok := s.scheduler.next()
+		if !ok {
+			s.log.Infow("stopping worker", "worker", id)
 			return
-		case jobID, ok := <-s.chJobQueue:
-			if !ok {
-				s.log.Info("stopping worker (s.chJobQueue is closed)")
-				return
-			}
-			s.log.Infow("executing job", "jobID", jobID)
-
-			_job, ok := s.jobs.Load(jobID)
-			if !ok {
-				s.log.Errorw("inconsistant Server state: received a job in the job queue, that's not in the job sync.Map", "jobID", jobID)
-				continue
-			}
-			job := _job.(*proveJob)
-
-			if s.isExpired(job) {
-				s.log.Warnw("job TTL expired", "jobID", job.id.String())
-				continue
-			}
-
-			s.updateJobStatusOrDie(job, pb.ProveJobResult_RUNNING)
-
-			// note that job.witness and job.prove can only be accessed by this go routine at this point
-			circuit, ok := s.circuits[job.circuitID]
-			if !ok {
-				s.log.Fatalw("inconsistant Server state: couldn't find circuit pointed by job", "jobID", jobID.String(), "circuitID", job.circuitID)
-			}
-
-			// run prove
-			proof, err := groth16.ReadAndProve(circuit.r1cs, circuit.pk, bytes.NewReader(job.witness))
-			job.witness = nil // set witness to nil
-			if err != nil {
-				s.log.Errorw("proving job failed", "jobID", jobID.String(), "circuitID", job.circuitID, "err", err)
-				job.err = err
-				s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
-				continue
-			}
-
-			// serialize proof
-			buf.Reset()
-			_, err = proof.WriteTo(&buf)
-			if err != nil {
-				s.log.Errorw("couldn't serialize proof", "err", err)
-				job.err = err
-				s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
-				continue
-			}
-
-			s.log.Infow("successfully computed proof", "jobID", job.id)
-			job.proof = buf.Bytes()
-			s.updateJobStatusOrDie(job, pb.ProveJobResult_COMPLETED)
 		}
+		s.runJob(queued.id)
+		s.scheduler.done(queued)
 	}
 }
 
-func (s *Server) isExpired(job *proveJob) bool {
-	job.Lock()
-	defer job.Unlock()
+func (s *Server) runJob(jobID jobID) {
+	s.log.Infow("executing job", "jobID", jobID)
 
-	if job.expiration.Before(time.Now()) {
-		job.status = pb.ProveJobResult_ERRORED
-		job.err = errJobExpired
+	_job, ok := s.jobs.Load(jobID)
+	if !ok {
+		s.log.Errorw("inconsistant Server state: received a job in the job queue, that's not in the job sync.Map", "jobID", jobID)
+		return
+	}
+	job := _job.(*proveJob)
+
+	// the job may have been cancelled or may have expired since it was queued: its status is checked and
+	// set under the same lock, a job that isn't queued anymore is skipped
+	job.Lock()
+	status := job.status
+	if status == pb.ProveJobResult_QUEUED && !job.expiration.Before(time.Now()) {
+		job.status = pb.ProveJobResult_RUNNING
 		for _, ch := range job.subscribers {
 			ch <- struct{}{}
 		}
-		return true
 	}
-	return false
-}
-
-func (s *Server) updateJobStatusOrDie(job *proveJob, status pb.ProveJobResult_Status) {
-	if err := job.setStatus(status); err != nil {
-		s.log.Fatalw("when updating job status", "err", err, "jobID", job.id.String())
+	job.Unlock()
+	if status != pb.ProveJobResult_QUEUED {
+		s.log.Infow("job is not queued anymore", "jobID", job.id.String(), "status", status.String())
+		return
 	}
-}
-
-func (s *Server) receiveWitness(c net.Conn) {
-	s.log.Infow("receiving a witness", "remoteAddr", c.RemoteAddr().String())
-
-	// success handler
-	success := func() {
-		if _, err := c.Write([]byte("ok")); err != nil {
-			s.log.Errorw("when responding OK on witness socket", "err", err)
-		}
-		if err := c.Close(); err != nil {
-			s.log.Errorw("when closing", "err", err)
-		}
+	if s.isExpired(job) {
+		s.log.Warnw("job TTL expired", "jobID", job.id.String())
+		s.saveJob(job)
+		return
 	}
+	s.saveJob(job)
 
-	// fail handler
-	fail := func(err error) {
-		s.log.Errorw("receive witness failed", "err", err)
-		if _, err := c.Write([]byte("nok")); err != nil {
-			s.log.Errorw("when responding NOK on witness socket", "err", err)
-		}
-		if err := c.Close(); err != nil {
-			s.log.Errorw("when closing", "err", err)
-		}
+	// note that job.witness and job.prove can only be accessed by this go routine at this point
+	circuit, ok := s.lookupCircuit(job.circuitID)
+	if !ok {
+		s.log.Errorw("circuit of the job was unloaded", "jobID", jobID.String(), "circuitID", job.circuitID)
+		job.witness = nil
+		job.err = errCircuitUnloaded
+		s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
+		return
 	}
 
-	// read jobID
-	var bufJobID [jobIDSize]byte
-	if _, err := io.ReadFull(c, bufJobID[:]); err != nil {
-		fail(err)
+	data, err := s.provingData(job.circuitID, circuit)
+	if err != nil {
+		s.log.Errorw("couldn't read circuit proving data", "jobID", jobID.String(), "circuitID", job.circuitID, "err", err)
+		job.witness = nil
+		job.err = err
+		s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
 		return
 	}
 
-	// parse jobid
-	var jobID uuid.UUID
-	if err := jobID.UnmarshalBinary(bufJobID[:]); err != nil {
-		fail(err)
+	// run prove
+	start, cpu := time.Now(), s.cpu.start(proofWeight(circuit))
+	proof, err := s.prove(s.ctx, job.circuitID, circuit, data, job.witness)
+	s.metrics.observeProve(job.circuitID, start)
+	s.addProveTime(job.owner, s.cpu.stop(cpu))
+	job.witness = nil // set witness to nil
+	if err != nil {
+		s.log.Errorw("proving job failed", "jobID", jobID.String(), "circuitID", job.circuitID, "err", err)
+		job.err = err
+		job.solverErr = circuit.solverError(err)
+		s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
 		return
 	}
 
-	// find job
-	_job, ok := s.jobs.Load(jobID)
-	if !ok {
-		fail(fmt.Errorf("unknown jobID %s", jobID.String()))
+	// serialize proof
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	if err != nil {
+		s.log.Errorw("couldn't serialize proof", "err", err)
+		job.err = err
+		s.updateJobStatusOrDie(job, pb.ProveJobResult_ERRORED)
 		return
 	}
 
-	// check job status
-	job := _job.(*proveJob)
-	job.Lock()
-	if job.status != pb.ProveJobResult_WAITING_WITNESS {
-		job.Unlock()
-		fail(fmt.Errorf("job is not waiting for witness, jobID %s", jobID.String()))
-		return
+	s.log.Infow("successfully computed proof", "jobID", job.id)
+	job.proof = buf.Bytes()
+	s.updateJobStatusOrDie(job, pb.ProveJobResult_COMPLETED)
+}
+
+// prove runs the proving scheme of the circuit with the binary encoded full witness
+// the multi-exponentiations of a bn254 Groth16 circuit whose proving key is split are computed by the MSM workers
+// ctx cancels the calls to the MSM workers
+func (s *Server) prove(ctx context.Context, circuitID string, circuit circuit, data *provingData, witness []byte) (io.WriterTo, error) {
+	if circuit.backendID == backend.PLONK {
+		return plonk.ReadAndProve(data.spr, data.scheme, bytes.NewReader(witness))
 	}
+	if pk, ok := data.pk.(*groth16_bn254.ProvingKey); ok && isSplit(pk) {
+		if s.msmWorkers == nil {
+			return nil, fmt.Errorf("the proving key of %s is split, and gnarkd has no MSM workers", circuitID)
+		}
+		chunks, err := s.msmWorkers.find(circuitID)
+		if err != nil {
+			return nil, err
+		}
+		return s.msmWorkers.prove(ctx, circuitID, chunks, data, circuit.vk.(*groth16_bn254.VerifyingKey), witness)
+	}
+	return groth16.ReadAndProve(data.r1cs, data.pk, bytes.NewReader(witness))
+}
 
-	// /!\  keeping the lock on the job while we get the witness /!\
+// addProveTime accounts the CPU time of a proof in the quotas of its owner, errors are logged
+func (s *Server) addProveTime(owner string, cpu time.Duration) {
+	if err := s.quotas.addProveTime(owner, cpu); err != nil {
+		s.log.Errorw("couldn't save quota usage", "owner", owner, "err", err)
+	}
+}
 
-	circuit, ok := s.circuits[job.circuitID]
-	if !ok {
-		s.log.Fatalw("inconsistant Server state: couldn't find circuit pointed by job", "jobID", jobID.String(), "circuitID", job.circuitID)
+// provingData returns the provingData of the circuit, from s.cache or read from its files
+// the constraint system already cached by constraintSystem is reused, and evicted: the provingData holds it
+func (s *Server) provingData(circuitID string, circuit circuit) (*provingData, error) {
+	return s.cache.get(circuitID, circuit.provingDataSize, func() (*provingData, error) {
+		s.log.Infow("reading circuit proving data", "circuitID", circuitID)
+		var cs frontend.CompiledConstraintSystem
+		if data := s.cache.peek(csCacheKey(circuitID)); data != nil {
+			cs = data.cs()
+		}
+		data, err := circuit.readProvingData(cs)
+		if err == nil {
+			s.cache.remove(csCacheKey(circuitID))
+		}
+		return data, err
+	})
+}
+
+// constraintSystem returns the constraint system of the circuit, to validate witnesses
+// it is taken from the cached provingData if any, otherwise only the constraint system file is read (and cached
+// on its own): validating a witness doesn't read the proving key, which is read when a job is run.
+func (s *Server) constraintSystem(circuitID string, circuit circuit) (frontend.CompiledConstraintSystem, error) {
+	if data := s.cache.peek(circuitID); data != nil {
+		return data.cs(), nil
+	}
+	data, err := s.cache.get(csCacheKey(circuitID), circuit.csSize, func() (*provingData, error) {
+		s.log.Infow("reading circuit constraint system", "circuitID", circuitID)
+		return circuit.readConstraintSystem()
+	})
+	if err != nil {
+		return nil, err
 	}
+	return data.cs(), nil
+}
 
-	wBuf := make([]byte, circuit.fullWitnessSize)
-	if _, err := io.ReadFull(c, wBuf); err != nil {
-		job.Unlock()
-		fail(err)
-		return
+// csCacheKey is the key of the constraint system of the circuit in s.cache, see constraintSystem
+// circuit names can't contain a "/", it can't be the ID of another circuit
+func csCacheKey(circuitID string) string {
+	return circuitID + "/cs"
+}
+
+// isExpired marks the job as ERRORED if its TTL expired, and returns true if so
+// a RUNNING job doesn't expire before its worker finishes it
+func (s *Server) isExpired(job *proveJob) bool {
+	job.Lock()
+	defer job.Unlock()
+
+	if job.status != pb.ProveJobResult_RUNNING && job.expiration.Before(time.Now()) {
+		wasFinished := job.isFinished()
+		job.status = pb.ProveJobResult_ERRORED
+		job.err = errJobExpired
+		job.witness = nil
+		for _, ch := range job.subscribers {
+			ch <- struct{}{}
+		}
+		if !wasFinished {
+			s.notifyFinished(job)
+		}
+		return true
 	}
-	job.witness = wBuf
-	job.Unlock()
-	s.updateJobStatusOrDie(job, pb.ProveJobResult_QUEUED)
-	s.chJobQueue <- jobID // queue the job
+	return false
+}
 
-	success()
+// updateJobStatusOrDie updates the job status and persists the job
+func (s *Server) updateJobStatusOrDie(job *proveJob, status pb.ProveJobResult_Status) {
+	if err := job.setStatus(status); err != nil {
+		s.log.Fatalw("when updating job status", "err", err, "jobID", job.id.String())
+	}
+	s.saveJob(job)
+	job.RLock()
+	if job.isFinished() {
+		s.notifyFinished(job)
+	}
+	job.RUnlock()
 }
 
-// loadCircuits walk through s.circuitDir and caches proving keys, verifying keys, and R1CS
-// path must be circuits/curveXX/circuitName/ and contains exactly one of each .pk, .vk and .R1CS
-// TODO @gbotrel caching strategy, v1 caches everything.
+// loadCircuits walk through s.circuitDir and reads the metadata and verifying keys of the circuits
+// path must be circuits/curveXX/circuitName/ and contains exactly one of each .pk, .vk and .R1CS (Groth16)
+// or exactly one of each .spr and .pcs (PLONK)
+// proving keys and constraint systems are read when needed, and kept in s.cache
 func (s *Server) loadCircuits() error {
 	s.circuits = make(map[string]circuit)
 	// ensure root dir exists
@@ -285,7 +540,6 @@ func (s *Server) loadCircuits() error {
 		return err
 	}
 
-	curves := []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761}
 	for _, curve := range curves {
 		curveDir := filepath.Join(s.circuitDir, curve.String())
 
@@ -317,14 +571,44 @@ func (s *Server) loadCircuit(curveID ecc.ID, baseDir string) error {
 	circuitID := fmt.Sprintf("%s/%s", curveID.String(), filepath.Base(baseDir))
 	s.log.Debugw("looking for circuit in", "dir", circuitID)
 
+	circuit, err := readCircuit(curveID, baseDir)
+	if err != nil {
+		return err
+	}
+	if circuit.backendID == backend.PLONK && !s.insecurePlonk {
+		s.log.Warnw("skipping PLONK circuit, PLONK proofs are insecure (mock polynomial commitment scheme)", "circuitID", circuitID)
+		return nil
+	}
+
+	s.circuitsLock.Lock()
+	s.circuits[circuitID] = circuit
+	s.circuitsLock.Unlock()
+
+	s.log.Infow("successfully loaded circuit", "circuitID", circuitID)
+
+	return nil
+}
+
+// lookupCircuit returns the loaded circuit with given ID
+func (s *Server) lookupCircuit(circuitID string) (circuit, bool) {
+	s.circuitsLock.RLock()
+	circuit, ok := s.circuits[circuitID]
+	s.circuitsLock.RUnlock()
+	return circuit, ok
+}
+
+// readCircuit reads the circuit metadata from the files in baseDir, see loadCircuits for the expected files
+// the proving keys aren't read, see circuit.readProvingData; the constraint system is only read
+// if the circuit has no manifest, see circuit.readSizes
+func readCircuit(curveID ecc.ID, baseDir string) (circuit, error) {
 	// list files in dir
 	files, err := ioutil.ReadDir(baseDir)
 	if err != nil {
-		return err
+		return circuit{}, err
 	}
 
 	// empty circuit with nil values
-	var circuit circuit
+	circuit := circuit{curveID: curveID, dir: baseDir}
 
 	for _, f := range files {
 		if f.IsDir() {
@@ -332,60 +616,106 @@ func (s *Server) loadCircuit(curveID ecc.ID, baseDir string) error {
 		}
 		switch filepath.Ext(f.Name()) {
 		case pkExt:
-			if circuit.pk != nil {
-				return fmt.Errorf("%s contains multiple %s files", baseDir, pkExt)
-			}
-			circuit.pk = groth16.NewProvingKey(curveID)
-			if err := loadGnarkObject(circuit.pk, filepath.Join(baseDir, f.Name())); err != nil {
-				return err
+			if circuit.pkFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, pkExt)
 			}
+			circuit.pkFile = f.Name()
+			circuit.estimatedMemory = f.Size()
+			circuit.provingDataSize += pkMemoryFactor * f.Size()
 		case vkExt:
-			if circuit.vk != nil {
-				return fmt.Errorf("%s contains multiple %s files", baseDir, pkExt)
-			}
-			circuit.vk = groth16.NewVerifyingKey(curveID)
-			if err := loadGnarkObject(circuit.vk, filepath.Join(baseDir, f.Name())); err != nil {
-				return err
+			if circuit.vkFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, vkExt)
 			}
+			circuit.vkFile = f.Name()
 		case r1csExt:
-			if circuit.r1cs != nil {
-				return fmt.Errorf("%s contains multiple %s files", baseDir, pkExt)
+			if circuit.r1csFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, r1csExt)
 			}
-			circuit.r1cs = groth16.NewCS(curveID)
-			if err := loadGnarkObject(circuit.r1cs, filepath.Join(baseDir, f.Name())); err != nil {
-				return err
+			circuit.r1csFile = f.Name()
+			circuit.provingDataSize += f.Size()
+			circuit.csSize = f.Size()
+		case sprExt:
+			if circuit.sprFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, sprExt)
+			}
+			circuit.sprFile = f.Name()
+			circuit.estimatedMemory = f.Size()
+			circuit.provingDataSize += f.Size()
+			circuit.csSize = f.Size()
+		case schemaExt:
+			if circuit.schemaFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, schemaExt)
+			}
+			circuit.schemaFile = f.Name()
+		case pcsExt:
+			if circuit.pcsFile != "" {
+				return circuit, fmt.Errorf("%s contains multiple %s files", baseDir, pcsExt)
 			}
+			circuit.pcsFile = f.Name()
+			circuit.provingDataSize += f.Size()
 		}
 	}
 
-	// ensure our circuit is full.
-	if circuit.pk == nil {
+	// a circuit directory contains either a Groth16 or a PLONK circuit
+	if circuit.r1csFile != "" && circuit.sprFile != "" {
+		return circuit, fmt.Errorf("%s contains both %s and %s files", baseDir, r1csExt, sprExt)
+	}
+	if circuit.sprFile != "" {
+		if err := checkPlonkCircuit(&circuit, baseDir); err != nil {
+			return circuit, err
+		}
+	} else if err := checkGroth16Circuit(&circuit, baseDir); err != nil {
+		return circuit, err
+	}
+	if err := circuit.readSizes(); err != nil {
+		return circuit, err
+	}
+	if circuit.vkFile != "" {
+		circuit.vk = groth16.NewVerifyingKey(curveID)
+		if err := circuit.loadFile(circuit.vk, circuit.vkFile); err != nil {
+			return circuit, err
+		}
+	}
+	if circuit.schemaFile != "" {
+		err := circuit.readFile(circuit.schemaFile, func(r io.Reader) (err error) {
+			circuit.schema, err = manifest.ReadWitnessSchema(r)
+			return
+		})
+		if err != nil {
+			return circuit, fmt.Errorf("%s: %w", filepath.Join(baseDir, circuit.schemaFile), err)
+		}
+	}
+	if circuit.schema != nil && 4+circuit.schema.Size()*frModulusSize(curveID) != circuit.fullWitnessSize {
+		return circuit, fmt.Errorf("%s: the witness schema doesn't match the constraint system", baseDir)
+	}
+
+	return circuit, nil
+}
+
+func checkGroth16Circuit(circuit *circuit, baseDir string) error {
+	if circuit.pcsFile != "" {
+		return fmt.Errorf("%s contains a %s file but no %s files", baseDir, pcsExt, sprExt)
+	}
+	if circuit.pkFile == "" {
 		return fmt.Errorf("%s contains no %s files", baseDir, pkExt)
 	}
-	if circuit.vk == nil {
+	if circuit.vkFile == "" {
 		return fmt.Errorf("%s contains no %s files", baseDir, vkExt)
 	}
-	if circuit.r1cs == nil {
+	if circuit.r1csFile == "" {
 		return fmt.Errorf("%s contains no %s files", baseDir, r1csExt)
 	}
-
-	_, nbSecretVariables, nbPublicVariables := circuit.r1cs.GetNbVariables()
-	circuit.publicWitnessSize = 4 + int(nbPublicVariables-1)*circuit.r1cs.FrSize()
-	circuit.fullWitnessSize = 4 + int(nbPublicVariables+nbSecretVariables-1)*circuit.r1cs.FrSize()
-
-	s.circuits[circuitID] = circuit
-
-	s.log.Infow("successfully loaded circuit", "circuitID", circuitID)
-
+	circuit.backendID = backend.GROTH16
 	return nil
 }
 
-func loadGnarkObject(o io.ReaderFrom, filePath string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return err
+func checkPlonkCircuit(circuit *circuit, baseDir string) error {
+	if circuit.pkFile != "" || circuit.vkFile != "" {
+		return fmt.Errorf("%s contains Groth16 keys (%s, %s) and a %s file", baseDir, pkExt, vkExt, sprExt)
+	}
+	if circuit.pcsFile == "" {
+		return fmt.Errorf("%s contains no %s files", baseDir, pcsExt)
 	}
-	_, err = o.ReadFrom(file)
-	file.Close()
-	return err
+	circuit.backendID = backend.PLONK
+	return nil
 }
@@ -3,8 +3,10 @@ package server
 import (
 	"bytes"
 	context "context"
+	"io"
 	"time"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/gnarkd/pb"
 	"github.com/google/uuid"
@@ -18,41 +20,81 @@ import (
 // it is meant to be used for small circuits, for larger circuits (proving time) and witnesses,
 // use CreateProveJob instead
 func (s *Server) Prove(ctx context.Context, request *pb.ProveRequest) (*pb.ProveResult, error) {
+	return s.syncProve(ctx, request, backend.GROTH16)
+}
+
+// syncProve implements the Prove RPC of the Groth16 and Plonk services
+func (s *Server) syncProve(ctx context.Context, request *pb.ProveRequest, backendID backend.ID) (*pb.ProveResult, error) {
 	s.log.Debugw("Prove", "circuitID", request.CircuitID)
+	if err := s.startSyncProof(); err != nil {
+		return nil, err
+	}
+	defer s.syncProofs.Done()
 
 	// get circuit
-	circuit, ok := s.circuits[request.CircuitID]
-	if !ok {
-		s.log.Errorw("Prove called with unknown circuitID", "circuitID", request.CircuitID)
-		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", request.CircuitID)
+	circuit, err := s.getCircuit(request.CircuitID, backendID)
+	if err != nil {
+		s.log.Errorw("Prove called with invalid circuitID", "circuitID", request.CircuitID, "err", err)
+		return nil, err
 	}
 
-	// call groth16.Prove with witness
-	proof, err := groth16.ReadAndProve(circuit.r1cs, circuit.pk, bytes.NewReader(request.Witness))
+	if err := checkWitnessSize(circuit, request.Witness); err != nil {
+		return nil, err
+	}
+
+	// a sync proof counts as a job of the caller while it runs, the quota is checked before any work is done
+	owner := identityFromContext(ctx)
+	if err := s.quotas.acquire(owner); err != nil {
+		return nil, err
+	}
+	defer s.quotas.release(owner)
+
+	data, err := s.provingData(request.CircuitID, circuit)
 	if err != nil {
-		s.log.Error(err)
-		return nil, status.Errorf(codes.Internal, err.Error())
+		s.log.Errorw("couldn't read circuit proving data", "circuitID", request.CircuitID, "err", err)
+		return nil, status.Errorf(codes.Internal, "couldn't read circuit %s", request.CircuitID)
 	}
 
-	// serialize proof
+	// the prover solves the witness, a witness which doesn't solve the circuit returns a structured error
+	start, cpu := time.Now(), s.cpu.start(proofWeight(circuit))
+	proof, err := s.prove(ctx, request.CircuitID, circuit, data, request.Witness)
+	s.metrics.observeProve(request.CircuitID, start)
+	s.addProveTime(owner, s.cpu.stop(cpu))
+	if err != nil {
+		return nil, s.proveError(request.CircuitID, circuit, err)
+	}
+
+	return s.proveResult(request.CircuitID, proof)
+}
+
+// proveResult serializes the proof in a ProveResult
+func (s *Server) proveResult(circuitID string, proof io.WriterTo) (*pb.ProveResult, error) {
 	var buf bytes.Buffer
-	_, err = proof.WriteTo(&buf)
+	_, err := proof.WriteTo(&buf)
 	if err != nil {
 		s.log.Error(err)
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
 	// return proof
-	s.log.Infow("successfully created proof", "circuitID", request.CircuitID)
+	s.log.Infow("successfully created proof", "circuitID", circuitID)
 	return &pb.ProveResult{Proof: buf.Bytes()}, nil
 }
 
 // CreateProveJob enqueue a job into the job queue with WAITING_WITNESS status
 func (s *Server) CreateProveJob(ctx context.Context, request *pb.CreateProveJobRequest) (*pb.CreateProveJobResponse, error) {
+	return s.createProveJob(ctx, request, backend.GROTH16)
+}
+
+func (s *Server) createProveJob(ctx context.Context, request *pb.CreateProveJobRequest, backendID backend.ID) (*pb.CreateProveJobResponse, error) {
+	if err := s.checkAccepting(); err != nil {
+		return nil, err
+	}
+
 	// ensure circuitID is valid
-	if _, ok := s.circuits[request.CircuitID]; !ok {
-		s.log.Errorw("CreateProveJob called with unknown circuitID", "circuitID", request.CircuitID)
-		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", request.CircuitID)
+	if _, err := s.getCircuit(request.CircuitID, backendID); err != nil {
+		s.log.Errorw("CreateProveJob called with invalid circuitID", "circuitID", request.CircuitID, "err", err)
+		return nil, err
 	}
 
 	ttl := defaultTTL
@@ -60,65 +102,245 @@ func (s *Server) CreateProveJob(ctx context.Context, request *pb.CreateProveJobR
 		ttl = time.Duration(*request.TTL) * time.Second
 	}
 
+	if request.CallbackURL != nil {
+		if _, err := s.parseCallbackURL(*request.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	owner := identityFromContext(ctx)
+	if err := s.quotas.acquire(owner); err != nil {
+		return nil, err
+	}
+
 	// create job
 	job := proveJob{
-		id:         uuid.New(),
-		status:     pb.ProveJobResult_WAITING_WITNESS, // default value
-		expiration: time.Now().Add(ttl),
-		circuitID:  request.CircuitID,
+		id:          uuid.New(),
+		status:      pb.ProveJobResult_WAITING_WITNESS, // default value
+		expiration:  time.Now().Add(ttl),
+		circuitID:   request.CircuitID,
+		backendID:   backendID,
+		priority:    request.GetPriority(),
+		owner:       owner,
+		callbackURL: request.GetCallbackURL(),
 	}
 
-	// store job, waiting for witness via TCP socket
+	// store job, waiting for witness via SubmitWitness
 	s.jobs.Store(job.id, &job)
+	s.saveJob(&job)
 	s.log.Infow("prove job created", "circuitID", request.CircuitID, "jobID", job.id, "expiration", job.expiration.String())
 
 	// return job id
 	return &pb.CreateProveJobResponse{JobID: job.id.String()}, nil
 }
 
-// CancelProveJob does what it says it does.
-func (s *Server) CancelProveJob(ctx context.Context, request *pb.CancelProveJobRequest) (*pb.CancelProveJobResponse, error) {
-	// ensure jobID is valid
-	jobID, err := uuid.Parse(request.JobID)
+// SubmitWitness receives the witness of a job created by CreateProveJob and queues the job
+func (s *Server) SubmitWitness(stream pb.Groth16_SubmitWitnessServer) error {
+	return s.submitWitness(stream, backend.GROTH16)
+}
+
+// witnessStream is implemented by pb.Groth16_SubmitWitnessServer and pb.Plonk_SubmitWitnessServer
+type witnessStream interface {
+	Recv() (*pb.WitnessChunk, error)
+	SendAndClose(*pb.SubmitWitnessResponse) error
+	Context() context.Context
+}
+
+func (s *Server) submitWitness(stream witnessStream, backendID backend.ID) error {
+	if err := s.checkAccepting(); err != nil {
+		return err
+	}
+
+	// the first chunk sets the jobID
+	chunk, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "didn't receive any witness")
+	}
 	if err != nil {
-		s.log.Errorw("invalid job id", "jobID", request.JobID)
-		return nil, status.Errorf(codes.InvalidArgument, "invalid jobID %s", request.JobID)
+		s.log.Errorw("SubmitWitness stream failed", "err", err)
+		s.metrics.witnessFailures.WithLabelValues(witnessStreamFailed).Inc()
+		return err
 	}
-	_job, ok := s.jobs.Load(jobID)
+	job, err := s.getJob(stream.Context(), chunk.JobID, backendID)
+	if err != nil {
+		s.log.Errorw("SubmitWitness called with invalid jobID", "jobID", chunk.JobID, "err", err)
+		s.metrics.witnessFailures.WithLabelValues(witnessInvalidJob).Inc()
+		return err
+	}
+	s.log.Infow("receiving a witness", "jobID", chunk.JobID)
+
+	// the witness is received and validated without holding the lock of the job, which is only taken to change
+	// its status: the job may be cancelled, or expire, in the meantime
+	job.RLock()
+	jobStatus := job.status
+	job.RUnlock()
+	if jobStatus != pb.ProveJobResult_WAITING_WITNESS {
+		s.log.Errorw("job is not waiting for a witness", "jobID", chunk.JobID, "status", jobStatus.String())
+		s.metrics.witnessFailures.WithLabelValues(witnessInvalidJob).Inc()
+		return status.Errorf(codes.FailedPrecondition, "job %s is not waiting for a witness", chunk.JobID)
+	}
+
+	circuit, ok := s.lookupCircuit(job.circuitID)
 	if !ok {
-		s.log.Errorw("CancelProveJobRequest called with unknown jobID", "jobID", request.JobID)
-		return nil, status.Errorf(codes.NotFound, "unknown job %s", request.JobID)
+		s.failWaitingJob(job, errCircuitUnloaded, nil)
+		s.log.Errorw("circuit of the job was unloaded", "jobID", chunk.JobID, "circuitID", job.circuitID)
+		s.metrics.witnessFailures.WithLabelValues(witnessInvalidJob).Inc()
+		return status.Errorf(codes.FailedPrecondition, "circuit %s of job %s was unloaded", job.circuitID, chunk.JobID)
+	}
+
+	witness, err := readWitness(stream, chunk.Data, circuit.fullWitnessSize)
+	if err != nil {
+		s.log.Errorw("receive witness failed", "jobID", chunk.JobID, "err", err)
+		reason := witnessStreamFailed
+		if status.Code(err) == codes.InvalidArgument {
+			reason = witnessInvalidSize
+		}
+		s.metrics.witnessFailures.WithLabelValues(reason).Inc()
+		return err
+	}
+
+	// validate the witness before queuing the job, a bad witness fails the job right away
+	cs, err := s.constraintSystem(job.circuitID, circuit)
+	if err != nil {
+		s.log.Errorw("couldn't read circuit constraint system", "jobID", chunk.JobID, "circuitID", job.circuitID, "err", err)
+		return status.Errorf(codes.Internal, "couldn't read circuit %s", job.circuitID)
+	}
+	if err := circuit.isSolved(cs, witness); err != nil {
+		serr := circuit.solverError(err)
+		s.failWaitingJob(job, err, serr)
+		s.log.Errorw("invalid witness", "jobID", chunk.JobID, "circuitID", job.circuitID, "err", err)
+		s.metrics.witnessFailures.WithLabelValues(witnessUnsatisfied).Inc()
+		return witnessStatus(err, serr)
+	}
+
+	job.Lock()
+	if job.status != pb.ProveJobResult_WAITING_WITNESS {
+		jobStatus = job.status
+		job.Unlock()
+		s.log.Errorw("job stopped waiting for its witness", "jobID", chunk.JobID, "status", jobStatus.String())
+		s.metrics.witnessFailures.WithLabelValues(witnessInvalidJob).Inc()
+		return status.Errorf(codes.FailedPrecondition, "job %s is not waiting for a witness", chunk.JobID)
+	}
+	job.witness = witness
+	job.status = pb.ProveJobResult_QUEUED
+	for _, ch := range job.subscribers {
+		ch <- struct{}{}
+	}
+	job.Unlock()
+	s.saveJob(job)
+	s.queueJob(job)
+
+	return stream.SendAndClose(&pb.SubmitWitnessResponse{})
+}
+
+// failWaitingJob sets a job waiting for its witness as ERRORED, and persists it
+// the job is left as is if it isn't waiting for its witness anymore (cancelled or expired)
+// serr is set if the witness doesn't solve the circuit. will lock job.
+func (s *Server) failWaitingJob(job *proveJob, err error, serr *pb.SolverError) {
+	job.Lock()
+	if job.status != pb.ProveJobResult_WAITING_WITNESS {
+		job.Unlock()
+		return
+	}
+	s.failJob(job, err, serr)
+	job.Unlock()
+	s.saveJob(job)
+}
+
+// failJob sets a job waiting for its witness as ERRORED
+// serr is set if the witness doesn't solve the circuit. must be called under lock
+func (s *Server) failJob(job *proveJob, err error, serr *pb.SolverError) {
+	job.status = pb.ProveJobResult_ERRORED
+	job.err = err
+	job.solverErr = serr
+	for _, ch := range job.subscribers {
+		ch <- struct{}{}
+	}
+	s.notifyFinished(job)
+}
+
+// readWitness reads the chunks of stream, following the first one, into a witness of given size
+func readWitness(stream witnessStream, first []byte, size int) ([]byte, error) {
+	witness := make([]byte, 0, size)
+	data := first
+	for {
+		if len(witness)+len(data) > size {
+			return nil, status.Errorf(codes.InvalidArgument, "witness is larger than the expected %d bytes", size)
+		}
+		witness = append(witness, data...)
+
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = chunk.Data
+	}
+	if len(witness) != size {
+		return nil, status.Errorf(codes.InvalidArgument, "received a %d bytes witness, expected %d bytes", len(witness), size)
+	}
+	return witness, nil
+}
+
+// CancelProveJob does what it says it does.
+func (s *Server) CancelProveJob(ctx context.Context, request *pb.CancelProveJobRequest) (*pb.CancelProveJobResponse, error) {
+	return s.cancelProveJob(ctx, request, backend.GROTH16)
+}
+
+func (s *Server) cancelProveJob(ctx context.Context, request *pb.CancelProveJobRequest, backendID backend.ID) (*pb.CancelProveJobResponse, error) {
+	job, err := s.getJob(ctx, request.JobID, backendID)
+	if err != nil {
+		s.log.Errorw("CancelProveJobRequest called with invalid jobID", "jobID", request.JobID, "err", err)
+		return nil, err
 	}
 
-	job := _job.(*proveJob)
 	job.Lock()
-	defer job.Unlock()
 	if job.isFinished() {
+		job.Unlock()
 		return &pb.CancelProveJobResponse{}, nil
 	}
 
 	if job.status == pb.ProveJobResult_RUNNING {
+		job.Unlock()
 		s.log.Warnw("cancel job called on a running job, doing nothing", "jobID", request.JobID)
 		return nil, status.Errorf(codes.OutOfRange, "job %s can't be cancelled -- already RUNNING", request.JobID)
 	}
 
 	s.log.Infow("cancelling job", "jobID", request.JobID, "previousStatus", job.status.String())
+	if job.status == pb.ProveJobResult_QUEUED {
+		s.scheduler.remove(job.id)
+	}
 	job.err = errJobCancelled
 	job.status = pb.ProveJobResult_ERRORED
+	job.witness = nil
 	for _, ch := range job.subscribers {
 		ch <- struct{}{}
 	}
+	s.notifyFinished(job)
+	job.Unlock()
+	s.saveJob(job)
 
 	return &pb.CancelProveJobResponse{}, nil
 }
 
 // ListProveJob does what it says it does
 func (s *Server) ListProveJob(ctx context.Context, request *pb.ListProveJobRequest) (*pb.ListProveJobResponse, error) {
+	return s.listProveJob(ctx, backend.GROTH16), nil
+}
+
+// listProveJob lists the jobs of the caller
+func (s *Server) listProveJob(ctx context.Context, backendID backend.ID) *pb.ListProveJobResponse {
 	response := &pb.ListProveJobResponse{}
+	owner := identityFromContext(ctx)
 	s.jobs.Range(func(k, v interface{}) bool {
 		job := v.(*proveJob)
+		if job.backendID != backendID || job.owner != owner {
+			return true
+		}
 		job.RLock()
-		r := &pb.ProveJobResult{JobID: job.id.String(), Status: job.status}
+		r := &pb.ProveJobResult{JobID: job.id.String(), Status: job.status, SolverError: job.solverErr}
 		if job.err != nil {
 			errMsg := job.err.Error()
 			r.Err = &errMsg
@@ -127,27 +349,30 @@ func (s *Server) ListProveJob(ctx context.Context, request *pb.ListProveJobReque
 		response.Jobs = append(response.Jobs, r)
 		return true
 	})
-	return response, nil
+	return response
 }
 
 // SubscribeToProveJob enables a client to get job status changes from the Server
 // at connection start, Server sends current job status
 // when job is done (ok or errored), Server closes connection
 func (s *Server) SubscribeToProveJob(request *pb.SubscribeToProveJobRequest, stream pb.Groth16_SubscribeToProveJobServer) error {
-	// ensure jobID is valid
-	jobID, err := uuid.Parse(request.JobID)
+	return s.subscribeToProveJob(request, stream, backend.GROTH16)
+}
+
+// jobResultStream is implemented by pb.Groth16_SubscribeToProveJobServer and pb.Plonk_SubscribeToProveJobServer
+type jobResultStream interface {
+	Send(*pb.ProveJobResult) error
+	Context() context.Context
+}
+
+func (s *Server) subscribeToProveJob(request *pb.SubscribeToProveJobRequest, stream jobResultStream, backendID backend.ID) error {
+	job, err := s.getJob(stream.Context(), request.JobID, backendID)
 	if err != nil {
-		s.log.Errorw("invalid job id", "jobID", request.JobID)
-		return status.Errorf(codes.InvalidArgument, "invalid jobID %s", request.JobID)
-	}
-	_job, ok := s.jobs.Load(jobID)
-	if !ok {
-		s.log.Errorw("SubscribeToProveJob called with unknown jobID", "jobID", request.JobID)
-		return status.Errorf(codes.NotFound, "unknown job %s", request.JobID)
+		s.log.Errorw("SubscribeToProveJob called with invalid jobID", "jobID", request.JobID, "err", err)
+		return err
 	}
 
 	// check job status
-	job := _job.(*proveJob)
 	chJobUpdate := make(chan struct{}, 2)
 	job.Lock()
 	jobFinished := job.isFinished()
@@ -160,12 +385,7 @@ func (s *Server) SubscribeToProveJob(request *pb.SubscribeToProveJobRequest, str
 	// job is done we don't need to subscribe and just send the result, close the conn.
 	if jobFinished {
 		close(chJobUpdate)
-		result := &pb.ProveJobResult{JobID: request.JobID, Status: job.status, Proof: job.proof}
-		if job.err != nil {
-			errMsg := job.err.Error()
-			result.Err = &errMsg
-		}
-		if err := stream.Send(result); err != nil {
+		if err := stream.Send(job.result()); err != nil {
 			s.log.Errorw("couldn't send response of finished job", "jobID", request.JobID, "err", err)
 			return status.Errorf(codes.Internal, "couldn't send response of finished job")
 		}
@@ -188,17 +408,27 @@ func (s *Server) SubscribeToProveJob(request *pb.SubscribeToProveJobRequest, str
 		case <-s.ctx.Done():
 			s.log.Warnw("server stopping, closing client connection", "jobID", request.JobID)
 			return grpc.ErrServerStopped
+		case <-s.drained:
+			// the job won't change anymore on this server, the client may follow it on the next one
+			job.RLock()
+			result := job.result()
+			jobFinished := job.isFinished()
+			job.RUnlock()
+			if err := stream.Send(result); err != nil {
+				s.log.Errorw("couldn't send job status", "jobID", request.JobID, "err", err)
+			}
+			if jobFinished {
+				return nil
+			}
+			s.log.Infow("server shut down, closing job stream", "jobID", request.JobID, "status", result.Status.String())
+			return status.Errorf(codes.Unavailable, "gnarkd is shutting down, job %s is %s", request.JobID, result.Status.String())
 		case <-stream.Context().Done():
 			s.log.Warnw("connection terminated", "jobID", request.JobID)
 			return status.Error(codes.Canceled, "connection terminated")
 		case _, ok := <-chJobUpdate:
 			// job status updated.
 			job.RLock()
-			result := &pb.ProveJobResult{JobID: request.JobID, Status: job.status, Proof: job.proof}
-			if job.err != nil {
-				errMsg := job.err.Error()
-				result.Err = &errMsg
-			}
+			result := job.result()
 			jobFinished := job.isFinished()
 			job.RUnlock()
 
@@ -224,19 +454,21 @@ func (s *Server) Verify(ctx context.Context, request *pb.VerifyRequest) (*pb.Ver
 	s.log.Debugw("Verify", "circuitID", request.CircuitID)
 
 	// get circuit
-	circuit, ok := s.circuits[request.CircuitID]
-	if !ok {
-		s.log.Errorw("Verify called with unknown circuitID", "circuitID", request.CircuitID)
-		return nil, status.Errorf(codes.NotFound, "unknown circuit %s", request.CircuitID)
+	circuit, err := s.getCircuit(request.CircuitID, backend.GROTH16)
+	if err != nil {
+		s.log.Errorw("Verify called with invalid circuitID", "circuitID", request.CircuitID, "err", err)
+		return nil, err
 	}
 
 	// call groth16.Verify with witness
-	proof := groth16.NewProof(circuit.r1cs.CurveID())
+	proof := groth16.NewProof(circuit.curveID)
 	if _, err := proof.ReadFrom(bytes.NewReader(request.Proof)); err != nil {
 		s.log.Error(err)
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
 	}
-	err := groth16.ReadAndVerify(proof, circuit.vk, bytes.NewReader(request.PublicWitness))
+	start := time.Now()
+	err = groth16.ReadAndVerify(proof, circuit.vk, bytes.NewReader(request.PublicWitness))
+	s.metrics.observeVerify(request.CircuitID, start)
 	if err != nil {
 		s.log.Error(err)
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
@@ -246,3 +478,115 @@ func (s *Server) Verify(ctx context.Context, request *pb.VerifyRequest) (*pb.Ver
 	s.log.Infow("successfully verified proof", "circuitID", request.CircuitID)
 	return &pb.VerifyResult{Ok: true}, nil
 }
+
+// maxBatchVerify is the max number of proofs of a BatchVerify call
+const maxBatchVerify = 1024
+
+// BatchVerify takes circuitID and (proof, public witness) pairs as parameter, and returns the result of each proof
+// the pairing checks are batched, which is cheaper than calling Verify for each proof
+// this is a synchronous call
+func (s *Server) BatchVerify(ctx context.Context, request *pb.BatchVerifyRequest) (*pb.BatchVerifyResult, error) {
+	s.log.Debugw("BatchVerify", "circuitID", request.CircuitID, "proofs", len(request.Proofs))
+	if len(request.Proofs) > maxBatchVerify {
+		return nil, status.Errorf(codes.InvalidArgument, "too many proofs, got %d, max %d", len(request.Proofs), maxBatchVerify)
+	}
+
+	// get circuit
+	circuit, err := s.getCircuit(request.CircuitID, backend.GROTH16)
+	if err != nil {
+		s.log.Errorw("BatchVerify called with invalid circuitID", "circuitID", request.CircuitID, "err", err)
+		return nil, err
+	}
+
+	// proofs which can't be decoded are left out of the batch
+	errs := make([]error, len(request.Proofs))
+	var (
+		batch           []int
+		proofs          []groth16.Proof
+		publicWitnesses []io.Reader
+	)
+	for i, p := range request.Proofs {
+		proof := groth16.NewProof(circuit.curveID)
+		if _, errs[i] = proof.ReadFrom(bytes.NewReader(p.Proof)); errs[i] != nil {
+			continue
+		}
+		batch = append(batch, i)
+		proofs = append(proofs, proof)
+		publicWitnesses = append(publicWitnesses, bytes.NewReader(p.PublicWitness))
+	}
+
+	// call groth16.ReadAndBatchVerify with the witnesses
+	start := time.Now()
+	batchErrs, err := groth16.ReadAndBatchVerify(proofs, circuit.vk, publicWitnesses)
+	s.metrics.observeBatchVerify(request.CircuitID, start)
+	if err != nil {
+		s.log.Error(err)
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	for j, i := range batch {
+		errs[i] = batchErrs[j]
+	}
+
+	result := &pb.BatchVerifyResult{Results: make([]*pb.ProofVerifyResult, len(errs))}
+	nbValid := 0
+	for i, err := range errs {
+		result.Results[i] = &pb.ProofVerifyResult{Ok: err == nil}
+		if err != nil {
+			errMsg := err.Error()
+			result.Results[i].Err = &errMsg
+		} else {
+			nbValid++
+		}
+	}
+	s.log.Infow("batch verified", "circuitID", request.CircuitID, "proofs", len(errs), "valid", nbValid)
+	return result, nil
+}
+
+var backendNames = map[backend.ID]string{
+	backend.GROTH16: "Groth16",
+	backend.PLONK:   "PLONK",
+}
+
+// getCircuit returns the circuit with given ID if it uses the given backend, or a gRPC status error
+func (s *Server) getCircuit(circuitID string, backendID backend.ID) (circuit, error) {
+	circuit, ok := s.lookupCircuit(circuitID)
+	if !ok {
+		return circuit, status.Errorf(codes.NotFound, "unknown circuit %s", circuitID)
+	}
+	if circuit.backendID != backendID {
+		return circuit, status.Errorf(codes.InvalidArgument, "circuit %s is not a %s circuit", circuitID, backendNames[backendID])
+	}
+	return circuit, nil
+}
+
+// LookupProveJob returns a NotFound error if the Groth16 job isn't visible to the client of ctx
+// it lets a frontend of the Server (e.g. the HTTP gateway) check a job before calling a streaming RPC
+func (s *Server) LookupProveJob(ctx context.Context, jobID string) error {
+	if _, err := s.getJob(ctx, jobID, backend.GROTH16); err != nil {
+		// invalid jobIDs are unknown jobs too
+		return status.Errorf(codes.NotFound, "unknown job %s", jobID)
+	}
+	return nil
+}
+
+// getJob returns the job with given ID if it uses the given backend and belongs to the caller, or a gRPC status error
+func (s *Server) getJob(ctx context.Context, id string, backendID backend.ID) (*proveJob, error) {
+	// ensure jobID is valid
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid jobID %s", id)
+	}
+	_job, ok := s.jobs.Load(jobID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown job %s", id)
+	}
+	job := _job.(*proveJob)
+	if job.backendID != backendID {
+		return nil, status.Errorf(codes.NotFound, "unknown %s job %s", backendNames[backendID], id)
+	}
+	// jobs of other identities are hidden
+	if job.owner != identityFromContext(ctx) {
+		return nil, status.Errorf(codes.NotFound, "unknown job %s", id)
+	}
+	return job, nil
+}
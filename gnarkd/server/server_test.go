@@ -12,6 +12,7 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/examples/cubic"
 	"github.com/consensys/gnark/gnarkd/pb"
@@ -19,16 +20,17 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
 const bufSize = 1024 * 1024
 
 var (
-	grpcListener    *bufconn.Listener
-	witnessListener *bufconn.Listener
-	cancelServer    context.CancelFunc
-	gnarkdServer    *Server
+	grpcListener *bufconn.Listener
+	cancelServer context.CancelFunc
+	gnarkdServer *Server
 )
 
 // -------------------------------------------------------------------------------------------------
@@ -50,20 +52,19 @@ func init() {
 
 func setupServer() {
 	grpcListener = bufconn.Listen(bufSize)
-	witnessListener = bufconn.Listen(bufSize)
 	s := grpc.NewServer()
 
 	var serverCtx context.Context
 	var err error
 	serverCtx, cancelServer = context.WithCancel(context.Background())
-	gnarkdServer, err = NewServer(serverCtx, log, "../circuits")
+	gnarkdServer, err = NewServer(serverCtx, log, "../circuits", WithInsecurePlonk())
 	if err != nil {
 		log.Fatalw("couldn't init gnarkd", "err", err)
 	}
 
-	// start witness listener
-	go gnarkdServer.StartWitnessListener(witnessListener)
 	pb.RegisterGroth16Server(s, gnarkdServer)
+	pb.RegisterPlonkServer(s, gnarkdServer.Plonk())
+	pb.RegisterCircuitsServer(s, gnarkdServer.Circuits())
 
 	go func() {
 		if err := s.Serve(grpcListener); err != nil {
@@ -74,14 +75,38 @@ func setupServer() {
 
 func shutdownServer() {
 	grpcListener.Close()
-	witnessListener.Close()
 	cancelServer()
 	cancelServer = nil
 	grpcListener = nil
-	witnessListener = nil
 	gnarkdServer = nil
 }
 
+// witnessClientStream is implemented by pb.Groth16_SubmitWitnessClient and pb.Plonk_SubmitWitnessClient
+type witnessClientStream interface {
+	Send(*pb.WitnessChunk) error
+	CloseAndRecv() (*pb.SubmitWitnessResponse, error)
+}
+
+// sendWitness sends the witness of a job in small chunks
+func sendWitness(stream witnessClientStream, jobID string, witness []byte) error {
+	const chunkSize = 100
+	chunk := &pb.WitnessChunk{JobID: jobID}
+	for len(witness) > 0 {
+		n := chunkSize
+		if n > len(witness) {
+			n = len(witness)
+		}
+		chunk.Data = witness[:n]
+		if err := stream.Send(chunk); err != nil && err != io.EOF {
+			return err
+		}
+		chunk = &pb.WitnessChunk{}
+		witness = witness[n:]
+	}
+	_, err := stream.CloseAndRecv()
+	return err
+}
+
 func TestMain(m *testing.M) {
 	setupServer()
 	code := m.Run()
@@ -199,18 +224,10 @@ func TestProveAsync(t *testing.T) {
 		}
 	}()
 
-	// 4. send wtness on the wire
-	wc, err := witnessListener.Dial()
-	assert.NoError(err, "dialing witness socket")
-	defer wc.Close()
-	jobID, err := uuid.Parse(r.JobID)
-	assert.NoError(err)
-	bjobID, err := jobID.MarshalBinary()
-	assert.NoError(err)
-	_, err = wc.Write(bjobID)
-	assert.NoError(err)
-	_, err = wc.Write(bWitness.Bytes())
+	// 4. send witness
+	wStream, err := client.SubmitWitness(ctx)
 	assert.NoError(err)
+	assert.NoError(sendWitness(wStream, r.JobID, bWitness.Bytes()), "couldn't send witness")
 
 	<-done
 	assert.Equal(lastStatus, pb.ProveJobResult_COMPLETED)
@@ -225,6 +242,56 @@ func TestProveAsync(t *testing.T) {
 
 }
 
+func TestSubmitWitnessErrors(t *testing.T) {
+	assert := require.New(t)
+
+	// create grpc client connection
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+
+	assert.NoError(err)
+	defer conn.Close()
+
+	client := pb.NewGroth16Client(conn)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+
+	r, err := client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+
+	send := func(jobID string, witness []byte) error {
+		wStream, err := client.SubmitWitness(ctx)
+		assert.NoError(err)
+		return sendWitness(wStream, jobID, witness)
+	}
+
+	// unknown job
+	err = send(uuid.New().String(), bWitness.Bytes())
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	// truncated and oversized witnesses, the job keeps waiting for its witness
+	err = send(r.JobID, bWitness.Bytes()[:bWitness.Len()-1])
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	err = send(r.JobID, append(bWitness.Bytes(), 0))
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	assert.NoError(send(r.JobID, bWitness.Bytes()))
+
+	// the job is not waiting for a witness anymore
+	err = send(r.JobID, bWitness.Bytes())
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
 func TestJobTTL(t *testing.T) {
 	assert := require.New(t)
 
@@ -280,19 +347,11 @@ func TestJobTTL(t *testing.T) {
 		}
 	}()
 
-	// 4. send wtness on the wire
+	// 4. send witness
 	<-time.After(1030 * time.Millisecond) // wait for TTL to expire
-	wc, err := witnessListener.Dial()
-	assert.NoError(err, "dialing witness socket")
-	defer wc.Close()
-	jobID, err := uuid.Parse(r.JobID)
-	assert.NoError(err)
-	bjobID, err := jobID.MarshalBinary()
-	assert.NoError(err)
-	_, err = wc.Write(bjobID)
-	assert.NoError(err)
-	_, err = wc.Write(bWitness.Bytes())
+	wStream, err := client.SubmitWitness(ctx)
 	assert.NoError(err)
+	assert.NoError(sendWitness(wStream, r.JobID, bWitness.Bytes()), "couldn't send witness")
 	<-done
 	assert.Equal(lastStatus, pb.ProveJobResult_ERRORED)
 	assert.Equal(errMsg, errJobExpired.Error())
@@ -397,7 +456,9 @@ func TestVerifySync(t *testing.T) {
 	)
 	w.X.Assign(3)
 	w.Y.Assign(35)
-	proof, err := groth16.Prove(gnarkdServer.circuits["bn254/cubic"].r1cs, gnarkdServer.circuits["bn254/cubic"].pk, &w)
+	data, err := gnarkdServer.provingData("bn254/cubic", gnarkdServer.circuits["bn254/cubic"])
+	assert.NoError(err)
+	proof, err := groth16.Prove(data.r1cs, data.pk, &w)
 	assert.NoError(err)
 	_, err = proof.WriteRawTo(&bProof)
 	assert.NoError(err)
@@ -414,3 +475,272 @@ func TestVerifySync(t *testing.T) {
 	assert.NoError(err, "grpc sync verify failed")
 	assert.True(vResult.Ok)
 }
+
+func TestBatchVerify(t *testing.T) {
+	assert := require.New(t)
+
+	// create grpc client connection
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+
+	assert.NoError(err)
+	defer conn.Close()
+
+	client := pb.NewGroth16Client(conn)
+
+	// 1. prove x³ + x + 5 == y for a few x
+	data, err := gnarkdServer.provingData("bn254/cubic", gnarkdServer.circuits["bn254/cubic"])
+	assert.NoError(err)
+	request := &pb.BatchVerifyRequest{CircuitID: "bn254/cubic"}
+	for x := 1; x <= 4; x++ {
+		var (
+			w        cubic.Circuit
+			bWitness bytes.Buffer
+			bProof   bytes.Buffer
+		)
+		w.X.Assign(x)
+		w.Y.Assign(x*x*x + x + 5)
+		proof, err := groth16.Prove(data.r1cs, data.pk, &w)
+		assert.NoError(err)
+		_, err = proof.WriteRawTo(&bProof)
+		assert.NoError(err)
+		_, err = witness.WritePublicTo(&bWitness, ecc.BN254, &w)
+		assert.NoError(err)
+		request.Proofs = append(request.Proofs, &pb.ProofToVerify{Proof: bProof.Bytes(), PublicWitness: bWitness.Bytes()})
+	}
+
+	// 2. all the proofs are valid
+	result, err := client.BatchVerify(ctx, request)
+	assert.NoError(err)
+	assert.Len(result.Results, len(request.Proofs))
+	for _, r := range result.Results {
+		assert.True(r.Ok)
+		assert.Nil(r.Err)
+	}
+
+	// 3. a swapped public witness and a truncated proof only fail their own proof
+	request.Proofs[1].PublicWitness, request.Proofs[2].PublicWitness = request.Proofs[2].PublicWitness, request.Proofs[1].PublicWitness
+	request.Proofs[3].Proof = request.Proofs[3].Proof[:10]
+	result, err = client.BatchVerify(ctx, request)
+	assert.NoError(err)
+	for i, r := range result.Results {
+		assert.Equal(i == 0, r.Ok, "proof %d", i)
+		assert.Equal(i == 0, r.Err == nil, "proof %d", i)
+	}
+
+	// 4. the batch size is bounded, and the circuit must be a groth16 circuit
+	_, err = client.BatchVerify(ctx, &pb.BatchVerifyRequest{CircuitID: "bn254/cubic", Proofs: make([]*pb.ProofToVerify, maxBatchVerify+1)})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = client.BatchVerify(ctx, &pb.BatchVerifyRequest{CircuitID: "bn254/cubic_plonk"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestInsecurePlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+	_, ok := s.lookupCircuit("bn254/cubic")
+	assert.True(ok)
+	_, ok = s.lookupCircuit("bn254/cubic_plonk")
+	assert.False(ok, "PLONK circuits shouldn't be loaded without WithInsecurePlonk")
+}
+
+func TestPlonkProveSync(t *testing.T) {
+	assert := require.New(t)
+
+	// create grpc client connection
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+
+	assert.NoError(err)
+	defer conn.Close()
+
+	c := pb.NewPlonkClient(conn)
+
+	// 1. serialize a valid witness
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+		bPublic  bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &w)
+	assert.NoError(err)
+
+	// 2. call prove
+	proveResult, err := c.Prove(ctx, &pb.ProveRequest{
+		CircuitID: "bn254/cubic_plonk",
+		Witness:   bWitness.Bytes(),
+	})
+	assert.NoError(err, "grpc sync prove failed")
+
+	// 3. ensure returned proof is valid.
+	proof := plonk.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(bytes.NewReader(proveResult.Proof))
+	assert.NoError(err, "deserializing grpc proof response failed")
+
+	data, err := gnarkdServer.provingData("bn254/cubic_plonk", gnarkdServer.circuits["bn254/cubic_plonk"])
+	assert.NoError(err)
+	err = plonk.ReadAndVerify(proof, data.spr, data.scheme, bytes.NewReader(bPublic.Bytes()))
+	assert.NoError(err, "couldn't verify proof returned from grpc server")
+
+	vResult, err := c.Verify(ctx, &pb.VerifyRequest{
+		CircuitID:     "bn254/cubic_plonk",
+		PublicWitness: bPublic.Bytes(),
+		Proof:         proveResult.Proof,
+	})
+	assert.NoError(err, "grpc sync verify failed")
+	assert.True(vResult.Ok)
+
+	// 4. create invalid proof
+	var wBad cubic.Circuit
+	wBad.X.Assign(4)
+	wBad.Y.Assign(42)
+	bWitness.Reset()
+
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &wBad)
+	assert.NoError(err)
+
+	_, err = c.Prove(ctx, &pb.ProveRequest{
+		CircuitID: "bn254/cubic_plonk",
+		Witness:   bWitness.Bytes(),
+	})
+	assert.Error(err, "grpc sync false prove failed")
+
+	// 5. the proof doesn't verify with another public witness
+	bPublic.Reset()
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &wBad)
+	assert.NoError(err)
+	_, err = c.Verify(ctx, &pb.VerifyRequest{
+		CircuitID:     "bn254/cubic_plonk",
+		PublicWitness: bPublic.Bytes(),
+		Proof:         proveResult.Proof,
+	})
+	assert.Error(err, "grpc sync verify with invalid public witness should fail")
+}
+
+func TestPlonkProveAsync(t *testing.T) {
+	assert := require.New(t)
+
+	// create grpc client connection
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+
+	assert.NoError(err)
+	defer conn.Close()
+
+	client := pb.NewPlonkClient(conn)
+
+	// 1. serialize a valid witness
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+		bPublic  bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+	_, err = witness.WritePublicTo(&bPublic, ecc.BN254, &w)
+	assert.NoError(err)
+
+	// 2. call prove
+	r, err := client.CreateProveJob(ctx, &pb.CreateProveJobRequest{
+		CircuitID: "bn254/cubic_plonk",
+	})
+	assert.NoError(err, "grpc sync create prove failed")
+
+	// 3. subscribe to status changes
+	stream, err := client.SubscribeToProveJob(ctx, &pb.SubscribeToProveJobRequest{JobID: r.JobID})
+	assert.NoError(err, "couldn't subscribe to job")
+
+	done := make(chan struct{})
+	var lastStatus pb.ProveJobResult_Status
+	var rproof []byte
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				done <- struct{}{}
+				return
+			}
+			lastStatus = resp.Status
+			if lastStatus == pb.ProveJobResult_COMPLETED {
+				rproof = resp.Proof
+			}
+		}
+	}()
+
+	// 4. send witness
+	wStream, err := client.SubmitWitness(ctx)
+	assert.NoError(err)
+	assert.NoError(sendWitness(wStream, r.JobID, bWitness.Bytes()), "couldn't send witness")
+
+	<-done
+	assert.Equal(lastStatus, pb.ProveJobResult_COMPLETED)
+
+	// 5. ensure returned proof is valid.
+	vResult, err := client.Verify(ctx, &pb.VerifyRequest{
+		CircuitID:     "bn254/cubic_plonk",
+		PublicWitness: bPublic.Bytes(),
+		Proof:         rproof,
+	})
+	assert.NoError(err, "couldn't verify proof returned from grpc server")
+	assert.True(vResult.Ok)
+
+	// 6. the job is listed by the Plonk service only
+	list, err := client.ListProveJob(ctx, &pb.ListProveJobRequest{})
+	assert.NoError(err)
+	found := false
+	for _, j := range list.Jobs {
+		found = found || j.JobID == r.JobID
+	}
+	assert.True(found)
+
+	list, err = pb.NewGroth16Client(conn).ListProveJob(ctx, &pb.ListProveJobRequest{})
+	assert.NoError(err)
+	for _, j := range list.Jobs {
+		assert.NotEqual(r.JobID, j.JobID, "PLONK job listed by the Groth16 service")
+	}
+}
+
+func TestBackendMismatch(t *testing.T) {
+	assert := require.New(t)
+
+	// create grpc client connection
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+
+	assert.NoError(err)
+	defer conn.Close()
+
+	_, err = pb.NewGroth16Client(conn).CreateProveJob(ctx, &pb.CreateProveJobRequest{
+		CircuitID: "bn254/cubic_plonk",
+	})
+	assert.Error(err, "Groth16 service shouldn't accept a PLONK circuit")
+
+	_, err = pb.NewPlonkClient(conn).CreateProveJob(ctx, &pb.CreateProveJobRequest{
+		CircuitID: "bn254/cubic",
+	})
+	assert.Error(err, "PLONK service shouldn't accept a Groth16 circuit")
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errShuttingDown = status.Error(codes.Unavailable, "gnarkd is shutting down")
+
+// Shutdown gracefully stops the Server, for rolling deploys
+//
+// new jobs, witnesses and synchronous proofs are rejected with an Unavailable status; queued jobs stay queued
+// (they are queued again at restart if the JobStore persists them) and the running jobs and synchronous proofs finish.
+// Once they are done and their callbacks delivered, the SubscribeToProveJob streams of the unfinished jobs
+// receive the status of their job and are closed with an Unavailable status.
+//
+// If ctx is done first, Shutdown returns ctx.Err(): the jobs still running stay RUNNING in the JobStore,
+// and are queued again at restart. Shutdown doesn't cancel the context of the Server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.log.Info("shutting down, waiting for the running jobs")
+		s.drainingLock.Lock()
+		close(s.draining)
+		s.drainingLock.Unlock()
+		s.scheduler.close()
+		go func() {
+			s.workers.Wait()
+			s.syncProofs.Wait()
+			// jobs still finish (cancelled, expired): their callbacks mustn't be added while waiting
+			s.drainingLock.Lock()
+			s.callbacksClosed = true
+			s.drainingLock.Unlock()
+			s.callbacks.Wait()
+			close(s.drained)
+		}()
+	})
+
+	select {
+	case <-s.drained:
+		s.log.Info("running jobs finished")
+		return nil
+	case <-ctx.Done():
+		s.log.Warnw("shutdown deadline exceeded, jobs are still running", "err", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// ShuttingDown returns true once Shutdown is called
+func (s *Server) ShuttingDown() bool {
+	select {
+	case <-s.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// startSyncProof registers a synchronous proof, Shutdown waits for it until the caller calls s.syncProofs.Done()
+// it returns an Unavailable status if the Server is shutting down
+func (s *Server) startSyncProof() error {
+	s.drainingLock.RLock()
+	defer s.drainingLock.RUnlock()
+	if err := s.checkAccepting(); err != nil {
+		return err
+	}
+	s.syncProofs.Add(1)
+	return nil
+}
+
+// checkAccepting returns an Unavailable status if the Server is shutting down
+func (s *Server) checkAccepting() error {
+	if s.ShuttingDown() {
+		return errShuttingDown
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestShutdown(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryJobStore()
+	s, err := NewServer(ctx, log, "../circuits", WithJobStore(store), WithWorkers(2))
+	assert.NoError(err)
+
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGroth16Server(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+	client := pb.NewGroth16Client(conn)
+
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+
+	// a job waiting for its witness, followed by a client
+	waiting, err := client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+	stream, err := client.SubscribeToProveJob(ctx, &pb.SubscribeToProveJobRequest{JobID: waiting.JobID})
+	assert.NoError(err)
+
+	// jobs being proved when the shutdown starts
+	var jobIDs []string
+	for i := 0; i < 4; i++ {
+		r, err := client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+		assert.NoError(err)
+		wStream, err := client.SubmitWitness(ctx)
+		assert.NoError(err)
+		assert.NoError(sendWitness(wStream, r.JobID, bWitness.Bytes()))
+		jobIDs = append(jobIDs, r.JobID)
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(ctx, time.Minute)
+	defer cancelShutdown()
+	assert.NoError(s.Shutdown(shutdownCtx))
+	assert.True(s.ShuttingDown())
+
+	// no job is left running: jobs are either proved or still queued
+	records, err := store.LoadAll()
+	assert.NoError(err)
+	assert.Len(records, len(jobIDs)+1)
+	for _, r := range records {
+		assert.NotEqual(pb.ProveJobResult_RUNNING, r.Status, "job %s is still running", r.ID)
+	}
+
+	// the subscribers get the last status of their job
+	result, err := stream.Recv()
+	assert.NoError(err)
+	assert.Equal(pb.ProveJobResult_WAITING_WITNESS, result.Status)
+	_, err = stream.Recv()
+	assert.Equal(codes.Unavailable, status.Code(err))
+
+	// new jobs, witnesses and proofs are rejected
+	_, err = client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.Equal(codes.Unavailable, status.Code(err))
+	wStream, err := client.SubmitWitness(ctx)
+	assert.NoError(err)
+	err = sendWitness(wStream, waiting.JobID, bWitness.Bytes())
+	assert.Equal(codes.Unavailable, status.Code(err))
+	_, err = client.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Equal(codes.Unavailable, status.Code(err))
+
+	// finished jobs can still be followed
+	jobs, err := client.ListProveJob(ctx, &pb.ListProveJobRequest{})
+	assert.NoError(err)
+	assert.Len(jobs.Jobs, len(jobIDs)+1)
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+
+	// a worker busy with a job
+	s.workers.Add(1)
+	shutdownCtx, cancelShutdown := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelShutdown()
+	assert.Equal(context.DeadlineExceeded, s.Shutdown(shutdownCtx))
+
+	// the job finishes, a later call returns once the workers are done
+	s.workers.Done()
+	assert.NoError(s.Shutdown(ctx))
+}
+
+func TestShutdownWaitsSyncProofs(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits")
+	assert.NoError(err)
+
+	// a synchronous proof is running
+	assert.NoError(s.startSyncProof())
+	shutdownCtx, cancelShutdown := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelShutdown()
+	assert.Equal(context.DeadlineExceeded, s.Shutdown(shutdownCtx))
+
+	// no new proof starts, the shutdown completes once the running one is done
+	assert.Equal(codes.Unavailable, status.Code(s.startSyncProof()))
+	s.syncProofs.Done()
+	assert.NoError(s.Shutdown(ctx))
+}
+
+func TestShutdownCallbacks(t *testing.T) {
+	assert := require.New(t)
+
+	var received int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+	}))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits", WithCallbacks([]string{"127.0.0.1"}, ""))
+	assert.NoError(err)
+	job := &proveJob{id: uuid.New(), status: pb.ProveJobResult_ERRORED, callbackURL: httpServer.URL}
+
+	// the callbacks of the jobs finishing during the shutdown are delivered
+	shutdownCtx, cancelShutdown := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelShutdown()
+	s.workers.Add(1)
+	assert.Equal(context.DeadlineExceeded, s.Shutdown(shutdownCtx))
+	s.notifyFinished(job)
+	s.workers.Done()
+	assert.NoError(s.Shutdown(ctx))
+	assert.Equal(int32(1), atomic.LoadInt32(&received))
+
+	// once drained, jobs finishing (e.g. cancelled) don't start callbacks
+	s.notifyFinished(job)
+	s.callbacks.Wait()
+	assert.Equal(int32(1), atomic.LoadInt32(&received))
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// isSolved solves the constraint system of the circuit with the binary encoded full witness
+// a witness which doesn't satisfy a constraint returns a *backend.UnsatisfiedConstraintError
+func (c *circuit) isSolved(cs frontend.CompiledConstraintSystem, witness []byte) error {
+	if c.backendID == backend.PLONK {
+		return plonk.ReadAndIsSolved(cs, bytes.NewReader(witness))
+	}
+	return groth16.ReadAndIsSolved(cs, bytes.NewReader(witness))
+}
+
+// checkWitnessSize returns an InvalidArgument status if the binary encoded full witness doesn't have the size
+// of the witnesses of the circuit
+func checkWitnessSize(circuit circuit, witness []byte) error {
+	if len(witness) != circuit.fullWitnessSize {
+		return status.Errorf(codes.InvalidArgument, "received a %d bytes witness, expected %d bytes", len(witness), circuit.fullWitnessSize)
+	}
+	return nil
+}
+
+// proveError returns the status of a failed synchronous proof: InvalidArgument with the pb.SolverError as detail
+// if the witness doesn't solve the circuit (the prover solves it first), Internal otherwise
+func (s *Server) proveError(circuitID string, circuit circuit, err error) error {
+	s.log.Errorw("proof failed", "circuitID", circuitID, "err", err)
+	if serr := circuit.solverError(err); serr != nil {
+		return witnessStatus(err, serr)
+	}
+	return status.Errorf(codes.Internal, err.Error())
+}
+
+// solverError returns the pb.SolverError describing err, nil if err isn't a *backend.UnsatisfiedConstraintError
+func (c *circuit) solverError(err error) *pb.SolverError {
+	var uerr *backend.UnsatisfiedConstraintError
+	if !errors.As(err, &uerr) {
+		return nil
+	}
+	serr := &pb.SolverError{ConstraintID: uint64(uerr.ConstraintID), DebugInfo: uerr.DebugInfo}
+	for _, wireID := range uerr.Wires {
+		serr.Wires = append(serr.Wires, c.wireName(wireID))
+	}
+	return serr
+}
+
+// wireName returns the name of the wire in the witness schema of the circuit
+// inputs are named public[i] or secret[i] if the circuit has no schema, internal wires internal[i]
+func (c *circuit) wireName(wireID int) string {
+	nbPublic := c.nbPublicVariables
+	if c.backendID == backend.GROTH16 {
+		// the first wire of a R1CS is the ONE_WIRE
+		wireID--
+		nbPublic--
+	}
+	if wireID < nbPublic {
+		if c.schema != nil && wireID < len(c.schema.Public) {
+			return c.schema.Public[wireID]
+		}
+		return fmt.Sprintf("public[%d]", wireID)
+	}
+	wireID -= nbPublic
+	if wireID < c.nbSecretVariables {
+		if c.schema != nil && wireID < len(c.schema.Secret) {
+			return c.schema.Secret[wireID]
+		}
+		return fmt.Sprintf("secret[%d]", wireID)
+	}
+	return fmt.Sprintf("internal[%d]", wireID-c.nbSecretVariables)
+}
+
+// witnessStatus returns the InvalidArgument status of a witness which doesn't solve the circuit
+// with the pb.SolverError as detail, if serr isn't nil
+func witnessStatus(err error, serr *pb.SolverError) error {
+	st := status.Newf(codes.InvalidArgument, "invalid witness: %s", err)
+	if serr == nil {
+		return st.Err()
+	}
+	if withDetails, err := st.WithDetails(serr); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// SolverErrorFromStatus returns the pb.SolverError in the details of a gRPC status error, nil if there is none
+func SolverErrorFromStatus(err error) *pb.SolverError {
+	for _, detail := range status.Convert(err).Details() {
+		if serr, ok := detail.(*pb.SolverError); ok {
+			return serr
+		}
+	}
+	return nil
+}
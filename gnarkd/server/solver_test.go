@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"net"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSolverError(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "", grpc.WithContextDialer(
+		func(c context.Context, s string) (net.Conn, error) {
+			return grpcListener.Dial()
+		}), grpc.WithInsecure())
+	assert.NoError(err)
+	defer conn.Close()
+
+	// x³ + x + 5 != y
+	var (
+		wBad     cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	wBad.X.Assign(4)
+	wBad.Y.Assign(42)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &wBad)
+	assert.NoError(err)
+
+	// the wires of the failing constraint are named after the witness schema
+	checkSolverError := func(serr *pb.SolverError) {
+		assert.NotNil(serr, "missing solver error")
+		assert.NotEmpty(serr.Wires)
+		for _, wire := range serr.Wires {
+			assert.NotEqual("secret[0]", wire)
+			assert.NotEqual("public[0]", wire)
+		}
+	}
+
+	// sync prove
+	groth16Client := pb.NewGroth16Client(conn)
+	_, err = groth16Client.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic", Witness: bWitness.Bytes()})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	serr := SolverErrorFromStatus(err)
+	checkSolverError(serr)
+	assert.Contains(serr.Wires, "Y")
+	assert.Contains(serr.DebugInfo, "42", "debug info should have the values of the wires")
+
+	plonkClient := pb.NewPlonkClient(conn)
+	_, err = plonkClient.Prove(ctx, &pb.ProveRequest{CircuitID: "bn254/cubic_plonk", Witness: bWitness.Bytes()})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	checkSolverError(SolverErrorFromStatus(err))
+
+	// async prove: the job fails when the witness is submitted, before being queued
+	r, err := groth16Client.CreateProveJob(ctx, &pb.CreateProveJobRequest{CircuitID: "bn254/cubic"})
+	assert.NoError(err)
+	wStream, err := groth16Client.SubmitWitness(ctx)
+	assert.NoError(err)
+	err = sendWitness(wStream, r.JobID, bWitness.Bytes())
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	checkSolverError(SolverErrorFromStatus(err))
+
+	jobs, err := groth16Client.ListProveJob(ctx, &pb.ListProveJobRequest{})
+	assert.NoError(err)
+	var result *pb.ProveJobResult
+	for _, job := range jobs.Jobs {
+		if job.JobID == r.JobID {
+			result = job
+		}
+	}
+	assert.NotNil(result, "job not listed")
+	assert.Equal(pb.ProveJobResult_ERRORED, result.Status)
+	assert.NotNil(result.Err)
+	checkSolverError(result.SolverError)
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/gnarkd/pb"
+)
+
+// JobRecord is the persisted state of a prove job
+type JobRecord struct {
+	ID          uuid.UUID
+	CircuitID   string
+	BackendID   backend.ID
+	Priority    int32
+	Owner       string
+	CallbackURL string
+	Status      pb.ProveJobResult_Status
+	Expiration  time.Time
+	Witness     []byte // set while the job is QUEUED or RUNNING, dropped once the job is finished
+	Err         string
+	SolverError []byte // proto encoded pb.SolverError, if the witness doesn't solve the circuit
+	Proof       []byte
+}
+
+// JobStore persists the prove jobs of a Server, so that they survive restarts
+//
+// The Server keeps the jobs it serves in memory and saves them in the JobStore each time they change;
+// when it starts, it restores the jobs returned by LoadAll.
+// The records of the jobs that are not finished hold the secret witness, in plaintext.
+type JobStore interface {
+	// Save inserts or replaces the record with the same ID
+	// the Server doesn't save a record concurrently with another one of the same ID
+	Save(record *JobRecord) error
+
+	// Delete removes the record with given ID, if any
+	Delete(id uuid.UUID) error
+
+	// LoadAll returns all the records in the store
+	LoadAll() ([]*JobRecord, error)
+
+	// SaveUsage replaces the usage of the identities with limits, see Quotas
+	SaveUsage(usage map[string]QuotaUsage) error
+
+	// LoadUsage returns the usage saved by SaveUsage, empty if none was saved
+	LoadUsage() (map[string]QuotaUsage, error)
+}
+
+// NewMemoryJobStore returns a JobStore keeping the records in memory: jobs don't survive restarts
+func NewMemoryJobStore() JobStore {
+	return &memoryJobStore{}
+}
+
+type memoryJobStore struct {
+	records sync.Map // key == uuid, value == *JobRecord
+
+	lock  sync.Mutex
+	usage map[string]QuotaUsage
+}
+
+func (m *memoryJobStore) Save(record *JobRecord) error {
+	m.records.Store(record.ID, record)
+	return nil
+}
+
+func (m *memoryJobStore) Delete(id uuid.UUID) error {
+	m.records.Delete(id)
+	return nil
+}
+
+func (m *memoryJobStore) LoadAll() ([]*JobRecord, error) {
+	var records []*JobRecord
+	m.records.Range(func(k, v interface{}) bool {
+		records = append(records, v.(*JobRecord))
+		return true
+	})
+	return records, nil
+}
+
+func (m *memoryJobStore) SaveUsage(usage map[string]QuotaUsage) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.usage = usage
+	return nil
+}
+
+func (m *memoryJobStore) LoadUsage() (map[string]QuotaUsage, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	usage := make(map[string]QuotaUsage, len(m.usage))
+	for identity, u := range m.usage {
+		usage[identity] = u
+	}
+	return usage, nil
+}
+
+const (
+	jobRecordExt = ".job"
+	usageFile    = "quotas.usage" // usage of the identities, see SaveUsage
+)
+
+// NewDirJobStore returns a JobStore keeping one cbor encoded file per record in dir
+// dir is created if it doesn't exist; the files are only readable by their owner (0600), as the records of the
+// unfinished jobs hold their witness in plaintext
+func NewDirJobStore(dir string) (JobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	// default encoding of time.Time drops the sub-second part of the expiration
+	enc, err := cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return &dirJobStore{dir: dir, enc: enc}, nil
+}
+
+type dirJobStore struct {
+	dir string
+	enc cbor.EncMode
+}
+
+func (d *dirJobStore) path(id uuid.UUID) string {
+	return filepath.Join(d.dir, id.String()+jobRecordExt)
+}
+
+// Save writes the record in a temporary file first, synced before it replaces the record, and syncs dir after:
+// a crash never leaves a partially written record
+func (d *dirJobStore) Save(record *JobRecord) error {
+	data, err := d.enc.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return d.writeFile(record.ID.String()+jobRecordExt, data)
+}
+
+// writeFile replaces the file name in dir with data, see Save
+func (d *dirJobStore) writeFile(name string, data []byte) error {
+	// TempFile creates the file with mode 0600
+	f, err := ioutil.TempFile(d.dir, name+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filepath.Join(d.dir, name)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return syncDir(d.dir)
+}
+
+// syncDir flushes the entries of dir (created, renamed or removed files) to disk
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (d *dirJobStore) Delete(id uuid.UUID) error {
+	err := os.Remove(d.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *dirJobStore) LoadAll() ([]*JobRecord, error) {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	var records []*JobRecord
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), jobRecordExt) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(d.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var record JobRecord
+		if err := cbor.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// SaveUsage writes the usage in a cbor encoded file next to the records, see Save
+func (d *dirJobStore) SaveUsage(usage map[string]QuotaUsage) error {
+	data, err := d.enc.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	return d.writeFile(usageFile, data)
+}
+
+func (d *dirJobStore) LoadUsage() (map[string]QuotaUsage, error) {
+	usage := make(map[string]QuotaUsage)
+	data, err := ioutil.ReadFile(filepath.Join(d.dir, usageFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cbor.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
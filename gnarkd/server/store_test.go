@@ -0,0 +1,181 @@
+package server
+
+import (
+	"bytes"
+	context "context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/gnarkd/pb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirJobStore(t *testing.T) {
+	assert := require.New(t)
+
+	dir, err := ioutil.TempDir("", "gnarkd-jobs")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewDirJobStore(dir)
+	assert.NoError(err)
+
+	r1 := &JobRecord{
+		ID:         uuid.New(),
+		CircuitID:  "bn254/cubic",
+		BackendID:  backend.GROTH16,
+		Status:     pb.ProveJobResult_QUEUED,
+		Expiration: time.Now().Add(time.Hour),
+		Witness:    []byte{1, 2, 3},
+	}
+	r2 := &JobRecord{
+		ID:         uuid.New(),
+		CircuitID:  "bn254/cubic_plonk",
+		BackendID:  backend.PLONK,
+		Status:     pb.ProveJobResult_ERRORED,
+		Expiration: time.Now().Add(time.Hour),
+		Err:        errJobCancelled.Error(),
+	}
+	assert.NoError(store.Save(r1))
+	assert.NoError(store.Save(r2))
+
+	// the record of r1 holds its witness, only its owner can read it
+	info, err := os.Stat(filepath.Join(dir, r1.ID.String()+jobRecordExt))
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// update r1
+	r1.Status = pb.ProveJobResult_COMPLETED
+	r1.Witness = nil
+	r1.Proof = []byte{4, 5}
+	assert.NoError(store.Save(r1))
+
+	records, err := store.LoadAll()
+	assert.NoError(err)
+	assert.Equal(2, len(records))
+	for _, r := range records {
+		expected := r1
+		if r.ID == r2.ID {
+			expected = r2
+		}
+		assert.Equal(expected.CircuitID, r.CircuitID)
+		assert.Equal(expected.BackendID, r.BackendID)
+		assert.Equal(expected.Status, r.Status)
+		assert.True(expected.Expiration.Equal(r.Expiration))
+		assert.Equal(len(expected.Witness), len(r.Witness))
+		assert.Equal(expected.Err, r.Err)
+		assert.Equal(expected.Proof, r.Proof)
+	}
+
+	// usage of the quotas
+	usage, err := store.LoadUsage()
+	assert.NoError(err)
+	assert.Empty(usage)
+	day := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(store.SaveUsage(map[string]QuotaUsage{"alice": {Day: day, ProveCPUSeconds: 42}}))
+	usage, err = store.LoadUsage()
+	assert.NoError(err)
+	assert.True(day.Equal(usage["alice"].Day))
+	assert.Equal(42.0, usage["alice"].ProveCPUSeconds)
+
+	assert.NoError(store.Delete(r1.ID))
+	assert.NoError(store.Delete(r1.ID), "deleting a missing record shouldn't fail")
+	records, err = store.LoadAll()
+	assert.NoError(err)
+	assert.Equal(1, len(records))
+	assert.Equal(r2.ID, records[0].ID)
+}
+
+func TestRestoreJobs(t *testing.T) {
+	assert := require.New(t)
+
+	dir, err := ioutil.TempDir("", "gnarkd-jobs")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	store, err := NewDirJobStore(dir)
+	assert.NoError(err)
+
+	// a job waiting for its witness, and a queued job, persisted by a server which then stops
+	var (
+		w        cubic.Circuit
+		bWitness bytes.Buffer
+	)
+	w.X.Assign(3)
+	w.Y.Assign(35)
+	_, err = witness.WriteFullTo(&bWitness, ecc.BN254, &w)
+	assert.NoError(err)
+
+	waiting := &JobRecord{
+		ID:         uuid.New(),
+		CircuitID:  "bn254/cubic",
+		BackendID:  backend.GROTH16,
+		Status:     pb.ProveJobResult_WAITING_WITNESS,
+		Expiration: time.Now().Add(time.Hour),
+	}
+	queued := &JobRecord{
+		ID:         uuid.New(),
+		CircuitID:  "bn254/cubic",
+		BackendID:  backend.GROTH16,
+		Status:     pb.ProveJobResult_RUNNING,
+		Expiration: time.Now().Add(time.Hour),
+		Witness:    bWitness.Bytes(),
+	}
+	unknown := &JobRecord{
+		ID:         uuid.New(),
+		CircuitID:  "bn254/unknown",
+		BackendID:  backend.GROTH16,
+		Expiration: time.Now().Add(time.Hour),
+	}
+	assert.NoError(store.Save(waiting))
+	assert.NoError(store.Save(queued))
+	assert.NoError(store.Save(unknown))
+
+	// restart
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, log, "../circuits", WithJobStore(store), WithInsecurePlonk())
+	assert.NoError(err)
+
+	job, err := s.getJob(ctx, waiting.ID.String(), backend.GROTH16)
+	assert.NoError(err)
+	job.RLock()
+	assert.Equal(pb.ProveJobResult_WAITING_WITNESS, job.status)
+	job.RUnlock()
+
+	_, err = s.getJob(ctx, unknown.ID.String(), backend.GROTH16)
+	assert.Error(err, "job of an unknown circuit should be dropped")
+
+	// the interrupted job is proved again
+	job, err = s.getJob(ctx, queued.ID.String(), backend.GROTH16)
+	assert.NoError(err)
+	done := false
+	for i := 0; i < 100 && !done; i++ {
+		time.Sleep(50 * time.Millisecond)
+		job.RLock()
+		done = job.isFinished()
+		job.RUnlock()
+	}
+	job.RLock()
+	assert.Equal(pb.ProveJobResult_COMPLETED, job.status)
+	job.RUnlock()
+
+	// and its result is persisted
+	records, err := store.LoadAll()
+	assert.NoError(err)
+	assert.Equal(2, len(records))
+	for _, r := range records {
+		if r.ID == queued.ID {
+			assert.Equal(pb.ProveJobResult_COMPLETED, r.Status)
+			assert.NotEmpty(r.Proof)
+			assert.Empty(r.Witness)
+		}
+	}
+}
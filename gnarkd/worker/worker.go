@@ -0,0 +1,189 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package worker implements the MSMWorker service of gnarkd-worker.
+//
+// A worker holds a chunk of the proving key of bn254 Groth16 circuits (see groth16.SplitProvingKey), and computes
+// the multi-exponentiations of the proofs of gnarkd over the points of its chunks.
+package worker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/gnarkd/pb"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChunkExtension of the proving key chunk files, <circuit>.<i>.pkchunk
+const ChunkExtension = ".pkchunk"
+
+// Worker implements MSMWorkerServer
+type Worker struct {
+	pb.UnimplementedMSMWorkerServer
+	chunks map[string]*groth16_bn254.ProvingKeyChunk // by circuitID
+	log    *zap.SugaredLogger
+}
+
+// New returns a Worker serving the proving key chunks in chunkDir
+// as for the circuits of gnarkd, chunkDir/bn254/cubic holds the chunk of circuit bn254/cubic (one .pkchunk file)
+func New(log *zap.SugaredLogger, chunkDir string) (*Worker, error) {
+	w := &Worker{chunks: make(map[string]*groth16_bn254.ProvingKeyChunk), log: log}
+	curves, err := ioutil.ReadDir(chunkDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, curveDir := range curves {
+		if !curveDir.IsDir() {
+			continue
+		}
+		if curveDir.Name() != "bn254" {
+			log.Warnw("ignoring chunks, only bn254 circuits can be proved by workers", "dir", filepath.Join(chunkDir, curveDir.Name()))
+			continue
+		}
+		circuits, err := ioutil.ReadDir(filepath.Join(chunkDir, curveDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, circuit := range circuits {
+			if !circuit.IsDir() {
+				continue
+			}
+			circuitID := curveDir.Name() + "/" + circuit.Name()
+			if err := w.loadChunk(circuitID, filepath.Join(chunkDir, curveDir.Name(), circuit.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return w, nil
+}
+
+// loadChunk reads the chunk file in circuitDir
+func (w *Worker) loadChunk(circuitID, circuitDir string) error {
+	matches, err := filepath.Glob(filepath.Join(circuitDir, "*"+ChunkExtension))
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	if len(matches) > 1 {
+		return fmt.Errorf("%s: a worker holds one chunk per circuit, found %s", circuitDir, strings.Join(matches, ", "))
+	}
+	f, err := os.Open(matches[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var chunk groth16_bn254.ProvingKeyChunk
+	if _, err := chunk.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("%s: %w", matches[0], err)
+	}
+	w.chunks[circuitID] = &chunk
+	w.log.Infow("loaded proving key chunk", "circuitID", circuitID, "file", matches[0])
+	return nil
+}
+
+// ListChunks describes the proving key chunks held by the worker
+func (w *Worker) ListChunks(ctx context.Context, request *pb.ListChunksRequest) (*pb.ListChunksResponse, error) {
+	response := &pb.ListChunksResponse{}
+	for circuitID, chunk := range w.chunks {
+		info := &pb.ProvingKeyChunkInfo{CircuitID: circuitID}
+		for msm := groth16_bn254.MSM(0); msm < groth16_bn254.NbMSM; msm++ {
+			info.Ranges = append(info.Ranges, &pb.MSMRange{
+				Start: uint64(chunk.Start[msm]),
+				End:   uint64(chunk.Start[msm] + chunk.Len(msm)),
+				Total: uint64(chunk.Total[msm]),
+			})
+		}
+		response.Chunks = append(response.Chunks, info)
+	}
+	return response, nil
+}
+
+// MultiExp receives the scalars of a multi-exponentiation and returns its result over the points of the chunk
+func (w *Worker) MultiExp(stream pb.MSMWorker_MultiExpServer) error {
+	// the first message sets the circuitID and msm
+	msg, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "didn't receive any scalars")
+	}
+	if err != nil {
+		return err
+	}
+	circuitID := msg.CircuitID
+	chunk, ok := w.chunks[circuitID]
+	if !ok {
+		return status.Errorf(codes.NotFound, "no chunk of circuit %s", circuitID)
+	}
+	msm := groth16_bn254.MSM(msg.Msm)
+	if msm < 0 || msm >= groth16_bn254.NbMSM {
+		return status.Errorf(codes.InvalidArgument, "unknown msm %d", msg.Msm)
+	}
+
+	// the scalars of the points of the chunk
+	expectedSize := chunk.Len(msm) * fr.Bytes
+	buf := make([]byte, 0, expectedSize)
+	for {
+		if len(buf)+len(msg.Scalars) > expectedSize {
+			return status.Errorf(codes.InvalidArgument, "too many scalars, expected %d", chunk.Len(msm))
+		}
+		buf = append(buf, msg.Scalars...)
+		if msg, err = stream.Recv(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(buf) != expectedSize {
+		return status.Errorf(codes.InvalidArgument, "received %d bytes of scalars, expected %d", len(buf), expectedSize)
+	}
+	scalars, err := groth16_bn254.UnmarshalScalars(buf)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	w.log.Debugw("MultiExp", "circuitID", circuitID, "msm", msm.String(), "nbPoints", len(scalars))
+	var point []byte
+	if msm == groth16_bn254.MSMG2B {
+		res, err := chunk.MultiExpG2(scalars)
+		if err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		var p curve.G2Affine
+		p.FromJacobian(&res)
+		point = p.Marshal()
+	} else {
+		res, err := chunk.MultiExpG1(msm, scalars)
+		if err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		var p curve.G1Affine
+		p.FromJacobian(&res)
+		point = p.Marshal()
+	}
+	return stream.SendAndClose(&pb.MultiExpResult{Point: point})
+}
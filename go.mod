@@ -11,6 +11,7 @@ require (
 	github.com/kr/pretty v0.2.0 // indirect
 	github.com/leanovate/gopter v0.2.9
 	github.com/pkg/errors v0.9.1 // indirect
+	github.com/prometheus/client_golang v1.11.0
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.16.0
@@ -18,11 +18,17 @@ package cs
 
 import (
 	"fmt"
+	"io"
 	"math/big"
+	"os"
+
+	"github.com/fxamacker/cbor/v2"
 
 	"github.com/consensys/gnark-crypto/ecc"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/backend/ioutils"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 )
@@ -33,6 +39,8 @@ type SparseR1CS struct {
 
 	// Coefficients in the constraints
 	Coefficients []fr.Element // list of unique coefficients.
+
+	loggerOut io.Writer
 }
 
 // NewSparseR1CS returns a new SparseR1CS and sets r1cs.Coefficient (fr.Element) from provided big.Int values
@@ -40,10 +48,13 @@ func NewSparseR1CS(r1cs compiled.SparseR1CS, coefficients []big.Int) *SparseR1CS
 	cs := SparseR1CS{
 		r1cs,
 		make([]fr.Element, len(coefficients)),
+		os.Stdout,
 	}
 	for i := 0; i < len(coefficients); i++ {
 		cs.Coefficients[i].SetBigInt(&coefficients[i])
 	}
+	// the big.Int coefficients are only needed by the frontend, cs.Coefficients replaces them
+	cs.Coeffs, cs.CoeffsIDs = nil, nil
 	return &cs
 }
 
@@ -62,6 +73,31 @@ func (cs *SparseR1CS) CurveID() ecc.ID {
 	return ecc.BLS12_377
 }
 
+// SetLoggerOutput replace existing logger output with provided one
+// default uses os.Stdout
+// if nil is provided, logs are not printed
+func (cs *SparseR1CS) SetLoggerOutput(w io.Writer) {
+	cs.loggerOut = w
+}
+
+// WriteTo encodes SparseR1CS into provided io.Writer using cbor
+func (cs *SparseR1CS) WriteTo(w io.Writer) (int64, error) {
+	_w := ioutils.WriterCounter{W: w} // wraps writer to count the bytes written
+	encoder := cbor.NewEncoder(&_w)
+
+	// encode our object
+	err := encoder.Encode(cs)
+	return _w.N, err
+}
+
+// ReadFrom attempts to decode SparseR1CS from io.Reader using cbor
+func (cs *SparseR1CS) ReadFrom(r io.Reader) (int64, error) {
+	decoder := cbor.NewDecoder(r)
+
+	err := decoder.Decode(cs)
+	return int64(decoder.NumBytesRead()), err
+}
+
 // find unsolved variable
 // returns 0 if the variable to solve is L, 1 if it's R, 2 if it's O
 func findUnsolvedVariable(c compiled.SparseR1C, wireInstantiated []bool) int {
@@ -79,6 +115,8 @@ func findUnsolvedVariable(c compiled.SparseR1C, wireInstantiated []bool) int {
 		// M[1] corresponds to R by default
 		return 1
 	}
+	// C[0], C[1] are on L and C[2] on R: a cubic gate only solves O,
+	// so they are expected to be instantiated at this stage
 	// TODO panic if wire is already instantiated
 	// only O remains
 	return 2
@@ -91,11 +129,47 @@ func (cs *SparseR1CS) computeTerm(t compiled.Term, solution []fr.Element) fr.Ele
 	return res
 }
 
+// computeCubicTerm computes C[0]*C[1]*C[2]
+func (cs *SparseR1CS) computeCubicTerm(c [3]compiled.Term, solution []fr.Element) fr.Element {
+	res := cs.computeTerm(c[0], solution)
+	t := cs.computeTerm(c[1], solution)
+	res.Mul(&res, &t)
+	t = cs.computeTerm(c[2], solution)
+	res.Mul(&res, &t)
+	return res
+}
+
+// lookupTables indexes the entries of the lookup tables of a SparseR1CS for the solver
+type lookupTables struct {
+	entries []map[[3]fr.Element]struct{}   // entries of each table
+	outputs []map[[2]fr.Element]fr.Element // (T1, T2) -> T3 for each table, the first entry is kept
+}
+
+// newLookupTables indexes the lookup tables of cs
+func (cs *SparseR1CS) newLookupTables() lookupTables {
+	res := lookupTables{
+		entries: make([]map[[3]fr.Element]struct{}, len(cs.Tables)),
+		outputs: make([]map[[2]fr.Element]fr.Element, len(cs.Tables)),
+	}
+	for i, table := range cs.Tables {
+		res.entries[i] = make(map[[3]fr.Element]struct{}, len(table))
+		res.outputs[i] = make(map[[2]fr.Element]fr.Element, len(table))
+		for _, e := range table {
+			t1, t2, t3 := cs.Coefficients[e[0]], cs.Coefficients[e[1]], cs.Coefficients[e[2]]
+			res.entries[i][[3]fr.Element{t1, t2, t3}] = struct{}{}
+			if _, ok := res.outputs[i][[2]fr.Element{t1, t2}]; !ok {
+				res.outputs[i][[2]fr.Element{t1, t2}] = t3
+			}
+		}
+	}
+	return res
+}
+
 // solveConstraint solves c with the help of the slices wireInstantiated
 // and solution. Those are used to find which variable remains to be solved,
-// and the way of solving it (binary or single value). Once the variable(s)
+// and the way of solving it (binary, single value or lookup). Once the variable(s)
 // is solved, solution and wireInstantiated are updated.
-func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []bool, solution []fr.Element) {
+func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []bool, solution []fr.Element, tables *lookupTables) {
 
 	switch c.Solver {
 	case compiled.SingleOutput:
@@ -137,7 +211,8 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []b
 			m := cs.computeTerm(c.M[0], solution)
 			_m := cs.computeTerm(c.M[1], solution)
 			m.Mul(&m, &_m)
-			m.Add(&m, &l).Add(&m, &r).Add(&m, &cs.Coefficients[c.K])
+			cu := cs.computeCubicTerm(c.C, solution)
+			m.Add(&m, &l).Add(&m, &r).Add(&m, &cu).Add(&m, &cs.Coefficients[c.K])
 			m.Div(&m, &cs.Coefficients[c.O.CoeffID()])
 
 			solution[c.O.VariableID()].Neg(&m)
@@ -158,6 +233,13 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []b
 		wireInstantiated[c.L.VariableID()] = true
 		wireInstantiated[c.R.VariableID()] = true
 
+	case compiled.TableLookup:
+		// O is read from the table, if (L, R) is not in the table
+		// O is set to zero and checkConstraint fails
+		key := [2]fr.Element{solution[c.L.VariableID()], solution[c.R.VariableID()]}
+		solution[c.O.VariableID()] = tables.outputs[c.Table-1][key]
+		wireInstantiated[c.O.VariableID()] = true
+
 	default:
 		panic("unimplemented solving method")
 	}
@@ -172,7 +254,15 @@ func (cs *SparseR1CS) IsSolved(witness []fr.Element) error {
 }
 
 // checkConstraint verifies that the constraint holds
-func (cs *SparseR1CS) checkConstraint(c compiled.SparseR1C, solution []fr.Element) error {
+// i is the index of the constraint in the constraints then the assertions, for the error
+func (cs *SparseR1CS) checkConstraint(i int, c compiled.SparseR1C, solution []fr.Element, tables *lookupTables) error {
+	if c.Table != 0 {
+		key := [3]fr.Element{solution[c.L.VariableID()], solution[c.R.VariableID()], solution[c.O.VariableID()]}
+		if _, ok := tables.entries[c.Table-1][key]; !ok {
+			return unsatisfiedConstraintError(i, c, fmt.Sprintf("entry not in lookup table %d", c.Table-1))
+		}
+		return nil
+	}
 	var res, a, b, zero fr.Element
 	res = cs.computeTerm(c.L, solution)
 	a = cs.computeTerm(c.R, solution)
@@ -181,16 +271,28 @@ func (cs *SparseR1CS) checkConstraint(c compiled.SparseR1C, solution []fr.Elemen
 	b = cs.computeTerm(c.M[1], solution)
 	a.Mul(&a, &b)
 	res.Add(&res, &a)
+	a = cs.computeCubicTerm(c.C, solution)
+	res.Add(&res, &a)
 	a = cs.computeTerm(c.O, solution)
 	res.Add(&res, &a)
 	a = cs.Coefficients[c.K]
 	res.Add(&res, &a)
 	if !res.Equal(&zero) {
-		return fmt.Errorf("%w", ErrUnsatisfiedConstraint)
+		return unsatisfiedConstraintError(i, c, "")
 	}
 	return nil
 }
 
+// unsatisfiedConstraintError returns the error of the i-th constraint, see backend.UnsatisfiedConstraintError
+func unsatisfiedConstraintError(i int, c compiled.SparseR1C, debugInfo string) error {
+	return &backend.UnsatisfiedConstraintError{
+		ConstraintID: i,
+		DebugInfo:    debugInfo,
+		Wires:        c.WireIDs(),
+		Err:          ErrUnsatisfiedConstraint,
+	}
+}
+
 // Solve sets all the wires.
 // wireValues =  [publicInputs | secretInputs | internalVariables ]
 // witness: contains the input variables
@@ -222,19 +324,20 @@ func (cs *SparseR1CS) Solve(witness []fr.Element) (solution []fr.Element, err er
 	// defer log printing once all wireValues are computed
 	defer cs.printLogs(solution, wireInstantiated)
 
+	tables := cs.newLookupTables()
+
 	// loop through the constraints to solve the variables
 	for i := 0; i < len(cs.Constraints); i++ {
-		cs.solveConstraint(cs.Constraints[i], wireInstantiated, solution)
-		err = cs.checkConstraint(cs.Constraints[i], solution)
+		cs.solveConstraint(cs.Constraints[i], wireInstantiated, solution, &tables)
+		err = cs.checkConstraint(i, cs.Constraints[i], solution, &tables)
 		if err != nil {
-			fmt.Printf("%d-th constraint\n", i)
 			return solution, err
 		}
 	}
 
 	// loop through the assertions and check consistency
 	for i := 0; i < len(cs.Assertions); i++ {
-		err = cs.checkConstraint(cs.Assertions[i], solution)
+		err = cs.checkConstraint(len(cs.Constraints)+i, cs.Assertions[i], solution, &tables)
 		if err != nil {
 			return solution, err
 		}
@@ -249,9 +352,10 @@ func logValue(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated
 	for j := 0; j < len(entry.ToResolve); j++ {
 		wireID := entry.ToResolve[j]
 		if !wireInstantiated[wireID] {
-			panic("wire values was not instantiated")
+			toResolve = append(toResolve, "???")
+		} else {
+			toResolve = append(toResolve, wireValues[wireID].String())
 		}
-		toResolve = append(toResolve, wireValues[wireID].String())
 	}
 	return fmt.Sprintf(entry.Format, toResolve...)
 }
@@ -260,6 +364,9 @@ func (cs *SparseR1CS) printLogs(wireValues []fr.Element, wireInstantiated []bool
 
 	// for each log, resolve the wire values and print the log to stdout
 	for i := 0; i < len(cs.Logs); i++ {
-		fmt.Print(logValue(cs.Logs[i], wireValues, wireInstantiated))
+		logLine := logValue(cs.Logs[i], wireValues, wireInstantiated)
+		if cs.loggerOut != nil {
+			io.WriteString(cs.loggerOut, logLine)
+		}
 	}
 }
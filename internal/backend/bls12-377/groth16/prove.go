@@ -56,6 +56,14 @@ func (proof *Proof) GetCurveID() ecc.ID {
 // if force flag is set, Prove ignores R1CS solving error (ie invalid witness) and executes
 // the FFTs and MultiExponentiations to compute an (invalid) Proof object
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, force bool) (*Proof, error) {
+	// using this ensures that our multiExps running in parallel won't use more than
+	// provided CPUs
+	return ProveWith(r1cs, pk, witness, force, pkMultiExper{pk: pk, cpuSemaphore: ecc.NewCPUSemaphore(runtime.NumCPU())})
+}
+
+// ProveWith behaves like Prove, except the multi-exponentiations over the points of the proving key
+// are computed by msm; pk then only needs the domain and the [α], [β], [δ] points
+func ProveWith(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, force bool, msm MultiExper) (*Proof, error) {
 	if len(witness) != int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables) {
 		return nil, fmt.Errorf("invalid witness size, got %d, expected %d = %d (public - ONE_WIRE) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables, r1cs.NbSecretVariables)
 	}
@@ -110,23 +118,24 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, forc
 	proof := &Proof{}
 	var bs1, ar curve.G1Jac
 
-	// using this ensures that our multiExps running in parallel won't use more than
-	// provided CPUs
-	cpuSemaphore := ecc.NewCPUSemaphore(runtime.NumCPU())
+	// errors of the multi-exponentiations, by MSM
+	var errs [NbMSM]error
 
 	chBs1Done := make(chan struct{}, 1)
 	computeBS1 := func() {
-		bs1.MultiExp(pk.G1.B, wireValues, cpuSemaphore)
-		bs1.AddMixed(&pk.G1.Beta)
-		bs1.AddMixed(&deltas[1])
+		if bs1, errs[MSMB] = msm.MultiExpG1(MSMB, wireValues); errs[MSMB] == nil {
+			bs1.AddMixed(&pk.G1.Beta)
+			bs1.AddMixed(&deltas[1])
+		}
 		chBs1Done <- struct{}{}
 	}
 
 	chArDone := make(chan struct{}, 1)
 	computeAR1 := func() {
-		ar.MultiExp(pk.G1.A, wireValues, cpuSemaphore)
-		ar.AddMixed(&pk.G1.Alpha)
-		ar.AddMixed(&deltas[0])
+		if ar, errs[MSMA] = msm.MultiExpG1(MSMA, wireValues); errs[MSMA] == nil {
+			ar.AddMixed(&pk.G1.Alpha)
+			ar.AddMixed(&deltas[0])
+		}
 		proof.Ar.FromJacobian(&ar)
 		chArDone <- struct{}{}
 	}
@@ -139,10 +148,10 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, forc
 		var krs, krs2, p1 curve.G1Jac
 		chKrs2Done := make(chan struct{}, 1)
 		go func() {
-			krs2.MultiExp(pk.G1.Z, h, cpuSemaphore)
+			krs2, errs[MSMZ] = msm.MultiExpG1(MSMZ, h)
 			chKrs2Done <- struct{}{}
 		}()
-		krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], cpuSemaphore)
+		krs, errs[MSMK] = msm.MultiExpG1(MSMK, wireValues[r1cs.NbPublicVariables:])
 		krs.AddMixed(&deltas[2])
 		n := 3
 		for n != 0 {
@@ -166,32 +175,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, forc
 	computeBS2 := func() {
 		// Bs2 (1 multi exp G2 - size = len(wires))
 		var Bs, deltaS curve.G2Jac
-
-		// splitting Bs2 in 3 ensures all our go routines in the prover have similar running time
-		// and is good for parallelism. However, on a machine with limited CPUs, this may not be
-		// a good idea, as the MultiExp scales slightly better than linearly
-		bsSplit := len(pk.G2.B) / 3
-		if bsSplit > 10 {
-			chDone1 := make(chan struct{}, 1)
-			chDone2 := make(chan struct{}, 1)
-			var bs1, bs2 curve.G2Jac
-			go func() {
-				bs1.MultiExp(pk.G2.B[:bsSplit], wireValues[:bsSplit], cpuSemaphore)
-				chDone1 <- struct{}{}
-			}()
-			go func() {
-				bs2.MultiExp(pk.G2.B[bsSplit:bsSplit*2], wireValues[bsSplit:bsSplit*2], cpuSemaphore)
-				chDone2 <- struct{}{}
-			}()
-			Bs.MultiExp(pk.G2.B[bsSplit*2:], wireValues[bsSplit*2:], cpuSemaphore)
-
-			<-chDone1
-			Bs.AddAssign(&bs1)
-			<-chDone2
-			Bs.AddAssign(&bs2)
-		} else {
-			Bs.MultiExp(pk.G2.B, wireValues, cpuSemaphore)
-		}
+		Bs, errs[MSMG2B] = msm.MultiExpG2(wireValues)
 
 		deltaS.FromAffine(&pk.G2.Delta)
 		deltaS.ScalarMultiplication(&deltaS, &s)
@@ -213,9 +197,127 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, forc
 	// wait for all parts of the proof to be computed.
 	<-chKrsDone
 
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
 	return proof, nil
 }
 
+// MSM identifies the points of the ProvingKey of a multi-exponentiation of Prove
+type MSM int
+
+const (
+	MSMA   MSM = iota // pk.G1.A, with the wire values
+	MSMB              // pk.G1.B, with the wire values
+	MSMZ              // pk.G1.Z, with the coefficients of h
+	MSMK              // pk.G1.K, with the private wire values
+	MSMG2B            // pk.G2.B, with the wire values
+	NbMSM
+)
+
+func (msm MSM) String() string {
+	switch msm {
+	case MSMA:
+		return "G1.A"
+	case MSMB:
+		return "G1.B"
+	case MSMZ:
+		return "G1.Z"
+	case MSMK:
+		return "G1.K"
+	case MSMG2B:
+		return "G2.B"
+	default:
+		return fmt.Sprintf("MSM(%d)", int(msm))
+	}
+}
+
+// MultiExper computes the multi-exponentiations of Prove, see ProveWith
+// scalars are in regular form; the multi-exponentiations of a proof are computed concurrently
+type MultiExper interface {
+	// MultiExpG1 returns Σ scalars[i]·points[i], points being the G1 points of the ProvingKey selected by msm
+	MultiExpG1(msm MSM, scalars []fr.Element) (curve.G1Jac, error)
+
+	// MultiExpG2 returns Σ scalars[i]·pk.G2.B[i]
+	MultiExpG2(scalars []fr.Element) (curve.G2Jac, error)
+}
+
+// pkMultiExper computes the multi-exponentiations of Prove with the points of a full ProvingKey
+type pkMultiExper struct {
+	pk           *ProvingKey
+	cpuSemaphore *ecc.CPUSemaphore
+}
+
+func (m pkMultiExper) MultiExpG1(msm MSM, scalars []fr.Element) (curve.G1Jac, error) {
+	var points []curve.G1Affine
+	switch msm {
+	case MSMA:
+		points = m.pk.G1.A
+	case MSMB:
+		points = m.pk.G1.B
+	case MSMZ:
+		points = m.pk.G1.Z
+	case MSMK:
+		points = m.pk.G1.K
+	}
+	return multiExpG1(msm, points, scalars, m.cpuSemaphore)
+}
+
+func (m pkMultiExper) MultiExpG2(scalars []fr.Element) (curve.G2Jac, error) {
+	var Bs curve.G2Jac
+	points := m.pk.G2.B
+	if len(points) != len(scalars) {
+		return multiExpG2(points, scalars, m.cpuSemaphore)
+	}
+
+	// splitting Bs2 in 3 ensures all our go routines in the prover have similar running time
+	// and is good for parallelism. However, on a machine with limited CPUs, this may not be
+	// a good idea, as the MultiExp scales slightly better than linearly
+	bsSplit := len(points) / 3
+	if bsSplit > 10 {
+		chDone1 := make(chan struct{}, 1)
+		chDone2 := make(chan struct{}, 1)
+		var bs1, bs2 curve.G2Jac
+		go func() {
+			bs1.MultiExp(points[:bsSplit], scalars[:bsSplit], m.cpuSemaphore)
+			chDone1 <- struct{}{}
+		}()
+		go func() {
+			bs2.MultiExp(points[bsSplit:bsSplit*2], scalars[bsSplit:bsSplit*2], m.cpuSemaphore)
+			chDone2 <- struct{}{}
+		}()
+		Bs.MultiExp(points[bsSplit*2:], scalars[bsSplit*2:], m.cpuSemaphore)
+
+		<-chDone1
+		Bs.AddAssign(&bs1)
+		<-chDone2
+		Bs.AddAssign(&bs2)
+	} else {
+		Bs.MultiExp(points, scalars, m.cpuSemaphore)
+	}
+	return Bs, nil
+}
+
+func multiExpG1(msm MSM, points []curve.G1Affine, scalars []fr.Element, cpuSemaphore *ecc.CPUSemaphore) (curve.G1Jac, error) {
+	var res curve.G1Jac
+	if len(points) != len(scalars) {
+		return res, fmt.Errorf("%d points in %s for %d scalars (split proving key?)", len(points), msm, len(scalars))
+	}
+	res.MultiExp(points, scalars, cpuSemaphore)
+	return res, nil
+}
+
+func multiExpG2(points []curve.G2Affine, scalars []fr.Element, cpuSemaphore *ecc.CPUSemaphore) (curve.G2Jac, error) {
+	var res curve.G2Jac
+	if len(points) != len(scalars) {
+		return res, fmt.Errorf("%d points in %s for %d scalars (split proving key?)", len(points), MSMG2B, len(scalars))
+	}
+	res.MultiExp(points, scalars, cpuSemaphore)
+	return res, nil
+}
+
 func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
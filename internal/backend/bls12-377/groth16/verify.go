@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 )
 
 var (
@@ -85,6 +86,96 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls12_377witness.Witne
 	return nil
 }
 
+// BatchVerify verifies the proofs with given VerifyingKey, publicWitnesses[i] being the public witness of proofs[i]
+//
+// the pairing checks of the proofs are batched with a random linear combination (len(proofs)+3 pairings
+// instead of 3 per proof); if the batch doesn't verify, the proofs are verified one by one.
+// It returns the error of each proof, nil if the proof is valid.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []bls12_377witness.Witness) ([]error, error) {
+	if len(proofs) != len(publicWitnesses) {
+		return nil, fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	errs := make([]error, len(proofs))
+
+	// the proofs that can't be valid are left out of the batch
+	batch := make([]int, 0, len(proofs))
+	for i := range proofs {
+		if len(publicWitnesses[i]) != (len(vk.G1.K) - 1) {
+			errs[i] = fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitnesses[i]), len(vk.G1.K)-1)
+		} else if !proofs[i].isValid() {
+			errs[i] = errCorrectSubgroupCheckFailed
+		} else {
+			batch = append(batch, i)
+		}
+	}
+	if len(batch) < 2 {
+		for _, i := range batch {
+			errs[i] = Verify(proofs[i], vk, publicWitnesses[i])
+		}
+		return errs, nil
+	}
+
+	ok, err := batchPairingCheck(proofs, vk, publicWitnesses, batch)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		for _, i := range batch {
+			errs[i] = Verify(proofs[i], vk, publicWitnesses[i])
+		}
+	}
+	return errs, nil
+}
+
+// batchPairingCheck checks the random linear combination of the pairing equations of the proofs in batch
+//
+// with random r_i, Verify's equation e(Ar, Bs) = e(α, β).e(Σx.[Kvk(t)]1, [γ]2).e(Krs, [δ]2) holds for all proofs if
+// Π e(r_i.Ar_i, Bs_i) . e(Σr_i.Krs_i, -[δ]2) . e(Σr_i.Σx_i.[Kvk(t)]1, -[γ]2) . e(-(Σr_i).[α]1, [β]2) == 1
+func batchPairingCheck(proofs []*Proof, vk *VerifyingKey, publicWitnesses []bls12_377witness.Witness, batch []int) (bool, error) {
+	n := len(batch)
+	p := make([]curve.G1Affine, 0, n+3)
+	q := make([]curve.G2Affine, 0, n+3)
+
+	r := make([]fr.Element, n)
+	krs := make([]curve.G1Affine, n)
+	// scalars of the public inputs: [Σr_i, Σr_i.x_i1, Σr_i.x_i2, ...]
+	kScalars := make([]fr.Element, len(vk.G1.K))
+	var rSum fr.Element
+	var bRi big.Int
+	for j, i := range batch {
+		if _, err := r[j].SetRandom(); err != nil {
+			return false, err
+		}
+		rSum.Add(&rSum, &r[j])
+		var t fr.Element
+		for k := range publicWitnesses[i] {
+			t.Mul(&r[j], &publicWitnesses[i][k])
+			kScalars[k+1].Add(&kScalars[k+1], &t)
+		}
+
+		var ar curve.G1Affine
+		ar.ScalarMultiplication(&proofs[i].Ar, r[j].ToBigIntRegular(&bRi))
+		p = append(p, ar)
+		q = append(q, proofs[i].Bs)
+		krs[j] = proofs[i].Krs
+		r[j] = r[j].ToRegular()
+	}
+	kScalars[0] = rSum
+	for k := range kScalars {
+		kScalars[k] = kScalars[k].ToRegular()
+	}
+
+	var krsSum, kSum, alpha curve.G1Affine
+	krsSum.MultiExp(krs, r)
+	kSum.MultiExp(vk.G1.K, kScalars)
+	alpha.ScalarMultiplication(&vk.G1.Alpha, rSum.ToBigIntRegular(&bRi))
+	alpha.Neg(&alpha)
+	p = append(p, krsSum, kSum, alpha)
+	q = append(q, vk.G2.deltaNeg, vk.G2.gammaNeg, vk.G2.Beta)
+
+	return curve.PairingCheck(p, q)
+}
+
 // ExportSolidity not implemented for BLS12-377
 func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
 	return errors.New("not implemented")
@@ -25,6 +25,7 @@ import (
 
 	"github.com/fxamacker/cbor/v2"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/backend/compiled"
 	"github.com/consensys/gnark/internal/backend/ioutils"
 
@@ -149,7 +150,7 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			return fmt.Errorf("%w: %s", ErrUnsatisfiedConstraint, "couldn't solve computational constraint. May happen: div by 0 or no inverse found")
+			return r1cs.unsatisfiedConstraintError(i, "couldn't solve computational constraint. May happen: div by 0 or no inverse found")
 		}
 	}
 
@@ -166,13 +167,23 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 		if !check.Equal(&c[i]) {
 			debugInfo := r1cs.DebugInfo[i-int(r1cs.NbCOConstraints)]
 			debugInfoStr := r1cs.logValue(debugInfo, wireValues, wireInstantiated)
-			return fmt.Errorf("%w: %s", ErrUnsatisfiedConstraint, debugInfoStr)
+			return r1cs.unsatisfiedConstraintError(i, debugInfoStr)
 		}
 	}
 
 	return nil
 }
 
+// unsatisfiedConstraintError returns the error of the i-th constraint, see backend.UnsatisfiedConstraintError
+func (r1cs *R1CS) unsatisfiedConstraintError(i int, debugInfo string) error {
+	return &backend.UnsatisfiedConstraintError{
+		ConstraintID: i,
+		DebugInfo:    debugInfo,
+		Wires:        r1cs.Constraints[i].WireIDs(),
+		Err:          ErrUnsatisfiedConstraint,
+	}
+}
+
 func (r1cs *R1CS) logValue(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
 	var toResolve []interface{}
 	for j := 0; j < len(entry.ToResolve); j++ {
@@ -0,0 +1,240 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package groth16
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// ProvingKeyChunk holds a range of the points of each multi-exponentiation of a ProvingKey, see SplitProvingKey
+//
+// it computes the multi-exponentiations over its points, the scalars being the matching range of the scalars
+// of Prove: the sum of the results of the chunks of a ProvingKey is the result of the full multi-exponentiation.
+type ProvingKeyChunk struct {
+	// the chunk holds the points [Start[msm], Start[msm]+Len(msm)) of the Total[msm] points of the msm
+	Start, Total [NbMSM]int
+
+	G1 struct {
+		A, B, Z, K []curve.G1Affine
+	}
+	G2 struct {
+		B []curve.G2Affine
+	}
+}
+
+// Len returns the number of points of the msm in the chunk
+func (c *ProvingKeyChunk) Len(msm MSM) int {
+	if msm == MSMG2B {
+		return len(c.G2.B)
+	}
+	return len(c.g1Points(msm))
+}
+
+func (c *ProvingKeyChunk) g1Points(msm MSM) []curve.G1Affine {
+	switch msm {
+	case MSMA:
+		return c.G1.A
+	case MSMB:
+		return c.G1.B
+	case MSMZ:
+		return c.G1.Z
+	case MSMK:
+		return c.G1.K
+	default:
+		return nil
+	}
+}
+
+// MultiExpG1 returns Σ scalars[i]·points[i] over the G1 points of the chunk selected by msm
+func (c *ProvingKeyChunk) MultiExpG1(msm MSM, scalars []fr.Element) (curve.G1Jac, error) {
+	return multiExpG1(msm, c.g1Points(msm), scalars, ecc.NewCPUSemaphore(runtime.NumCPU()))
+}
+
+// MultiExpG2 returns Σ scalars[i]·points[i] over the G2 points of the chunk
+func (c *ProvingKeyChunk) MultiExpG2(scalars []fr.Element) (curve.G2Jac, error) {
+	return multiExpG2(c.G2.B, scalars, ecc.NewCPUSemaphore(runtime.NumCPU()))
+}
+
+// WriteTo writes the chunk, with uncompressed points
+func (c *ProvingKeyChunk) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	for msm := MSM(0); msm < NbMSM; msm++ {
+		var points interface{} = c.g1Points(msm)
+		if msm == MSMG2B {
+			points = c.G2.B
+		}
+		for _, v := range []interface{}{uint64(c.Start[msm]), uint64(c.Total[msm]), points} {
+			if err := enc.Encode(v); err != nil {
+				return enc.BytesWritten(), err
+			}
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads a chunk written by WriteTo or SplitProvingKey
+func (c *ProvingKeyChunk) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	for msm := MSM(0); msm < NbMSM; msm++ {
+		var start, total uint64
+		var points interface{}
+		switch msm {
+		case MSMA:
+			points = &c.G1.A
+		case MSMB:
+			points = &c.G1.B
+		case MSMZ:
+			points = &c.G1.Z
+		case MSMK:
+			points = &c.G1.K
+		case MSMG2B:
+			points = &c.G2.B
+		}
+		for _, v := range []interface{}{&start, &total, points} {
+			if err := dec.Decode(v); err != nil {
+				return dec.BytesRead(), err
+			}
+		}
+		c.Start[msm], c.Total[msm] = int(start), int(total)
+		if c.Start[msm]+c.Len(msm) > c.Total[msm] {
+			return dec.BytesRead(), fmt.Errorf("invalid chunk: %d points from %d in %s of %d points", c.Len(msm), c.Start[msm], msm, c.Total[msm])
+		}
+	}
+	return dec.BytesRead(), nil
+}
+
+// SplitProvingKey reads a ProvingKey (written by WriteTo or WriteRawTo) and splits its points in len(chunks) ProvingKeyChunk
+//
+// the points of each multi-exponentiation are split in ranges of (almost) the same size. The ProvingKey without
+// its multi-exponentiation points is written to pk: it is the key ProveWith expects.
+// The ProvingKey is streamed, it is never fully held in memory.
+func SplitProvingKey(r io.Reader, pk io.Writer, chunks []io.Writer) error {
+	if len(chunks) == 0 {
+		return errors.New("no chunks to split the proving key into")
+	}
+	br := bufio.NewReader(r)
+	var split ProvingKey
+	if _, err := split.Domain.ReadFrom(br); err != nil {
+		return err
+	}
+	dec := curve.NewDecoder(br)
+	for _, v := range []interface{}{&split.G1.Alpha, &split.G1.Beta, &split.G1.Delta} {
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+	}
+
+	bws := make([]*bufio.Writer, len(chunks))
+	encs := make([]*curve.Encoder, len(chunks))
+	for i, w := range chunks {
+		bws[i] = bufio.NewWriter(w)
+		encs[i] = curve.NewEncoder(bws[i], curve.RawEncoding())
+	}
+
+	// the points are encoded in the order of the MSMs, with the [β]2, [δ]2 points before pk.G2.B
+	for msm := MSM(0); msm < NbMSM; msm++ {
+		if msm == MSMG2B {
+			for _, v := range []interface{}{&split.G2.Beta, &split.G2.Delta} {
+				if err := dec.Decode(v); err != nil {
+					return err
+				}
+			}
+		}
+		var total uint32
+		if err := binary.Read(br, binary.BigEndian, &total); err != nil {
+			return err
+		}
+		for i, enc := range encs {
+			start, end := chunkRange(int(total), i, len(chunks))
+			if err := enc.Encode(uint64(start)); err != nil {
+				return err
+			}
+			if err := enc.Encode(uint64(total)); err != nil {
+				return err
+			}
+			if err := binary.Write(bws[i], binary.BigEndian, uint32(end-start)); err != nil {
+				return err
+			}
+			for j := start; j < end; j++ {
+				var err error
+				if msm == MSMG2B {
+					var p curve.G2Affine
+					if err = dec.Decode(&p); err == nil {
+						err = enc.Encode(&p)
+					}
+				} else {
+					var p curve.G1Affine
+					if err = dec.Decode(&p); err == nil {
+						err = enc.Encode(&p)
+					}
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, bw := range bws {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	_, err := split.WriteTo(pk)
+	return err
+}
+
+// chunkRange returns the range of the i-th of n chunks of total points
+func chunkRange(total, i, n int) (start, end int) {
+	return i * total / n, (i + 1) * total / n
+}
+
+// MarshalScalars encodes scalars in regular form, fr.Bytes (big endian) per scalar
+func MarshalScalars(scalars []fr.Element) []byte {
+	buf := make([]byte, len(scalars)*fr.Bytes)
+	for i := range scalars {
+		for j := 0; j < fr.Limbs; j++ {
+			binary.BigEndian.PutUint64(buf[i*fr.Bytes+(fr.Limbs-1-j)*8:], scalars[i][j])
+		}
+	}
+	return buf
+}
+
+// UnmarshalScalars decodes scalars encoded by MarshalScalars
+func UnmarshalScalars(buf []byte) ([]fr.Element, error) {
+	if len(buf)%fr.Bytes != 0 {
+		return nil, fmt.Errorf("invalid scalars size %d, expected a multiple of %d", len(buf), fr.Bytes)
+	}
+	scalars := make([]fr.Element, len(buf)/fr.Bytes)
+	for i := range scalars {
+		for j := 0; j < fr.Limbs; j++ {
+			scalars[i][j] = binary.BigEndian.Uint64(buf[i*fr.Bytes+(fr.Limbs-1-j)*8:])
+		}
+	}
+	return scalars, nil
+}
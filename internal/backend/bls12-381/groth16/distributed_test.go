@@ -0,0 +1,166 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package groth16_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/consensys/gnark/internal/backend/bls12-381/cs"
+
+	bls12_381witness "github.com/consensys/gnark/internal/backend/bls12-381/witness"
+
+	bls12_381groth16 "github.com/consensys/gnark/internal/backend/bls12-381/groth16"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// chunksMultiExper sums the multi-exponentiations of the chunks of a proving key
+type chunksMultiExper []*bls12_381groth16.ProvingKeyChunk
+
+func (m chunksMultiExper) MultiExpG1(msm bls12_381groth16.MSM, scalars []fr.Element) (curve.G1Jac, error) {
+	var res curve.G1Jac
+	for _, c := range m {
+		p, err := c.MultiExpG1(msm, scalars[c.Start[msm]:c.Start[msm]+c.Len(msm)])
+		if err != nil {
+			return res, err
+		}
+		res.AddAssign(&p)
+	}
+	return res, nil
+}
+
+func (m chunksMultiExper) MultiExpG2(scalars []fr.Element) (curve.G2Jac, error) {
+	var res curve.G2Jac
+	for _, c := range m {
+		p, err := c.MultiExpG2(scalars[c.Start[bls12_381groth16.MSMG2B] : c.Start[bls12_381groth16.MSMG2B]+c.Len(bls12_381groth16.MSMG2B)])
+		if err != nil {
+			return res, err
+		}
+		res.AddAssign(&p)
+	}
+	return res, nil
+}
+
+func TestSplitProvingKey(t *testing.T) {
+	const nbChunks = 3
+	r1cs, err := frontend.Compile(curve.ID, backend.GROTH16, &refCircuit{nbConstraints: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pk bls12_381groth16.ProvingKey
+	var vk bls12_381groth16.VerifyingKey
+	if err := bls12_381groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk); err != nil {
+		t.Fatal(err)
+	}
+
+	// y = x^(2^10)
+	var x, y fr.Element
+	x.SetUint64(3)
+	y.Set(&x)
+	for i := 0; i < 10; i++ {
+		y.Square(&y)
+	}
+	var assignment refCircuit
+	assignment.X.Assign(x)
+	assignment.Y.Assign(y)
+	var fullWitness, publicWitness bls12_381witness.Witness
+	if err := fullWitness.FromFullAssignment(&assignment); err != nil {
+		t.Fatal(err)
+	}
+	if err := publicWitness.FromPublicAssignment(&assignment); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range []bool{false, true} {
+		var bPk bytes.Buffer
+		if raw {
+			_, err = pk.WriteRawTo(&bPk)
+		} else {
+			_, err = pk.WriteTo(&bPk)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var bSplit bytes.Buffer
+		bChunks := make([]bytes.Buffer, nbChunks)
+		writers := make([]io.Writer, nbChunks)
+		for i := range bChunks {
+			writers[i] = &bChunks[i]
+		}
+		if err := bls12_381groth16.SplitProvingKey(&bPk, &bSplit, writers); err != nil {
+			t.Fatal(err)
+		}
+
+		var split bls12_381groth16.ProvingKey
+		if _, err := split.ReadFrom(&bSplit); err != nil {
+			t.Fatal(err)
+		}
+		if len(split.G1.A) != 0 || len(split.G2.B) != 0 || !split.G1.Alpha.Equal(&pk.G1.Alpha) || !split.G2.Delta.Equal(&pk.G2.Delta) {
+			t.Fatal("split proving key should only have the domain and the α, β, δ points")
+		}
+		chunks := make(chunksMultiExper, nbChunks)
+		for i := range chunks {
+			chunks[i] = new(bls12_381groth16.ProvingKeyChunk)
+			if _, err := chunks[i].ReadFrom(&bChunks[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if chunks[1].Start[bls12_381groth16.MSMA] != len(chunks[0].G1.A) || chunks[2].Total[bls12_381groth16.MSMK] != len(pk.G1.K) {
+			t.Fatal("chunks should hold consecutive ranges of the points")
+		}
+
+		// the proof computed with the chunks is valid, the split key can't prove alone
+		proof, err := bls12_381groth16.ProveWith(r1cs.(*cs.R1CS), &split, fullWitness, false, chunks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bls12_381groth16.Verify(proof, &vk, publicWitness); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bls12_381groth16.Prove(r1cs.(*cs.R1CS), &split, fullWitness, false); err == nil {
+			t.Fatal("proving with a split proving key should fail")
+		}
+	}
+}
+
+func TestMarshalScalars(t *testing.T) {
+	scalars := make([]fr.Element, 10)
+	for i := range scalars {
+		scalars[i].SetRandom()
+		scalars[i].FromMont()
+	}
+	decoded, err := bls12_381groth16.UnmarshalScalars(bls12_381groth16.MarshalScalars(scalars))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range scalars {
+		if !scalars[i].Equal(&decoded[i]) {
+			t.Fatal("scalars don't match")
+		}
+	}
+	if _, err := bls12_381groth16.UnmarshalScalars(make([]byte, fr.Bytes+1)); err == nil {
+		t.Fatal("truncated scalars should fail")
+	}
+}
@@ -50,6 +50,66 @@ func TestCircuits(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	const nbProofs = 5
+	circuit := refCircuit{nbConstraints: 3}
+	r1cs, err := frontend.Compile(curve.ID, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pk bls12_381groth16.ProvingKey
+	var vk bls12_381groth16.VerifyingKey
+	if err := bls12_381groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs := make([]*bls12_381groth16.Proof, nbProofs)
+	publicWitnesses := make([]bls12_381witness.Witness, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		// y = x^(2^3)
+		var x, y fr.Element
+		x.SetUint64(uint64(i + 2))
+		y.Square(&x).Square(&y).Square(&y)
+		var assignment refCircuit
+		assignment.X.Assign(x)
+		assignment.Y.Assign(y)
+
+		var fullWitness bls12_381witness.Witness
+		if err := fullWitness.FromFullAssignment(&assignment); err != nil {
+			t.Fatal(err)
+		}
+		if err := publicWitnesses[i].FromPublicAssignment(&assignment); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = bls12_381groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs, err := bls12_381groth16.BatchVerify(proofs, &vk, publicWitnesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("proof %d: %v", i, err)
+		}
+	}
+
+	// swapped public witnesses, and a witness of the wrong size
+	publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+	publicWitnesses[4] = append(publicWitnesses[4], fr.One())
+	errs, err = bls12_381groth16.BatchVerify(proofs, &vk, publicWitnesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 1 || i == 3 || i == 4) {
+			t.Fatalf("proof %d: unexpected result %v", i, err)
+		}
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
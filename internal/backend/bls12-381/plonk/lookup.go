@@ -0,0 +1,443 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package plonk
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+
+	"github.com/consensys/gnark/internal/backend/bls12-381/cs"
+
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// The lookup argument follows plookup (https://eprint.iacr.org/2020/315).
+//
+// The lookup tables are concatenated in a single table t with 4 columns (t1, t2, t3, id),
+// where id is the ID+1 of the table an entry belongs to, padded with its last entry.
+// For a challenge eta, the tables and the lookups are compressed as
+//
+// 	t = t1+eta*t2+eta**2*t3+eta**3*id
+// 	f = qlookup*(l+eta*r+eta**2*o+eta**3*qtable) + (1-qlookup)*t[n-1]
+//
+// so that f is not committed, it is computed from l, r, o and the selectors. The sorted
+// version s of (f[:n-1], t) is split in h1=s[:n], h2=s[n-1:], and the prover shows that
+//
+// 	(X-z**(n-1))*(Zl*(1+beta)*(gamma+f)*(gamma(1+beta)+t+beta*t(zX))
+// 		- Zl(zX)*(gamma(1+beta)+h1+beta*h1(zX))*(gamma(1+beta)+h2+beta*h2(zX))) = 0
+// 	L1*(Zl-1) = 0
+// 	Ln*(h1-h2(zX)) = 0
+// 	Ln*(Zl-1) = 0
+//
+// on <z>, where Zl is the lookup accumulator polynomial, and z**(n-1) the last row
+// (which is never a lookup).
+
+// lookupTableSize returns the number of entries of the concatenated lookup tables
+func lookupTableSize(spr *cs.SparseR1CS) int {
+	res := 0
+	for i := 0; i < len(spr.Tables); i++ {
+		res += len(spr.Tables[i])
+	}
+	return res
+}
+
+// hasLookups returns true if the circuit uses lookup tables
+func (publicData *PublicRaw) hasLookups() bool {
+	return len(publicData.Qlookup) != 0
+}
+
+// setupLookup sets qlookup, qtable (canonical basis) and the columns of the
+// concatenated lookup table (Lagrange and canonical basis).
+func setupLookup(spr *cs.SparseR1CS, publicData *PublicRaw) {
+
+	nbElmts := int(publicData.DomainNum.Cardinality)
+
+	// selectors: [ placholders | constraints | assertions ], the placeholders are not lookups
+	publicData.Qlookup = make(bls12381.Polynomial, nbElmts)
+	publicData.Qtable = make(bls12381.Polynomial, nbElmts)
+	offset := spr.NbPublicVariables
+	for i := 0; i < len(spr.Constraints); i++ {
+		if spr.Constraints[i].Table != 0 {
+			publicData.Qlookup[offset+i].SetOne()
+			publicData.Qtable[offset+i].SetUint64(uint64(spr.Constraints[i].Table))
+		}
+	}
+	offset += len(spr.Constraints)
+	for i := 0; i < len(spr.Assertions); i++ {
+		if spr.Assertions[i].Table != 0 {
+			publicData.Qlookup[offset+i].SetOne()
+			publicData.Qtable[offset+i].SetUint64(uint64(spr.Assertions[i].Table))
+		}
+	}
+	publicData.DomainNum.FFTInverse(publicData.Qlookup, fft.DIF, 0)
+	publicData.DomainNum.FFTInverse(publicData.Qtable, fft.DIF, 0)
+	fft.BitReverse(publicData.Qlookup)
+	fft.BitReverse(publicData.Qtable)
+
+	// concatenated tables, padded with the last entry
+	for k := 0; k < 4; k++ {
+		publicData.LTable[k] = make(bls12381.Polynomial, nbElmts)
+	}
+	i := 0
+	for id := 0; id < len(spr.Tables); id++ {
+		for _, e := range spr.Tables[id] {
+			publicData.LTable[0][i].Set(&spr.Coefficients[e[0]])
+			publicData.LTable[1][i].Set(&spr.Coefficients[e[1]])
+			publicData.LTable[2][i].Set(&spr.Coefficients[e[2]])
+			publicData.LTable[3][i].SetUint64(uint64(id + 1))
+			i++
+		}
+	}
+	for ; i < nbElmts; i++ {
+		for k := 0; k < 4; k++ {
+			publicData.LTable[k][i].Set(&publicData.LTable[k][i-1])
+		}
+	}
+
+	for k := 0; k < 4; k++ {
+		publicData.CTable[k] = make(bls12381.Polynomial, nbElmts)
+		copy(publicData.CTable[k], publicData.LTable[k])
+		publicData.DomainNum.FFTInverse(publicData.CTable[k], fft.DIF, 0)
+		fft.BitReverse(publicData.CTable[k])
+	}
+}
+
+// compressTable returns t1+eta*t2+eta**2*t3+eta**3*t4, in the basis of the columns of table
+func compressTable(table [4]bls12381.Polynomial, eta fr.Element) bls12381.Polynomial {
+	res := make(bls12381.Polynomial, len(table[0]))
+	for i := 0; i < len(res); i++ {
+		res[i].Mul(&table[3][i], &eta).
+			Add(&res[i], &table[2][i]).
+			Mul(&res[i], &eta).
+			Add(&res[i], &table[1][i]).
+			Mul(&res[i], &eta).
+			Add(&res[i], &table[0][i])
+	}
+	return res
+}
+
+// computeLookupF returns f in Lagrange basis, that is l+eta*r+eta**2*o+eta**3*table
+// on the lookup constraints, and t[n-1] elsewhere.
+//
+// l, r, o are the solution in Lagrange basis, t is the compressed table in Lagrange basis.
+func computeLookupF(spr *cs.SparseR1CS, publicData *PublicRaw, l, r, o, t bls12381.Polynomial, eta fr.Element) bls12381.Polynomial {
+
+	nbElmts := int(publicData.DomainNum.Cardinality)
+	f := make(bls12381.Polynomial, nbElmts)
+	for i := 0; i < nbElmts; i++ {
+		f[i].Set(&t[nbElmts-1])
+	}
+
+	compress := func(i, table int) {
+		var id fr.Element
+		id.SetUint64(uint64(table))
+		f[i].Mul(&id, &eta).
+			Add(&f[i], &o[i]).
+			Mul(&f[i], &eta).
+			Add(&f[i], &r[i]).
+			Mul(&f[i], &eta).
+			Add(&f[i], &l[i])
+	}
+	offset := spr.NbPublicVariables
+	for i := 0; i < len(spr.Constraints); i++ {
+		if spr.Constraints[i].Table != 0 {
+			compress(offset+i, spr.Constraints[i].Table)
+		}
+	}
+	offset += len(spr.Constraints)
+	for i := 0; i < len(spr.Assertions); i++ {
+		if spr.Assertions[i].Table != 0 {
+			compress(offset+i, spr.Assertions[i].Table)
+		}
+	}
+
+	return f
+}
+
+// computeSortedLookup returns h1, h2 in Lagrange basis, where s=(f[:n-1], t) sorted
+// by t is split in h1=s[:n] and h2=s[n-1:].
+//
+// The values of f that are not in t (if the witness is wrong) are put at the end
+// of s, so the proof will not verify.
+func computeSortedLookup(f, t bls12381.Polynomial) (bls12381.Polynomial, bls12381.Polynomial) {
+
+	nbElmts := len(t)
+
+	// index of the first occurence of each value of t
+	index := make(map[fr.Element]int, nbElmts)
+	for i := nbElmts - 1; i >= 0; i-- {
+		index[t[i]] = i
+	}
+
+	// number of values of f to insert after t[i]
+	count := make([]int, nbElmts)
+	var missing []fr.Element
+	for i := 0; i < nbElmts-1; i++ {
+		if j, ok := index[f[i]]; ok {
+			count[j]++
+		} else {
+			missing = append(missing, f[i])
+		}
+	}
+
+	s := make([]fr.Element, 0, 2*nbElmts-1)
+	for i := 0; i < nbElmts; i++ {
+		s = append(s, t[i])
+		for j := 0; j < count[i]; j++ {
+			s = append(s, t[i])
+		}
+	}
+	s = append(s, missing...)
+
+	h1 := make(bls12381.Polynomial, nbElmts)
+	h2 := make(bls12381.Polynomial, nbElmts)
+	copy(h1, s[:nbElmts])
+	copy(h2, s[nbElmts-1:])
+
+	return h1, h2
+}
+
+// ComputeZLookup computes Zl (in Lagrange basis), the lookup accumulator polynomial, where
+//
+// * Zl(1)=1
+// 								   (1+beta)*(gamma+f_k)*(gamma(1+beta)+t_k+beta*t_k+1)
+//	* for i>0: Zl(z**i) = Pi_{k<i} ---------------------------------------------------------------------------
+//								     (gamma(1+beta)+h1_k+beta*h1_k+1)*(gamma(1+beta)+h2_k+beta*h2_k+1)
+//
+//	* f, t, h1, h2 are in Lagrange basis
+func ComputeZLookup(f, t, h1, h2 bls12381.Polynomial, beta, gamma fr.Element) bls12381.Polynomial {
+
+	nbElmts := len(t)
+	z := make(bls12381.Polynomial, nbElmts)
+
+	var oneBeta, gammaOneBeta fr.Element
+	oneBeta.SetOne().Add(&oneBeta, &beta)
+	gammaOneBeta.Mul(&gamma, &oneBeta)
+
+	// the ratios of the products are computed in parallel, the running product is then
+	// accumulated sequentially
+	ratios := make([]fr.Element, nbElmts-1)
+	utils.Parallelize(nbElmts-1, func(start, end int) {
+		var num, den, buf fr.Element
+		for i := start; i < end; i++ {
+
+			num.Add(&gamma, &f[i]).Mul(&num, &oneBeta)                        // (1+beta)*(gamma+f_i)
+			buf.Mul(&beta, &t[i+1]).Add(&buf, &t[i]).Add(&buf, &gammaOneBeta) // gamma(1+beta)+t_i+beta*t_i+1
+			num.Mul(&num, &buf)
+
+			den.Mul(&beta, &h1[i+1]).Add(&den, &h1[i]).Add(&den, &gammaOneBeta) // gamma(1+beta)+h1_i+beta*h1_i+1
+			buf.Mul(&beta, &h2[i+1]).Add(&buf, &h2[i]).Add(&buf, &gammaOneBeta) // gamma(1+beta)+h2_i+beta*h2_i+1
+			den.Mul(&den, &buf)
+
+			ratios[i].Div(&num, &den)
+		}
+	})
+
+	z[0].SetOne()
+	for i := 0; i < nbElmts-1; i++ {
+		z[i+1].Mul(&z[i], &ratios[i])
+	}
+
+	return z
+}
+
+// shiftCosets turns the evaluations of p on the odd cosets of (Z/8mZ)/(Z/mZ)
+// into the evaluations of p(zX): res[4i+j] = p(u**(2j+1)*z**i) so p(zX) is a
+// rotation by 4 of the evaluations.
+func shiftCosets(evalP bls12381.Polynomial) bls12381.Polynomial {
+	res := make(bls12381.Polynomial, len(evalP))
+	copy(res, evalP[4:])
+	copy(res[len(evalP)-4:], evalP[:4])
+	return res
+}
+
+// evalLookup computes the evaluation of
+//
+// 	(X-z**(n-1))*(Zl*(1+beta)*(gamma+f)*(gamma(1+beta)+t+beta*t(zX))
+// 		- Zl(zX)*(gamma(1+beta)+h1+beta*h1(zX))*(gamma(1+beta)+h2+beta*h2(zX)))
+// 	+ alpha*L1*(Zl-1) + alpha**2*Ln*(h1-h2(zX)) + alpha**3*Ln*(Zl-1)
+//
+// on the odd cosets of (Z/8mZ)/(Z/mZ).
+//
+// evalL, evalR, evalO are the evaluations of the solution on the odd cosets,
+// t (the compressed table), h1, h2, zl are in canonical form.
+func evalLookup(publicData *PublicRaw, evalL, evalR, evalO, t, h1, h2, zl bls12381.Polynomial, eta, beta, gamma, alpha fr.Element) bls12381.Polynomial {
+
+	nbElmts := 4 * int(publicData.DomainNum.Cardinality)
+
+	// evaluation of the polynomials and their shifts on the odd cosets of (Z/8mZ)/(Z/mZ)
+	// (Ln is evaluated along with them)
+	lastLagrange := make(bls12381.Polynomial, publicData.DomainNum.Cardinality)
+	lastLagrange[publicData.DomainNum.Cardinality-1].SetOne()
+	toCanonical(publicData.DomainNum, lastLagrange)
+	evals := evaluateCosetsConcurrently(publicData.DomainNum, publicData.Qlookup, publicData.Qtable, t, h1, h2, zl, lastLagrange)
+	evalQlookup, evalQtable, evalT, evalH1, evalH2, evalZl, evalLn := evals[0], evals[1], evals[2], evals[3], evals[4], evals[5], evals[6]
+	evalTz := shiftCosets(evalT)
+	evalH1z := shiftCosets(evalH1)
+	evalH2z := shiftCosets(evalH2)
+	evalZlz := shiftCosets(evalZl)
+
+	// evaluation of L1*(Zl-1) on the odd cosets of (Z/8mZ)/(Z/mZ)
+	startsAtOne := evalStartsAtOne(publicData, evalZl)
+
+	// evaluation of X-z**(n-1) on the odd cosets of (Z/8mZ)/(Z/mZ)
+	evalID, _, _ := evalIDCosets(publicData)
+
+	// t[n-1], the value of f on the rows which are not lookups
+	var last fr.Element
+	for k := 3; k >= 0; k-- {
+		last.Mul(&last, &eta).Add(&last, &publicData.LTable[k][publicData.DomainNum.Cardinality-1])
+	}
+
+	var oneBeta, gammaOneBeta, one fr.Element
+	one.SetOne()
+	oneBeta.Add(&one, &beta)
+	gammaOneBeta.Mul(&gamma, &oneBeta)
+
+	res := make(bls12381.Polynomial, nbElmts)
+	utils.Parallelize(nbElmts, func(start, end int) {
+		var f, num, den, buf fr.Element
+		for i := start; i < end; i++ {
+
+			// f = qlookup*(l+eta*r+eta**2*o+eta**3*qtable) + (1-qlookup)*t[n-1]
+			f.Mul(&evalQtable[i], &eta).
+				Add(&f, &evalO[i]).
+				Mul(&f, &eta).
+				Add(&f, &evalR[i]).
+				Mul(&f, &eta).
+				Add(&f, &evalL[i]).
+				Sub(&f, &last).
+				Mul(&f, &evalQlookup[i]).
+				Add(&f, &last)
+
+			num.Add(&gamma, &f).Mul(&num, &oneBeta)                                  // (1+beta)*(gamma+f)
+			buf.Mul(&beta, &evalTz[i]).Add(&buf, &evalT[i]).Add(&buf, &gammaOneBeta) // gamma(1+beta)+t+beta*t(zX)
+			num.Mul(&num, &buf).Mul(&num, &evalZl[i])
+
+			den.Mul(&beta, &evalH1z[i]).Add(&den, &evalH1[i]).Add(&den, &gammaOneBeta) // gamma(1+beta)+h1+beta*h1(zX)
+			buf.Mul(&beta, &evalH2z[i]).Add(&buf, &evalH2[i]).Add(&buf, &gammaOneBeta) // gamma(1+beta)+h2+beta*h2(zX)
+			den.Mul(&den, &buf).Mul(&den, &evalZlz[i])
+
+			buf.Sub(&evalID[i], &publicData.DomainNum.GeneratorInv) // X-z**(n-1)
+			res[i].Sub(&num, &den).Mul(&res[i], &buf)
+
+			// alpha**3*Ln*(Zl-1) + alpha**2*Ln*(h1-h2(zX)) + alpha*L1*(Zl-1)
+			num.Sub(&evalZl[i], &one).Mul(&num, &alpha)
+			buf.Sub(&evalH1[i], &evalH2z[i])
+			num.Add(&num, &buf).Mul(&num, &evalLn[i]).Mul(&num, &alpha)
+			num.Add(&num, &startsAtOne[i]).Mul(&num, &alpha)
+
+			res[i].Add(&res[i], &num)
+		}
+	})
+
+	return res
+}
+
+// evalLookupAtZeta computes the evaluation at zeta of
+//
+// 	(X-z**(n-1))*(Zl*(1+beta)*(gamma+f)*(gamma(1+beta)+t+beta*t(zX))
+// 		- Zl(zX)*(gamma(1+beta)+h1+beta*h1(zX))*(gamma(1+beta)+h2+beta*h2(zX)))
+// 	+ alpha*L1*(Zl-1) + alpha**2*Ln*(h1-h2(zX)) + alpha**3*Ln*(Zl-1)
+//
+// from the claimed values of the proof, lro being the claimed values of l (completed), r, o.
+func evalLookupAtZeta(proof *ProofRaw, publicData *PublicRaw, lro [3]fr.Element, zeta, eta, beta, gamma, alpha fr.Element) fr.Element {
+
+	var one, zzeta fr.Element
+	one.SetOne()
+	zzeta.Mul(&zeta, &publicData.DomainNum.Generator)
+
+	// evaluation of qlookup, qtable, t, t(zX) at zeta
+	var qlookup, qtable fr.Element
+	_qlookup := publicData.Qlookup.Eval(&zeta)
+	_qtable := publicData.Qtable.Eval(&zeta)
+	qlookup.Set(_qlookup.(*fr.Element))
+	qtable.Set(_qtable.(*fr.Element))
+	ct := compressTable(publicData.CTable, eta)
+	var t, tz fr.Element
+	_t := ct.Eval(&zeta)
+	_tz := ct.Eval(&zzeta)
+	t.Set(_t.(*fr.Element))
+	tz.Set(_tz.(*fr.Element))
+
+	// t[n-1], the value of f on the rows which are not lookups
+	var last fr.Element
+	for k := 3; k >= 0; k-- {
+		last.Mul(&last, &eta).Add(&last, &publicData.LTable[k][publicData.DomainNum.Cardinality-1])
+	}
+
+	// f = qlookup*(l+eta*r+eta**2*o+eta**3*qtable) + (1-qlookup)*t[n-1]
+	var f fr.Element
+	f.Mul(&qtable, &eta).
+		Add(&f, &lro[2]).
+		Mul(&f, &eta).
+		Add(&f, &lro[1]).
+		Mul(&f, &eta).
+		Add(&f, &lro[0]).
+		Sub(&f, &last).
+		Mul(&f, &qlookup).
+		Add(&f, &last)
+
+	h1, h2, zl := proof.Lookup[0], proof.Lookup[1], proof.Lookup[2]
+	h1z, h2z, zlz := proof.LookupShift[0], proof.LookupShift[1], proof.LookupShift[2]
+
+	var oneBeta, gammaOneBeta, num, den, buf, res fr.Element
+	oneBeta.Add(&one, &beta)
+	gammaOneBeta.Mul(&gamma, &oneBeta)
+
+	num.Add(&gamma, &f).Mul(&num, &oneBeta)                    // (1+beta)*(gamma+f)
+	buf.Mul(&beta, &tz).Add(&buf, &t).Add(&buf, &gammaOneBeta) // gamma(1+beta)+t+beta*t(zX)
+	num.Mul(&num, &buf).Mul(&num, &zl)
+
+	den.Mul(&beta, &h1z).Add(&den, &h1).Add(&den, &gammaOneBeta) // gamma(1+beta)+h1+beta*h1(zX)
+	buf.Mul(&beta, &h2z).Add(&buf, &h2).Add(&buf, &gammaOneBeta) // gamma(1+beta)+h2+beta*h2(zX)
+	den.Mul(&den, &buf).Mul(&den, &zlz)
+
+	buf.Sub(&zeta, &publicData.DomainNum.GeneratorInv) // zeta-z**(n-1)
+	res.Sub(&num, &den).Mul(&res, &buf)
+
+	// L1(zeta) = 1/m*(zeta**m-1)/(zeta-1), Ln(zeta) = z**(n-1)/m*(zeta**m-1)/(zeta-z**(n-1))
+	var bExpo big.Int
+	var zetaPowerMMinusOne, l1, ln fr.Element
+	bExpo.SetUint64(publicData.DomainNum.Cardinality)
+	zetaPowerMMinusOne.Exp(zeta, &bExpo).Sub(&zetaPowerMMinusOne, &one)
+	l1.Sub(&zeta, &one).
+		Inverse(&l1).
+		Mul(&l1, &zetaPowerMMinusOne).
+		Mul(&l1, &publicData.DomainNum.CardinalityInv)
+	ln.Inverse(&buf).
+		Mul(&ln, &zetaPowerMMinusOne).
+		Mul(&ln, &publicData.DomainNum.CardinalityInv).
+		Mul(&ln, &publicData.DomainNum.GeneratorInv)
+
+	// alpha**3*Ln*(Zl-1) + alpha**2*Ln*(h1-h2(zX)) + alpha*L1*(Zl-1)
+	var acc fr.Element
+	num.Sub(&zl, &one).Mul(&num, &alpha)
+	buf.Sub(&h1, &h2z)
+	num.Add(&num, &buf).Mul(&num, &ln).Mul(&num, &alpha)
+	acc.Sub(&zl, &one).Mul(&acc, &l1)
+	num.Add(&num, &acc).Mul(&num, &alpha)
+
+	res.Add(&res, &num)
+
+	return res
+}
@@ -19,7 +19,9 @@ package witness
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 
 	"github.com/consensys/gnark/frontend"
@@ -138,6 +140,52 @@ func (witness *Witness) FromPublicAssignment(w frontend.Circuit) error {
 	return parser.Visit(w, "", compiled.Unset, collectHandler, reflect.TypeOf(frontend.Variable{}))
 }
 
+// ToFullAssignment assigns the full witness [ public | secret ] to the variables of w, which must be unassigned
+// values are assigned as *big.Int, in regular form
+func (witness *Witness) ToFullAssignment(w frontend.Circuit) error {
+	nbSecret, nbPublic := count(w)
+	if len(*witness) != nbPublic+nbSecret {
+		return fmt.Errorf("witness has %d elements, expected %d public and %d secret variables", len(*witness), nbPublic, nbSecret)
+	}
+	return witness.assign(w, nbPublic, true)
+}
+
+// ToPublicAssignment assigns the public witness to the public variables of w, which must be unassigned
+// the secret variables are left unassigned
+func (witness *Witness) ToPublicAssignment(w frontend.Circuit) error {
+	_, nbPublic := count(w)
+	if len(*witness) != nbPublic {
+		return fmt.Errorf("public witness has %d elements, expected %d public variables", len(*witness), nbPublic)
+	}
+	return witness.assign(w, nbPublic, false)
+}
+
+// assign sets the variables of w from the witness [ public | secret ], the secret ones only if full is set
+func (witness *Witness) assign(w frontend.Circuit, nbPublic int, full bool) error {
+	var i, j int // indexes for secret / public variables
+	i = nbPublic // offset
+
+	var assignHandler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
+		var k int
+		if visibility == compiled.Secret && full {
+			k = i
+			i++
+		} else if visibility == compiled.Public {
+			k = j
+			j++
+		} else {
+			return nil
+		}
+		v := tInput.Addr().Interface().(*frontend.Variable)
+		if frontend.GetAssignedValue(*v) != nil {
+			return errors.New("variable " + name + " already assigned")
+		}
+		v.Assign((*witness)[k].ToBigIntRegular(new(big.Int)))
+		return nil
+	}
+	return parser.Visit(w, "", compiled.Unset, assignHandler, reflect.TypeOf(frontend.Variable{}))
+}
+
 func count(w frontend.Circuit) (nbSecret, nbPublic int) {
 	var collectHandler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
 		if visibility == compiled.Secret {
@@ -17,9 +17,10 @@
 package groth16
 
 import (
-	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+
 	"github.com/consensys/gnark/internal/backend/bn254/cs"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
@@ -30,6 +31,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/internal/utils"
 	"math/big"
+	"runtime"
 )
 
 // Proof represents a Groth16 proof that was encoded with a ProvingKey and can be verified
@@ -50,15 +52,49 @@ func (proof *Proof) GetCurveID() ecc.ID {
 	return curve.ID
 }
 
+// Prove generates the proof of knoweldge of a r1cs with full witness (secret + public part).
+// if force flag is set, Prove ignores R1CS solving error (ie invalid witness) and executes
+// the FFTs and MultiExponentiations to compute an (invalid) Proof object
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bn254witness.Witness, force bool) (*Proof, error) {
+	// using this ensures that our multiExps running in parallel won't use more than
+	// provided CPUs
+	return ProveWith(r1cs, pk, witness, force, pkMultiExper{pk: pk, cpuSemaphore: ecc.NewCPUSemaphore(runtime.NumCPU())})
+}
+
+// ProveWith behaves like Prove, except the multi-exponentiations over the points of the proving key
+// are computed by msm; pk then only needs the domain and the [α], [β], [δ] points
+func ProveWith(r1cs *cs.R1CS, pk *ProvingKey, witness bn254witness.Witness, force bool, msm MultiExper) (*Proof, error) {
 	if len(witness) != int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables) {
 		return nil, fmt.Errorf("invalid witness size, got %d, expected %d = %d (public - ONE_WIRE) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables, r1cs.NbSecretVariables)
 	}
-	wireValues, h, err := computeWires(r1cs, pk, witness, force)
-	if wireValues == nil || h == nil || err != nil {
+
+	// solve the R1CS and compute the a, b, c vectors
+	a := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
+	b := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
+	c := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
+	wireValues := make([]fr.Element, r1cs.NbInternalVariables+r1cs.NbPublicVariables+r1cs.NbSecretVariables)
+	if err := r1cs.Solve(witness, a, b, c, wireValues); err != nil && !force {
 		return nil, err
 	}
 
+	// set the wire values in regular form
+	utils.Parallelize(len(wireValues), func(start, end int) {
+		for i := start; i < end; i++ {
+			wireValues[i].FromMont()
+		}
+	})
+
+	// H (witness reduction / FFT part)
+	var h []fr.Element
+	chHDone := make(chan struct{}, 1)
+	go func() {
+		h = computeH(a, b, c, &pk.Domain)
+		a = nil
+		b = nil
+		c = nil
+		chHDone <- struct{}{}
+	}()
+
 	// sample random r and s
 	var r, s big.Int
 	var _r, _s, _kr fr.Element
@@ -79,59 +115,207 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bn254witness.Witness, force bo
 	// computes r[δ], s[δ], kr[δ]
 	deltas := curve.BatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr})
 
-	bs1 := computeBS1(pk, wireValues, deltas)
-	ar := computeAR1(pk, wireValues, deltas)
-
-	krs := computeKRS(r1cs, pk, wireValues, deltas, h, ar, &s, &r, bs1)
-	bs2 := computeBS2(pk, &s, wireValues)
 	proof := &Proof{}
-	proof.Krs.FromJacobian(&krs)
-	proof.Ar.FromJacobian(&ar)
-	proof.Bs.FromJacobian(&bs2)
+	var bs1, ar curve.G1Jac
+
+	// errors of the multi-exponentiations, by MSM
+	var errs [NbMSM]error
+
+	chBs1Done := make(chan struct{}, 1)
+	computeBS1 := func() {
+		if bs1, errs[MSMB] = msm.MultiExpG1(MSMB, wireValues); errs[MSMB] == nil {
+			bs1.AddMixed(&pk.G1.Beta)
+			bs1.AddMixed(&deltas[1])
+		}
+		chBs1Done <- struct{}{}
+	}
+
+	chArDone := make(chan struct{}, 1)
+	computeAR1 := func() {
+		if ar, errs[MSMA] = msm.MultiExpG1(MSMA, wireValues); errs[MSMA] == nil {
+			ar.AddMixed(&pk.G1.Alpha)
+			ar.AddMixed(&deltas[0])
+		}
+		proof.Ar.FromJacobian(&ar)
+		chArDone <- struct{}{}
+	}
+
+	chKrsDone := make(chan struct{}, 1)
+	computeKRS := func() {
+		// we could NOT split the Krs multiExp in 2, and just append pk.G1.K and pk.G1.Z
+		// however, having similar lengths for our tasks helps with parallelism
+
+		var krs, krs2, p1 curve.G1Jac
+		chKrs2Done := make(chan struct{}, 1)
+		go func() {
+			krs2, errs[MSMZ] = msm.MultiExpG1(MSMZ, h)
+			chKrs2Done <- struct{}{}
+		}()
+		krs, errs[MSMK] = msm.MultiExpG1(MSMK, wireValues[r1cs.NbPublicVariables:])
+		krs.AddMixed(&deltas[2])
+		n := 3
+		for n != 0 {
+			select {
+			case <-chKrs2Done:
+				krs.AddAssign(&krs2)
+			case <-chArDone:
+				p1.ScalarMultiplication(&ar, &s)
+				krs.AddAssign(&p1)
+			case <-chBs1Done:
+				p1.ScalarMultiplication(&bs1, &r)
+				krs.AddAssign(&p1)
+			}
+			n--
+		}
+
+		proof.Krs.FromJacobian(&krs)
+		chKrsDone <- struct{}{}
+	}
+
+	computeBS2 := func() {
+		// Bs2 (1 multi exp G2 - size = len(wires))
+		var Bs, deltaS curve.G2Jac
+		Bs, errs[MSMG2B] = msm.MultiExpG2(wireValues)
+
+		deltaS.FromAffine(&pk.G2.Delta)
+		deltaS.ScalarMultiplication(&deltaS, &s)
+		Bs.AddAssign(&deltaS)
+		Bs.AddMixed(&pk.G2.Beta)
+
+		proof.Bs.FromJacobian(&Bs)
+	}
+
+	// wait for FFT to end, as it uses all our CPUs
+	<-chHDone
+
+	// schedule our proof part computations
+	go computeKRS()
+	go computeAR1()
+	go computeBS1()
+	computeBS2()
+
+	// wait for all parts of the proof to be computed.
+	<-chKrsDone
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
 	return proof, nil
 }
 
-func computeBS2(pk *ProvingKey, s *big.Int, wireValues []fr.Element) curve.G2Jac {
-	cpuSemaphore := ecc.NewCPUSemaphore(1)
-	var Bs, deltaS curve.G2Jac
-	Bs.MultiExp(pk.G2.B, wireValues, cpuSemaphore)
-	deltaS.FromAffine(&pk.G2.Delta)
-	deltaS.ScalarMultiplication(&deltaS, s)
-	Bs.AddAssign(&deltaS)
-	Bs.AddMixed(&pk.G2.Beta)
-	return Bs
+// MSM identifies the points of the ProvingKey of a multi-exponentiation of Prove
+type MSM int
+
+const (
+	MSMA   MSM = iota // pk.G1.A, with the wire values
+	MSMB              // pk.G1.B, with the wire values
+	MSMZ              // pk.G1.Z, with the coefficients of h
+	MSMK              // pk.G1.K, with the private wire values
+	MSMG2B            // pk.G2.B, with the wire values
+	NbMSM
+)
+
+func (msm MSM) String() string {
+	switch msm {
+	case MSMA:
+		return "G1.A"
+	case MSMB:
+		return "G1.B"
+	case MSMZ:
+		return "G1.Z"
+	case MSMK:
+		return "G1.K"
+	case MSMG2B:
+		return "G2.B"
+	default:
+		return fmt.Sprintf("MSM(%d)", int(msm))
+	}
+}
+
+// MultiExper computes the multi-exponentiations of Prove, see ProveWith
+// scalars are in regular form; the multi-exponentiations of a proof are computed concurrently
+type MultiExper interface {
+	// MultiExpG1 returns Σ scalars[i]·points[i], points being the G1 points of the ProvingKey selected by msm
+	MultiExpG1(msm MSM, scalars []fr.Element) (curve.G1Jac, error)
+
+	// MultiExpG2 returns Σ scalars[i]·pk.G2.B[i]
+	MultiExpG2(scalars []fr.Element) (curve.G2Jac, error)
+}
+
+// pkMultiExper computes the multi-exponentiations of Prove with the points of a full ProvingKey
+type pkMultiExper struct {
+	pk           *ProvingKey
+	cpuSemaphore *ecc.CPUSemaphore
+}
+
+func (m pkMultiExper) MultiExpG1(msm MSM, scalars []fr.Element) (curve.G1Jac, error) {
+	var points []curve.G1Affine
+	switch msm {
+	case MSMA:
+		points = m.pk.G1.A
+	case MSMB:
+		points = m.pk.G1.B
+	case MSMZ:
+		points = m.pk.G1.Z
+	case MSMK:
+		points = m.pk.G1.K
+	}
+	return multiExpG1(msm, points, scalars, m.cpuSemaphore)
 }
 
-func computeBS1(pk *ProvingKey, wireValues []fr.Element, deltas []curve.G1Affine) curve.G1Jac {
-	cpuSemaphore := ecc.NewCPUSemaphore(1)
-	var bs1 curve.G1Jac
-	bs1.MultiExp(pk.G1.B, wireValues, cpuSemaphore)
-	bs1.AddMixed(&pk.G1.Beta)
-	bs1.AddMixed(&deltas[1])
-	return bs1
+func (m pkMultiExper) MultiExpG2(scalars []fr.Element) (curve.G2Jac, error) {
+	var Bs curve.G2Jac
+	points := m.pk.G2.B
+	if len(points) != len(scalars) {
+		return multiExpG2(points, scalars, m.cpuSemaphore)
+	}
+
+	// splitting Bs2 in 3 ensures all our go routines in the prover have similar running time
+	// and is good for parallelism. However, on a machine with limited CPUs, this may not be
+	// a good idea, as the MultiExp scales slightly better than linearly
+	bsSplit := len(points) / 3
+	if bsSplit > 10 {
+		chDone1 := make(chan struct{}, 1)
+		chDone2 := make(chan struct{}, 1)
+		var bs1, bs2 curve.G2Jac
+		go func() {
+			bs1.MultiExp(points[:bsSplit], scalars[:bsSplit], m.cpuSemaphore)
+			chDone1 <- struct{}{}
+		}()
+		go func() {
+			bs2.MultiExp(points[bsSplit:bsSplit*2], scalars[bsSplit:bsSplit*2], m.cpuSemaphore)
+			chDone2 <- struct{}{}
+		}()
+		Bs.MultiExp(points[bsSplit*2:], scalars[bsSplit*2:], m.cpuSemaphore)
+
+		<-chDone1
+		Bs.AddAssign(&bs1)
+		<-chDone2
+		Bs.AddAssign(&bs2)
+	} else {
+		Bs.MultiExp(points, scalars, m.cpuSemaphore)
+	}
+	return Bs, nil
 }
 
-func computeAR1(pk *ProvingKey, wireValues []fr.Element, deltas []curve.G1Affine) curve.G1Jac {
-	cpuSemaphore := ecc.NewCPUSemaphore(1)
-	var ar curve.G1Jac
-	ar.MultiExp(pk.G1.A, wireValues, cpuSemaphore)
-	ar.AddMixed(&pk.G1.Alpha)
-	ar.AddMixed(&deltas[0])
-	return ar
+func multiExpG1(msm MSM, points []curve.G1Affine, scalars []fr.Element, cpuSemaphore *ecc.CPUSemaphore) (curve.G1Jac, error) {
+	var res curve.G1Jac
+	if len(points) != len(scalars) {
+		return res, fmt.Errorf("%d points in %s for %d scalars (split proving key?)", len(points), msm, len(scalars))
+	}
+	res.MultiExp(points, scalars, cpuSemaphore)
+	return res, nil
 }
 
-func computeKRS(r1cs *cs.R1CS, pk *ProvingKey, wireValues []fr.Element, deltas []curve.G1Affine, h []fr.Element, ar curve.G1Jac, s *big.Int, r *big.Int, bs1 curve.G1Jac) curve.G1Jac {
-	cpuSemaphore := ecc.NewCPUSemaphore(1)
-	var krs, krs2, p1 curve.G1Jac
-	krs2.MultiExp(pk.G1.Z, h, cpuSemaphore)
-	krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], cpuSemaphore)
-	krs.AddMixed(&deltas[2])
-	krs.AddAssign(&krs2)
-	p1.ScalarMultiplication(&ar, s)
-	krs.AddAssign(&p1)
-	p1.ScalarMultiplication(&bs1, r)
-	krs.AddAssign(&p1)
-	return krs
+func multiExpG2(points []curve.G2Affine, scalars []fr.Element, cpuSemaphore *ecc.CPUSemaphore) (curve.G2Jac, error) {
+	var res curve.G2Jac
+	if len(points) != len(scalars) {
+		return res, fmt.Errorf("%d points in %s for %d scalars (split proving key?)", len(points), MSMG2B, len(scalars))
+	}
+	res.MultiExp(points, scalars, cpuSemaphore)
+	return res, nil
 }
 
 func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
@@ -184,20 +368,3 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 
 	return a
 }
-
-func computeWires(r1cs *cs.R1CS, pk *ProvingKey, witness bn254witness.Witness, force bool) ([]fr.Element, []fr.Element, error) {
-	a := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
-	b := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
-	c := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
-	wireValues := make([]fr.Element, r1cs.NbInternalVariables+r1cs.NbPublicVariables+r1cs.NbSecretVariables)
-	if err := r1cs.Solve(witness, a, b, c, wireValues); err != nil && !force {
-		return nil, nil, err
-	}
-	for i := 0; i < len(wireValues); i++ {
-		wireValues[i].FromMont()
-	}
-	var h []fr.Element
-	//chHDone := make(chan struct{}, 1)
-	h = computeH(a, b, c, &pk.Domain)
-	return wireValues, h, nil
-}
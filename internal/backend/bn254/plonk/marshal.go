@@ -0,0 +1,346 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package plonk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial/mockcommitment"
+	"github.com/consensys/gnark-crypto/polynomial"
+
+	"github.com/consensys/gnark/internal/backend/ioutils"
+)
+
+// maxEncodedSize bounds the size of an encoded commitment or opening proof
+// read by ProofRaw.ReadFrom
+const maxEncodedSize = 1 << 20
+
+var errInvalidEncodedSize = errors.New("invalid encoded commitment or opening proof size")
+
+// WriteTo writes binary encoding of the proof to writer:
+// LROZH | ZShift | Lookup | LookupShift | CommitmentsLROZH | BatchOpenings | OpeningZShift | [lookup commitments and openings]
+// claimed values are fr elements in regular (big endian) form, commitments are encoded
+// through Digest.Bytes() and opening proofs through their WriteTo method, both prefixed by their size (uint32).
+// The commitments and openings of the lookup argument are prefixed by a flag (1 byte) telling if they are set.
+func (proof *ProofRaw) WriteTo(w io.Writer) (int64, error) {
+	_w := ioutils.WriterCounter{W: w} // wraps writer to count the bytes written
+
+	values := make([]fr.Element, 0, len(proof.LROZH)+1+len(proof.Lookup)+len(proof.LookupShift))
+	values = append(values, proof.LROZH[:]...)
+	values = append(values, proof.ZShift)
+	values = append(values, proof.Lookup[:]...)
+	values = append(values, proof.LookupShift[:]...)
+	for i := 0; i < len(values); i++ {
+		buf := values[i].Bytes()
+		if _, err := _w.Write(buf[:]); err != nil {
+			return _w.N, err
+		}
+	}
+
+	for i := 0; i < len(proof.CommitmentsLROZH); i++ {
+		if err := writeEncoded(&_w, proof.CommitmentsLROZH[i].Bytes()); err != nil {
+			return _w.N, err
+		}
+	}
+	if err := writeWriterTo(&_w, proof.BatchOpenings); err != nil {
+		return _w.N, err
+	}
+	if err := writeWriterTo(&_w, proof.OpeningZShift); err != nil {
+		return _w.N, err
+	}
+
+	hasLookups := proof.CommitmentsLookup[0] != nil
+	flag := []byte{0}
+	if hasLookups {
+		flag[0] = 1
+	}
+	if _, err := _w.Write(flag); err != nil {
+		return _w.N, err
+	}
+	if !hasLookups {
+		return _w.N, nil
+	}
+	for i := 0; i < len(proof.CommitmentsLookup); i++ {
+		if err := writeEncoded(&_w, proof.CommitmentsLookup[i].Bytes()); err != nil {
+			return _w.N, err
+		}
+	}
+	if err := writeWriterTo(&_w, proof.BatchOpeningsLookup); err != nil {
+		return _w.N, err
+	}
+	if err := writeWriterTo(&_w, proof.BatchOpeningsLookupShift); err != nil {
+		return _w.N, err
+	}
+
+	return _w.N, nil
+}
+
+// ReadFrom attempts to decode a proof from reader, proof must be encoded through WriteTo.
+// The commitments and opening proofs are kept in their encoded form (see EncodedDigest and EncodedOpeningProof),
+// VerifyRaw decodes them through the commitment scheme, see SchemeDecoder.
+func (proof *ProofRaw) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+
+	var buf [fr.Limbs * 8]byte
+	readValue := func(e *fr.Element) error {
+		read, err := io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return err
+		}
+		e.SetBytes(buf[:])
+		return nil
+	}
+	for i := 0; i < len(proof.LROZH); i++ {
+		if err := readValue(&proof.LROZH[i]); err != nil {
+			return n, err
+		}
+	}
+	if err := readValue(&proof.ZShift); err != nil {
+		return n, err
+	}
+	for i := 0; i < len(proof.Lookup); i++ {
+		if err := readValue(&proof.Lookup[i]); err != nil {
+			return n, err
+		}
+	}
+	for i := 0; i < len(proof.LookupShift); i++ {
+		if err := readValue(&proof.LookupShift[i]); err != nil {
+			return n, err
+		}
+	}
+
+	for i := 0; i < len(proof.CommitmentsLROZH); i++ {
+		b, err := readEncoded(r, &n)
+		if err != nil {
+			return n, err
+		}
+		proof.CommitmentsLROZH[i] = EncodedDigest(b)
+	}
+	b, err := readEncoded(r, &n)
+	if err != nil {
+		return n, err
+	}
+	proof.BatchOpenings = EncodedOpeningProof(b)
+	if b, err = readEncoded(r, &n); err != nil {
+		return n, err
+	}
+	proof.OpeningZShift = EncodedOpeningProof(b)
+
+	flag := []byte{0}
+	read, err := io.ReadFull(r, flag)
+	n += int64(read)
+	if err != nil {
+		return n, err
+	}
+	if flag[0] == 0 {
+		proof.CommitmentsLookup = [3]polynomial.Digest{}
+		proof.BatchOpeningsLookup, proof.BatchOpeningsLookupShift = nil, nil
+		return n, nil
+	}
+	for i := 0; i < len(proof.CommitmentsLookup); i++ {
+		b, err := readEncoded(r, &n)
+		if err != nil {
+			return n, err
+		}
+		proof.CommitmentsLookup[i] = EncodedDigest(b)
+	}
+	if b, err = readEncoded(r, &n); err != nil {
+		return n, err
+	}
+	proof.BatchOpeningsLookup = EncodedOpeningProof(b)
+	if b, err = readEncoded(r, &n); err != nil {
+		return n, err
+	}
+	proof.BatchOpeningsLookupShift = EncodedOpeningProof(b)
+
+	return n, nil
+}
+
+// EncodedDigest is a commitment read by ProofRaw.ReadFrom, as encoded by Digest.Bytes()
+type EncodedDigest []byte
+
+// WriteTo writes the encoded commitment to w
+func (d EncodedDigest) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(d)
+	return int64(n), err
+}
+
+// ReadFrom is not supported, an EncodedDigest is read through ProofRaw.ReadFrom
+func (d EncodedDigest) ReadFrom(r io.Reader) (int64, error) {
+	return 0, errors.New("EncodedDigest is read through ProofRaw.ReadFrom")
+}
+
+// Bytes returns the encoded commitment
+func (d EncodedDigest) Bytes() []byte {
+	return d
+}
+
+// EncodedOpeningProof is an opening proof read by ProofRaw.ReadFrom, as encoded by its WriteTo method
+type EncodedOpeningProof []byte
+
+// WriteTo writes the encoded opening proof to w
+func (p EncodedOpeningProof) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(p)
+	return int64(n), err
+}
+
+// ReadFrom is not supported, an EncodedOpeningProof is read through ProofRaw.ReadFrom
+func (p EncodedOpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	return 0, errors.New("EncodedOpeningProof is read through ProofRaw.ReadFrom")
+}
+
+// SchemeDecoder decodes the commitments and opening proofs of a polynomial commitment scheme,
+// as encoded by ProofRaw.WriteTo
+//
+// VerifyRaw needs the commitment scheme to implement it to verify a proof read by ProofRaw.ReadFrom;
+// gnark-crypto's mock commitment scheme is decoded by this package.
+type SchemeDecoder interface {
+	DecodeDigest(b []byte) (polynomial.Digest, error)
+	DecodeOpeningProof(b []byte) (polynomial.OpeningProof, error)
+	DecodeBatchOpeningProof(b []byte) (polynomial.BatchOpeningProofSinglePoint, error)
+}
+
+// schemeDecoder returns the SchemeDecoder of the commitment scheme
+func schemeDecoder(scheme polynomial.CommitmentScheme) (SchemeDecoder, error) {
+	switch s := scheme.(type) {
+	case *mockcommitment.Scheme:
+		return mockDecoder{}, nil
+	case SchemeDecoder:
+		return s, nil
+	default:
+		return nil, fmt.Errorf("commitment scheme %T can't decode serialized proofs", scheme)
+	}
+}
+
+// decode returns a copy of the proof where the commitments and opening proofs read by ReadFrom
+// are decoded by the commitment scheme; proofs computed by ProveRaw are returned as is
+func (proof *ProofRaw) decode(scheme polynomial.CommitmentScheme) (*ProofRaw, error) {
+	if _, ok := proof.CommitmentsLROZH[0].(EncodedDigest); !ok {
+		return proof, nil
+	}
+	decoder, err := schemeDecoder(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	res := *proof
+	for i := 0; i < len(res.CommitmentsLROZH); i++ {
+		if res.CommitmentsLROZH[i], err = decoder.DecodeDigest(proof.CommitmentsLROZH[i].(EncodedDigest)); err != nil {
+			return nil, err
+		}
+	}
+	if res.BatchOpenings, err = decoder.DecodeBatchOpeningProof(proof.BatchOpenings.(EncodedOpeningProof)); err != nil {
+		return nil, err
+	}
+	if res.OpeningZShift, err = decoder.DecodeOpeningProof(proof.OpeningZShift.(EncodedOpeningProof)); err != nil {
+		return nil, err
+	}
+	if proof.CommitmentsLookup[0] == nil {
+		return &res, nil
+	}
+	for i := 0; i < len(res.CommitmentsLookup); i++ {
+		if res.CommitmentsLookup[i], err = decoder.DecodeDigest(proof.CommitmentsLookup[i].(EncodedDigest)); err != nil {
+			return nil, err
+		}
+	}
+	if res.BatchOpeningsLookup, err = decoder.DecodeBatchOpeningProof(proof.BatchOpeningsLookup.(EncodedOpeningProof)); err != nil {
+		return nil, err
+	}
+	if res.BatchOpeningsLookupShift, err = decoder.DecodeBatchOpeningProof(proof.BatchOpeningsLookupShift.(EncodedOpeningProof)); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// mockDecoder decodes the commitments and opening proofs of the mock commitment scheme
+type mockDecoder struct{}
+
+// DecodeDigest decodes a mock commitment, the constant coefficient of the polynomial
+func (mockDecoder) DecodeDigest(b []byte) (polynomial.Digest, error) {
+	if len(b) != fr.Limbs*8 {
+		return nil, errInvalidEncodedSize
+	}
+	var e fr.Element
+	e.SetBytes(b)
+	if c := e.Bytes(); !bytes.Equal(c[:], b) {
+		return nil, errors.New("invalid mock commitment")
+	}
+	var scheme mockcommitment.Scheme
+	return scheme.Commit(bn254.Polynomial{e}), nil
+}
+
+// DecodeOpeningProof decodes a mock opening proof, which is empty
+func (mockDecoder) DecodeOpeningProof(b []byte) (polynomial.OpeningProof, error) {
+	if len(b) != 0 {
+		return nil, errInvalidEncodedSize
+	}
+	return &mockcommitment.MockProof{}, nil
+}
+
+// DecodeBatchOpeningProof decodes a mock batch opening proof, which is empty
+func (mockDecoder) DecodeBatchOpeningProof(b []byte) (polynomial.BatchOpeningProofSinglePoint, error) {
+	if len(b) != 0 {
+		return nil, errInvalidEncodedSize
+	}
+	return &mockcommitment.MockBatchProofsSinglePoint{}, nil
+}
+
+// writeEncoded writes b prefixed by its size
+func writeEncoded(w io.Writer, b []byte) error {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+	if _, err := w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// writeWriterTo writes the encoding of o prefixed by its size
+func writeWriterTo(w io.Writer, o io.WriterTo) error {
+	var buf bytes.Buffer
+	if _, err := o.WriteTo(&buf); err != nil {
+		return err
+	}
+	return writeEncoded(w, buf.Bytes())
+}
+
+// readEncoded reads a slice written by writeEncoded and adds the number of bytes read to n
+func readEncoded(r io.Reader, n *int64) ([]byte, error) {
+	var size [4]byte
+	read, err := io.ReadFull(r, size[:])
+	*n += int64(read)
+	if err != nil {
+		return nil, err
+	}
+	s := binary.BigEndian.Uint32(size[:])
+	if s > maxEncodedSize {
+		return nil, errInvalidEncodedSize
+	}
+	b := make([]byte, s)
+	read, err = io.ReadFull(r, b)
+	*n += int64(read)
+	return b, err
+}
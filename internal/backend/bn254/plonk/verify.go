@@ -34,11 +34,29 @@ var (
 // VerifyRaw verifies a PLONK proof
 func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witness.Witness) error {
 
+	// the commitments and opening proofs of a deserialized proof are decoded by the commitment scheme
+	proof, err := proof.decode(publicData.CommitmentScheme)
+	if err != nil {
+		return err
+	}
+
 	// create a transcript manager to apply Fiat Shamir and get the challenges
-	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "gamma", "alpha", "zeta")
-	fs.Bind("gamma", proof.CommitmentsLROZH[0].Bytes())
-	fs.Bind("gamma", proof.CommitmentsLROZH[1].Bytes())
-	fs.Bind("gamma", proof.CommitmentsLROZH[2].Bytes())
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "eta", "gamma", "beta", "alpha", "zeta")
+	if err := bindDigests(&fs, "eta", proof.CommitmentsLROZH[:3]...); err != nil {
+		return err
+	}
+	bEta, err := fs.ComputeChallenge("eta")
+	if err != nil {
+		return err
+	}
+	var eta fr.Element
+	eta.SetBytes(bEta)
+
+	if publicData.hasLookups() {
+		if err := bindDigests(&fs, "gamma", proof.CommitmentsLookup[:2]...); err != nil {
+			return err
+		}
+	}
 	bgamma, err := fs.ComputeChallenge("gamma")
 	if err != nil {
 		return err
@@ -46,7 +64,21 @@ func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witnes
 	var gamma fr.Element
 	gamma.SetBytes(bgamma)
 
-	fs.Bind("alpha", proof.CommitmentsLROZH[3].Bytes())
+	bbeta, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		return err
+	}
+	var beta fr.Element
+	beta.SetBytes(bbeta)
+
+	if err := bindDigests(&fs, "alpha", proof.CommitmentsLROZH[3]); err != nil {
+		return err
+	}
+	if publicData.hasLookups() {
+		if err := bindDigests(&fs, "alpha", proof.CommitmentsLookup[2]); err != nil {
+			return err
+		}
+	}
 	balpha, err := fs.ComputeChallenge("alpha")
 	if err != nil {
 		return err
@@ -54,9 +86,9 @@ func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witnes
 	var alpha fr.Element
 	alpha.SetBytes(balpha)
 
-	fs.Bind("zeta", proof.CommitmentsLROZH[4].Bytes())
-	fs.Bind("zeta", proof.CommitmentsLROZH[5].Bytes())
-	fs.Bind("zeta", proof.CommitmentsLROZH[6].Bytes())
+	if err := bindDigests(&fs, "zeta", proof.CommitmentsLROZH[4:]...); err != nil {
+		return err
+	}
 	bzeta, err := fs.ComputeChallenge("zeta")
 	if err != nil {
 		return err
@@ -73,17 +105,31 @@ func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witnes
 	if err != nil {
 		return err
 	}
+	if publicData.hasLookups() {
+		var shiftedZeta fr.Element
+		shiftedZeta.Mul(&zeta, &publicData.DomainNum.Generator)
+		err = publicData.CommitmentScheme.BatchVerifySinglePoint(&zeta, proof.Lookup, proof.CommitmentsLookup, proof.BatchOpeningsLookup)
+		if err != nil {
+			return err
+		}
+		err = publicData.CommitmentScheme.BatchVerifySinglePoint(&shiftedZeta, proof.LookupShift, proof.CommitmentsLookup, proof.BatchOpeningsLookupShift)
+		if err != nil {
+			return err
+		}
+	}
 
-	// evaluation of ql, qr, qm, qo, qk at zeta
-	var ql, qr, qm, qo, qk fr.Element
+	// evaluation of ql, qr, qm, qc, qo, qk at zeta
+	var ql, qr, qm, qc, qo, qk fr.Element
 	_ql := publicData.Ql.Eval(&zeta)
 	_qr := publicData.Qr.Eval(&zeta)
 	_qm := publicData.Qm.Eval(&zeta)
+	_qc := publicData.Qc.Eval(&zeta)
 	_qo := publicData.Qo.Eval(&zeta)
 	_qk := publicData.Qk.Eval(&zeta)
 	ql.Set(_ql.(*fr.Element))
 	qr.Set(_qr.(*fr.Element))
 	qm.Set(_qm.(*fr.Element))
+	qc.Set(_qc.(*fr.Element))
 	qo.Set(_qo.(*fr.Element))
 	qk.Set(_qk.(*fr.Element))
 
@@ -129,15 +175,17 @@ func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witnes
 		Mul(&hFull, &zetaPowerM).
 		Add(&hFull, &proof.LROZH[4])
 
-	// evaluation of qlL+qrR+qmL.R+qoO+k at zeta
+	// evaluation of qlL+qrR+qmL.R+qcL.L.R+qoO+k at zeta
 	var constraintInd fr.Element
-	var qll, qrr, qmlr, qoo fr.Element
+	var qll, qrr, qmlr, qcllr, qoo fr.Element
 	qll.Mul(&ql, &lroz[0])
 	qrr.Mul(&qr, &lroz[1])
 	qmlr.Mul(&qm, &lroz[0]).Mul(&qmlr, &lroz[1])
+	qcllr.Mul(&qc, &lroz[0]).Mul(&qcllr, &lroz[0]).Mul(&qcllr, &lroz[1])
 	qoo.Mul(&qo, &lroz[2])
 	constraintInd.Add(&qll, &qrr).
 		Add(&constraintInd, &qmlr).
+		Add(&constraintInd, &qcllr).
 		Add(&constraintInd, &qoo).
 		Add(&constraintInd, &qk)
 
@@ -182,9 +230,18 @@ func VerifyRaw(proof *ProofRaw, publicData *PublicRaw, publicWitness bn254witnes
 		Mul(&startsAtOne, &zzeta). // 1/m * (zeta**m-1)/(zeta-1)
 		Mul(&startsAtOne, &tmp)    // (Z(zeta)-1)*L1(ze)
 
-	// lhs = qlL+qrR+qmL.R+qoO+k(zeta) + alpha*(zu*g1*g2*g3*l-z*f1*f2*f3*l)(zeta) + alpha**2*L1(Z-1)(zeta)
+	// evaluation of the lookup constraints at zeta (see evalLookup)
+	var lookup fr.Element
+	if publicData.hasLookups() {
+		lookup = evalLookupAtZeta(proof, publicData, [3]fr.Element{lroz[0], lroz[1], lroz[2]}, zeta, eta, beta, gamma, alpha)
+	}
+
+	// lhs = qlL+qrR+qmL.R+qcL.L.R+qoO+k(zeta) + alpha*(zu*g1*g2*g3*l-z*f1*f2*f3*l)(zeta) + alpha**2*L1(Z-1)(zeta)
+	// + alpha**3*lookup(zeta)
 	var lhs fr.Element
-	lhs.Mul(&alpha, &startsAtOne).
+	lhs.Mul(&alpha, &lookup).
+		Add(&lhs, &startsAtOne).
+		Mul(&lhs, &alpha).
 		Add(&lhs, &constraintOrdering).
 		Mul(&lhs, &alpha).
 		Add(&lhs, &constraintInd)
@@ -18,6 +18,7 @@ package cs_test
 
 import (
 	"bytes"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
@@ -26,6 +27,8 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark/internal/backend/bw6-761/cs"
+
+	bw6_761witness "github.com/consensys/gnark/internal/backend/bw6-761/witness"
 )
 
 func TestSerialization(t *testing.T) {
@@ -70,3 +73,95 @@ func TestSerialization(t *testing.T) {
 		}
 	}
 }
+func TestSparseSerialization(t *testing.T) {
+	var buffer bytes.Buffer
+	for _, testCircuits := range []map[string]circuits.TestCircuit{circuits.Circuits, circuits.PlonkCircuits} {
+		for name, circuit := range testCircuits {
+			buffer.Reset()
+
+			if testing.Short() && name != "reference_small" {
+				continue
+			}
+
+			spr, err := frontend.Compile(ecc.BW6_761, backend.PLONK, circuit.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testing.Short() && spr.GetNbConstraints() > 50 {
+				continue
+			}
+
+			spr.SetLoggerOutput(nil) // no need to serialize.
+
+			{
+				t.Log(name)
+				var err error
+				var written, read int64
+				written, err = spr.WriteTo(&buffer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var reconstructed cs.SparseR1CS
+				read, err = reconstructed.ReadFrom(&buffer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if written != read {
+					t.Fatal("didn't read same number of bytes we wrote")
+				}
+				// compare both
+				if !reflect.DeepEqual(spr, &reconstructed) {
+					t.Fatal("round trip serialization failed")
+				}
+			}
+		}
+	}
+}
+
+func TestUnsatisfiedConstraintError(t *testing.T) {
+	for name, circuit := range circuits.Circuits {
+
+		if testing.Short() && name != "reference_small" {
+			continue
+		}
+
+		var w bw6_761witness.Witness
+		if err := w.FromFullAssignment(circuit.Bad); err != nil {
+			t.Fatal(err)
+		}
+		for _, backendID := range []backend.ID{backend.GROTH16, backend.PLONK} {
+			ccs, err := frontend.Compile(ecc.BW6_761, backendID, circuit.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ccs.SetLoggerOutput(nil)
+			nbInternal, nbSecret, nbPublic := ccs.GetNbVariables()
+
+			var nbConstraints int // constraints and assertions
+			switch _ccs := ccs.(type) {
+			case *cs.R1CS:
+				err = _ccs.IsSolved(w)
+				nbConstraints = len(_ccs.Constraints)
+			case *cs.SparseR1CS:
+				err = _ccs.IsSolved(w)
+				nbConstraints = len(_ccs.Constraints) + len(_ccs.Assertions)
+			}
+
+			var uerr *backend.UnsatisfiedConstraintError
+			if !errors.As(err, &uerr) {
+				t.Fatalf("%s: expected an UnsatisfiedConstraintError, got %v", name, err)
+			}
+			if !errors.Is(err, cs.ErrUnsatisfiedConstraint) {
+				t.Fatalf("%s: the error should wrap ErrUnsatisfiedConstraint", name)
+			}
+			if uerr.ConstraintID < 0 || uerr.ConstraintID >= nbConstraints {
+				t.Fatalf("%s: invalid constraint ID %d", name, uerr.ConstraintID)
+			}
+			for _, wireID := range uerr.Wires {
+				if wireID < 0 || wireID >= nbInternal+nbSecret+nbPublic {
+					t.Fatalf("%s: invalid wire ID %d", name, wireID)
+				}
+			}
+		}
+	}
+}
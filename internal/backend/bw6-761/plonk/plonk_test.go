@@ -17,6 +17,7 @@
 package plonk_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/consensys/gnark/backend"
@@ -25,16 +26,76 @@ import (
 	"github.com/consensys/gnark/internal/backend/circuits"
 
 	curve "github.com/consensys/gnark-crypto/ecc/bw6-761"
+
+	"github.com/consensys/gnark/internal/backend/bw6-761/cs"
+
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr/polynomial/mockcommitment"
+	bw6_761plonk "github.com/consensys/gnark/internal/backend/bw6-761/plonk"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCircuits(t *testing.T) {
-	for name, circuit := range circuits.Circuits {
+	for _, testCircuits := range []map[string]circuits.TestCircuit{circuits.Circuits, circuits.PlonkCircuits} {
+		for name, circuit := range testCircuits {
+			t.Run(name, func(t *testing.T) {
+				assert := plonk.NewAssert(t)
+				pcs, err := frontend.Compile(curve.ID, backend.PLONK, circuit.Circuit)
+				assert.NoError(err)
+				assert.ProverSucceeded(pcs, circuit.Good)
+				assert.ProverFailed(pcs, circuit.Bad)
+			})
+		}
+	}
+}
+
+func TestInvalidWitnessSize(t *testing.T) {
+	assert := require.New(t)
+
+	circuit := circuits.Circuits["assert_equal"]
+	pcs, err := frontend.Compile(curve.ID, backend.PLONK, circuit.Circuit)
+	assert.NoError(err)
+	spr := pcs.(*cs.SparseR1CS)
+
+	_, err = bw6_761plonk.SetupRaw(spr, &mockcommitment.Scheme{}, nil)
+	assert.Error(err, "setup with an invalid public witness size should output an error")
+
+	publicData, err := plonk.SetupDummyCommitment(pcs, circuit.Good)
+	assert.NoError(err)
+	_, err = bw6_761plonk.ProveRaw(spr, publicData.(*bw6_761plonk.PublicRaw), nil, true)
+	assert.Error(err, "proving with an invalid witness size should output an error, even in force mode")
+}
+
+func TestProofSerialization(t *testing.T) {
+	for _, name := range []string{"reference_small", "lookup"} {
+		circuit, ok := circuits.Circuits[name]
+		if !ok {
+			circuit = circuits.PlonkCircuits[name]
+		}
 		t.Run(name, func(t *testing.T) {
-			assert := plonk.NewAssert(t)
+			assert := require.New(t)
+
 			pcs, err := frontend.Compile(curve.ID, backend.PLONK, circuit.Circuit)
 			assert.NoError(err)
-			assert.ProverSucceeded(pcs, circuit.Good)
-			assert.ProverFailed(pcs, circuit.Bad)
+			publicData, err := plonk.SetupDummyCommitment(pcs, circuit.Good)
+			assert.NoError(err)
+			proof, err := plonk.Prove(pcs, publicData, circuit.Good)
+			assert.NoError(err)
+
+			var buf bytes.Buffer
+			written, err := proof.(*bw6_761plonk.ProofRaw).WriteTo(&buf)
+			assert.NoError(err)
+
+			var reconstructed bw6_761plonk.ProofRaw
+			read, err := reconstructed.ReadFrom(&buf)
+			assert.NoError(err)
+			assert.Equal(written, read, "didn't read same number of bytes we wrote")
+
+			assert.NoError(plonk.Verify(&reconstructed, publicData, circuit.Good), "deserialized proof should verify")
+
+			// the commitments of a deserialized proof are decoded by the commitment scheme
+			undecodable := *publicData.(*bw6_761plonk.PublicRaw)
+			undecodable.CommitmentScheme = struct{ *mockcommitment.Scheme }{&mockcommitment.Scheme{}}
+			assert.Error(plonk.Verify(&reconstructed, &undecodable, circuit.Good), "a scheme which can't decode the proof should reject it")
 		})
 	}
 }
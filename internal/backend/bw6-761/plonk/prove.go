@@ -17,6 +17,7 @@
 package plonk
 
 import (
+	"fmt"
 	"math/big"
 
 	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/polynomial"
@@ -31,6 +32,7 @@ import (
 	"github.com/consensys/gnark/internal/backend/bw6-761/cs"
 
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/internal/utils"
 )
 
 // ProofRaw PLONK proofs, consisting of opening proofs
@@ -50,6 +52,15 @@ type ProofRaw struct {
 
 	// opening proof for Z at z*zeta
 	OpeningZShift polynomial.OpeningProof
+
+	// Claimed values of H1, H2, Zl (lookup argument) at zeta and at z*zeta
+	Lookup, LookupShift [3]fr.Element
+
+	// Commitments to H1, H2, Zl (only set if the circuit uses lookup tables)
+	CommitmentsLookup [3]polynomial.Digest
+
+	// batch opening proofs for H1, H2, Zl at zeta and at z*zeta
+	BatchOpeningsLookup, BatchOpeningsLookupShift polynomial.BatchOpeningProofSinglePoint
 }
 
 // ComputeLRO extracts the solution l, r, o, and returns it in lagrange form.
@@ -64,32 +75,40 @@ func ComputeLRO(spr *cs.SparseR1CS, publicData *PublicRaw, solution []fr.Element
 	o = make([]fr.Element, s)
 	partialL = make([]fr.Element, s)
 
-	for i := 0; i < spr.NbPublicVariables; i++ { // placeholders
-		l[i].Set(&solution[i])
-		r[i].Set(&solution[0])
-		o[i].Set(&solution[0])
-	}
+	utils.Parallelize(spr.NbPublicVariables, func(start, end int) {
+		for i := start; i < end; i++ { // placeholders
+			l[i].Set(&solution[i])
+			r[i].Set(&solution[0])
+			o[i].Set(&solution[0])
+		}
+	})
 	offset := spr.NbPublicVariables
-	for i := 0; i < len(spr.Constraints); i++ { // constraints
-		l[offset+i].Set(&solution[spr.Constraints[i].L.VariableID()])
-		r[offset+i].Set(&solution[spr.Constraints[i].R.VariableID()])
-		o[offset+i].Set(&solution[spr.Constraints[i].O.VariableID()])
-		partialL[offset+i].Set(&l[offset+i])
-	}
+	utils.Parallelize(len(spr.Constraints), func(start, end int) {
+		for i := start; i < end; i++ { // constraints
+			l[offset+i].Set(&solution[spr.Constraints[i].L.VariableID()])
+			r[offset+i].Set(&solution[spr.Constraints[i].R.VariableID()])
+			o[offset+i].Set(&solution[spr.Constraints[i].O.VariableID()])
+			partialL[offset+i].Set(&l[offset+i])
+		}
+	})
 	offset += len(spr.Constraints)
-	for i := 0; i < len(spr.Assertions); i++ { // assertions
-		l[offset+i].Set(&solution[spr.Assertions[i].L.VariableID()])
-		r[offset+i].Set(&solution[spr.Assertions[i].R.VariableID()])
-		o[offset+i].Set(&solution[spr.Assertions[i].O.VariableID()])
-		partialL[offset+i].Set(&l[offset+i])
-	}
+	utils.Parallelize(len(spr.Assertions), func(start, end int) {
+		for i := start; i < end; i++ { // assertions
+			l[offset+i].Set(&solution[spr.Assertions[i].L.VariableID()])
+			r[offset+i].Set(&solution[spr.Assertions[i].R.VariableID()])
+			o[offset+i].Set(&solution[spr.Assertions[i].O.VariableID()])
+			partialL[offset+i].Set(&l[offset+i])
+		}
+	})
 	offset += len(spr.Assertions)
-	for i := 0; i < s-offset; i++ { // offset to reach 2**n constraints (where the id of l,r,o is 0, so we assign solution[0])
-		l[offset+i].Set(&solution[0])
-		r[offset+i].Set(&solution[0])
-		o[offset+i].Set(&solution[0])
-		partialL[offset+i].Set(&l[offset+i])
-	}
+	utils.Parallelize(s-offset, func(start, end int) {
+		for i := start; i < end; i++ { // offset to reach 2**n constraints (where the id of l,r,o is 0, so we assign solution[0])
+			l[offset+i].Set(&solution[0])
+			r[offset+i].Set(&solution[0])
+			o[offset+i].Set(&solution[0])
+			partialL[offset+i].Set(&l[offset+i])
+		}
+	})
 
 	return l, r, o, partialL
 
@@ -109,74 +128,82 @@ func ComputeZ(l, r, o bw6761.Polynomial, publicData *PublicRaw, gamma fr.Element
 	z := make(bw6761.Polynomial, publicData.DomainNum.Cardinality)
 	nbElmts := int(publicData.DomainNum.Cardinality)
 
-	var f [3]fr.Element
-	var g [3]fr.Element
-	var u [3]fr.Element
-	u[0].SetOne()
-	u[1].Set(&publicData.Shifter[0])
-	u[2].Set(&publicData.Shifter[1])
+	// the ratios of the products are computed in parallel, the running product is then
+	// accumulated sequentially
+	ratios := make([]fr.Element, nbElmts-1)
+	utils.Parallelize(nbElmts-1, func(start, end int) {
 
-	z[0].SetOne()
+		var f [3]fr.Element
+		var g [3]fr.Element
+		var u [3]fr.Element
+		var bStart big.Int
+		bStart.SetInt64(int64(start))
+		u[0].Exp(publicData.DomainNum.Generator, &bStart) // z**start
+		u[1].Mul(&u[0], &publicData.Shifter[0])
+		u[2].Mul(&u[0], &publicData.Shifter[1])
 
-	for i := 0; i < nbElmts-1; i++ {
+		for i := start; i < end; i++ {
 
-		f[0].Add(&l[i], &u[0]).Add(&f[0], &gamma) //l_i+z**i+gamma
-		f[1].Add(&r[i], &u[1]).Add(&f[1], &gamma) //r_i+u*z**i+gamma
-		f[2].Add(&o[i], &u[2]).Add(&f[2], &gamma) //o_i+u**2*z**i+gamma
+			f[0].Add(&l[i], &u[0]).Add(&f[0], &gamma) //l_i+z**i+gamma
+			f[1].Add(&r[i], &u[1]).Add(&f[1], &gamma) //r_i+u*z**i+gamma
+			f[2].Add(&o[i], &u[2]).Add(&f[2], &gamma) //o_i+u**2*z**i+gamma
 
-		g[0].Add(&l[i], &publicData.LS1[i]).Add(&g[0], &gamma) //l_i+z**i+gamma
-		g[1].Add(&r[i], &publicData.LS2[i]).Add(&g[1], &gamma) //r_i+u*z**i+gamma
-		g[2].Add(&o[i], &publicData.LS3[i]).Add(&g[2], &gamma) //o_i+u**2*z**i+gamma
+			g[0].Add(&l[i], &publicData.LS1[i]).Add(&g[0], &gamma) //l_i+z**i+gamma
+			g[1].Add(&r[i], &publicData.LS2[i]).Add(&g[1], &gamma) //r_i+u*z**i+gamma
+			g[2].Add(&o[i], &publicData.LS3[i]).Add(&g[2], &gamma) //o_i+u**2*z**i+gamma
 
-		f[0].Mul(&f[0], &f[1]).Mul(&f[0], &f[2]) // (l_i+z**i+gamma)*(r_i+u*z**i+gamma)*(o_i+u**2z**i+gamma)
-		g[0].Mul(&g[0], &g[1]).Mul(&g[0], &g[2]) //  (l_i+s1+gamma)*(r_i+s2+gamma)*(o_i+s3+gamma)
+			f[0].Mul(&f[0], &f[1]).Mul(&f[0], &f[2]) // (l_i+z**i+gamma)*(r_i+u*z**i+gamma)*(o_i+u**2z**i+gamma)
+			g[0].Mul(&g[0], &g[1]).Mul(&g[0], &g[2]) //  (l_i+s1+gamma)*(r_i+s2+gamma)*(o_i+s3+gamma)
 
-		z[i+1].Mul(&z[i], &f[0]).Div(&z[i+1], &g[0])
+			ratios[i].Div(&f[0], &g[0])
 
-		u[0].Mul(&u[0], &publicData.DomainNum.Generator) // z**i -> z**i+1
-		u[1].Mul(&u[1], &publicData.DomainNum.Generator) // u*z**i -> u*z**i+1
-		u[2].Mul(&u[2], &publicData.DomainNum.Generator) // u**2*z**i -> u**2*z**i+1
+			u[0].Mul(&u[0], &publicData.DomainNum.Generator) // z**i -> z**i+1
+			u[1].Mul(&u[1], &publicData.DomainNum.Generator) // u*z**i -> u*z**i+1
+			u[2].Mul(&u[2], &publicData.DomainNum.Generator) // u**2*z**i -> u**2*z**i+1
+		}
+	})
+
+	z[0].SetOne()
+	for i := 0; i < nbElmts-1; i++ {
+		z[i+1].Mul(&z[i], &ratios[i])
 	}
 
 	return z
 
 }
 
-// evalConstraints computes the evaluation of lL+qrR+qqmL.R+qoO+k on
+// evalConstraints computes the evaluation of lL+qrR+qqmL.R+qcL.L.R+qoO+k on
 // the odd cosets of (Z/8mZ)/(Z/mZ), where m=nbConstraints+nbAssertions.
 func evalConstraints(publicData *PublicRaw, evalL, evalR, evalO []fr.Element) []fr.Element {
 
 	res := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
 
-	// evaluates ql, qr, qm, qo, k on the odd cosets of (Z/8mZ)/(Z/mZ)
-	evalQl := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalQr := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalQm := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalQo := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalQk := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evaluateCosets(publicData.Ql, evalQl, publicData.DomainNum)
-	evaluateCosets(publicData.Qr, evalQr, publicData.DomainNum)
-	evaluateCosets(publicData.Qm, evalQm, publicData.DomainNum)
-	evaluateCosets(publicData.Qo, evalQo, publicData.DomainNum)
-	evaluateCosets(publicData.Qk, evalQk, publicData.DomainNum)
-
-	// computes the evaluation of qrR+qlL+qmL.R+qoO+k on the odd cosets
+	// evaluates ql, qr, qm, qc, qo, k on the odd cosets of (Z/8mZ)/(Z/mZ)
+	evals := evaluateCosetsConcurrently(publicData.DomainNum, publicData.Ql, publicData.Qr, publicData.Qm, publicData.Qc, publicData.Qo, publicData.Qk)
+	evalQl, evalQr, evalQm, evalQc, evalQo, evalQk := evals[0], evals[1], evals[2], evals[3], evals[4], evals[5]
+
+	// computes the evaluation of qrR+qlL+qmL.R+qcL.L.R+qoO+k on the odd cosets
 	// of (Z/8mZ)/(Z/mZ)
-	var acc, buf fr.Element
-	for i := uint64(0); i < 4*publicData.DomainNum.Cardinality; i++ {
+	utils.Parallelize(len(res), func(start, end int) {
+		var acc, buf fr.Element
+		for i := start; i < end; i++ {
 
-		acc.Mul(&evalQl[i], &evalL[i]) // ql.l
+			acc.Mul(&evalQl[i], &evalL[i]) // ql.l
 
-		buf.Mul(&evalQr[i], &evalR[i])
-		acc.Add(&acc, &buf) // ql.l + qr.r
+			buf.Mul(&evalQr[i], &evalR[i])
+			acc.Add(&acc, &buf) // ql.l + qr.r
 
-		buf.Mul(&evalQm[i], &evalL[i]).Mul(&buf, &evalR[i])
-		acc.Add(&acc, &buf) // ql.l + qr.r + qm.l.r
+			buf.Mul(&evalQm[i], &evalL[i]).Mul(&buf, &evalR[i])
+			acc.Add(&acc, &buf) // ql.l + qr.r + qm.l.r
 
-		buf.Mul(&evalQo[i], &evalO[i])
-		acc.Add(&acc, &buf)          // ql.l + qr.r + qm.l.r + qo.o
-		res[i].Add(&acc, &evalQk[i]) // ql.l + qr.r + qm.l.r + qo.o + k
-	}
+			buf.Mul(&evalQc[i], &evalL[i]).Mul(&buf, &evalL[i]).Mul(&buf, &evalR[i])
+			acc.Add(&acc, &buf) // ql.l + qr.r + qm.l.r + qc.l.l.r
+
+			buf.Mul(&evalQo[i], &evalO[i])
+			acc.Add(&acc, &buf)          // ql.l + qr.r + qm.l.r + qc.l.l.r + qo.o
+			res[i].Add(&acc, &evalQk[i]) // ql.l + qr.r + qm.l.r + qc.l.l.r + qo.o + k
+		}
+	})
 
 	return res
 }
@@ -184,20 +211,7 @@ func evalConstraints(publicData *PublicRaw, evalL, evalR, evalO []fr.Element) []
 // evalIDCosets id, uid, u**2id on the odd cosets of (Z/8mZ)/(Z/mZ)
 func evalIDCosets(publicData *PublicRaw) (id, uid, uuid bw6761.Polynomial) {
 
-	// evaluation of id, uid, u**id on the cosets
-	id = make([]fr.Element, 4*publicData.DomainNum.Cardinality)
 	c := int(publicData.DomainNum.Cardinality)
-	id[0].SetOne()
-	id[1].SetOne()
-	id[2].SetOne()
-	id[3].SetOne()
-	for i := 1; i < c; i++ {
-		id[4*i].Mul(&id[4*(i-1)], &publicData.DomainNum.Generator)
-		id[4*i+1].Set(&id[4*i])
-		id[4*i+2].Set(&id[4*i])
-		id[4*i+3].Set(&id[4*i])
-	}
-	// at this stage, id = [1,1,1,1,|z,z,z,z|,...,|z**n-1,z**n-1,z**n-1,z**n-1]
 
 	var uu fr.Element
 	uu.Square(&publicData.DomainNum.FinerGenerator)
@@ -206,26 +220,37 @@ func evalIDCosets(publicData *PublicRaw) (id, uid, uuid bw6761.Polynomial) {
 	u[1].Mul(&u[0], &uu)                                          // u**3
 	u[2].Mul(&u[1], &uu)                                          // u**5
 	u[3].Mul(&u[2], &uu)                                          // u**7
+	id = make([]fr.Element, 4*publicData.DomainNum.Cardinality)   // ID evaluated on odd cosets of (Z/8mZ)/(Z/mZ)
 	uid = make([]fr.Element, 4*publicData.DomainNum.Cardinality)  // shifter[0]*ID evaluated on odd cosets of (Z/8mZ)/(Z/mZ)
 	uuid = make([]fr.Element, 4*publicData.DomainNum.Cardinality) // shifter[1]*ID evaluated on odd cosets of (Z/8mZ)/(Z/mZ)
-	for i := 0; i < c; i++ {
 
-		id[4*i].Mul(&id[4*i], &u[0])     // coset u.<1,z,..,z**n-1>
-		id[4*i+1].Mul(&id[4*i+1], &u[1]) // coset u**3.<1,z,..,z**n-1>
-		id[4*i+2].Mul(&id[4*i+2], &u[2]) // coset u**5.<1,z,..,z**n-1>
-		id[4*i+3].Mul(&id[4*i+3], &u[3]) // coset u**7.<1,z,..,z**n-1>
+	utils.Parallelize(c, func(start, end int) {
 
-		uid[4*i].Mul(&id[4*i], &publicData.Shifter[0])     // shifter[0]*ID
-		uid[4*i+1].Mul(&id[4*i+1], &publicData.Shifter[0]) // shifter[0]*ID
-		uid[4*i+2].Mul(&id[4*i+2], &publicData.Shifter[0]) // shifter[0]*ID
-		uid[4*i+3].Mul(&id[4*i+3], &publicData.Shifter[0]) // shifter[0]*ID
+		var acc fr.Element
+		var bStart big.Int
+		bStart.SetInt64(int64(start))
+		acc.Exp(publicData.DomainNum.Generator, &bStart) // z**start
 
-		uuid[4*i].Mul(&id[4*i], &publicData.Shifter[1])     // shifter[1]*ID
-		uuid[4*i+1].Mul(&id[4*i+1], &publicData.Shifter[1]) // shifter[1]*ID
-		uuid[4*i+2].Mul(&id[4*i+2], &publicData.Shifter[1]) // shifter[1]*ID
-		uuid[4*i+3].Mul(&id[4*i+3], &publicData.Shifter[1]) // shifter[1]*ID
+		for i := start; i < end; i++ {
 
-	}
+			id[4*i].Mul(&acc, &u[0])   // coset u.<1,z,..,z**n-1>
+			id[4*i+1].Mul(&acc, &u[1]) // coset u**3.<1,z,..,z**n-1>
+			id[4*i+2].Mul(&acc, &u[2]) // coset u**5.<1,z,..,z**n-1>
+			id[4*i+3].Mul(&acc, &u[3]) // coset u**7.<1,z,..,z**n-1>
+
+			uid[4*i].Mul(&id[4*i], &publicData.Shifter[0])     // shifter[0]*ID
+			uid[4*i+1].Mul(&id[4*i+1], &publicData.Shifter[0]) // shifter[0]*ID
+			uid[4*i+2].Mul(&id[4*i+2], &publicData.Shifter[0]) // shifter[0]*ID
+			uid[4*i+3].Mul(&id[4*i+3], &publicData.Shifter[0]) // shifter[0]*ID
+
+			uuid[4*i].Mul(&id[4*i], &publicData.Shifter[1])     // shifter[1]*ID
+			uuid[4*i+1].Mul(&id[4*i+1], &publicData.Shifter[1]) // shifter[1]*ID
+			uuid[4*i+2].Mul(&id[4*i+2], &publicData.Shifter[1]) // shifter[1]*ID
+			uuid[4*i+3].Mul(&id[4*i+3], &publicData.Shifter[1]) // shifter[1]*ID
+
+			acc.Mul(&acc, &publicData.DomainNum.Generator) // z**i -> z**i+1
+		}
+	})
 	return
 }
 
@@ -236,42 +261,45 @@ func evalIDCosets(publicData *PublicRaw) (id, uid, uuid bw6761.Polynomial) {
 // l, r, o: solution, in canonical form
 func evalConstraintOrdering(publicData *PublicRaw, evalZ, evalZu, evalL, evalR, evalO bw6761.Polynomial, gamma fr.Element) bw6761.Polynomial {
 
-	// evaluation of z, zu, s1, s2, s3, on the odd cosets of (Z/8mZ)/(Z/mZ)
-	evalS1 := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalS2 := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalS3 := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evaluateCosets(publicData.CS1, evalS1, publicData.DomainNum)
-	evaluateCosets(publicData.CS2, evalS2, publicData.DomainNum)
-	evaluateCosets(publicData.CS3, evalS3, publicData.DomainNum)
-
+	// evaluation of s1, s2, s3, on the odd cosets of (Z/8mZ)/(Z/mZ), and in the meantime
 	// evalutation of ID, u*ID, u**2*ID on the odd cosets of (Z/8mZ)/(Z/mZ)
-	evalID, evaluID, evaluuID := evalIDCosets(publicData)
+	var evalID, evaluID, evaluuID bw6761.Polynomial
+	chIDDone := make(chan struct{}, 1)
+	go func() {
+		evalID, evaluID, evaluuID = evalIDCosets(publicData)
+		close(chIDDone)
+	}()
+	evals := evaluateCosetsConcurrently(publicData.DomainNum, publicData.CS1, publicData.CS2, publicData.CS3)
+	evalS1, evalS2, evalS3 := evals[0], evals[1], evals[2]
+	<-chIDDone
 
 	// computes Z(uX)g1g2g3l-Z(X)f1f2f3l on the odd cosets of (Z/8mZ)/(Z/mZ)
 	res := make(bw6761.Polynomial, 4*publicData.DomainNum.Cardinality)
 
-	var f [3]fr.Element
-	var g [3]fr.Element
-	for i := 0; i < 4*int(publicData.DomainNum.Cardinality); i++ {
+	utils.Parallelize(len(res), func(start, end int) {
+		var f [3]fr.Element
+		var g [3]fr.Element
+		for i := start; i < end; i++ {
 
-		f[0].Add(&evalL[i], &evalID[i]).Add(&f[0], &gamma)   //l_i+z**i+gamma
-		f[1].Add(&evalR[i], &evaluID[i]).Add(&f[1], &gamma)  //r_i+u*z**i+gamma
-		f[2].Add(&evalO[i], &evaluuID[i]).Add(&f[2], &gamma) //o_i+u**2*z**i+gamma
+			f[0].Add(&evalL[i], &evalID[i]).Add(&f[0], &gamma)   //l_i+z**i+gamma
+			f[1].Add(&evalR[i], &evaluID[i]).Add(&f[1], &gamma)  //r_i+u*z**i+gamma
+			f[2].Add(&evalO[i], &evaluuID[i]).Add(&f[2], &gamma) //o_i+u**2*z**i+gamma
 
-		g[0].Add(&evalL[i], &evalS1[i]).Add(&g[0], &gamma) //l_i+s1+gamma
-		g[1].Add(&evalR[i], &evalS2[i]).Add(&g[1], &gamma) //r_i+s2+gamma
-		g[2].Add(&evalO[i], &evalS3[i]).Add(&g[2], &gamma) //o_i+s3+gamma
+			g[0].Add(&evalL[i], &evalS1[i]).Add(&g[0], &gamma) //l_i+s1+gamma
+			g[1].Add(&evalR[i], &evalS2[i]).Add(&g[1], &gamma) //r_i+s2+gamma
+			g[2].Add(&evalO[i], &evalS3[i]).Add(&g[2], &gamma) //o_i+s3+gamma
 
-		f[0].Mul(&f[0], &f[1]).
-			Mul(&f[0], &f[2]).
-			Mul(&f[0], &evalZ[i]) // z_i*(l_i+z**i+gamma)*(r_i+u*z**i+gamma)*(o_i+u**2*z**i+gamma)
+			f[0].Mul(&f[0], &f[1]).
+				Mul(&f[0], &f[2]).
+				Mul(&f[0], &evalZ[i]) // z_i*(l_i+z**i+gamma)*(r_i+u*z**i+gamma)*(o_i+u**2*z**i+gamma)
 
-		g[0].Mul(&g[0], &g[1]).
-			Mul(&g[0], &g[2]).
-			Mul(&g[0], &evalZu[i]) // u*z_i*(l_i+s1+gamma)*(r_i+s2+gamma)*(o_i+s3+gamma)
+			g[0].Mul(&g[0], &g[1]).
+				Mul(&g[0], &g[2]).
+				Mul(&g[0], &evalZu[i]) // u*z_i*(l_i+s1+gamma)*(r_i+s2+gamma)*(o_i+s3+gamma)
 
-		res[i].Sub(&g[0], &f[0])
-	}
+			res[i].Sub(&g[0], &f[0])
+		}
+	})
 
 	return res
 }
@@ -293,12 +321,15 @@ func evalStartsAtOne(publicData *PublicRaw, evalZ bw6761.Polynomial) bw6761.Poly
 	evaluateCosets(lOneLagrange, res, publicData.DomainNum)
 
 	// // evaluates L1*(z-1) on the odd cosets of (Z/8mZ)/(Z/mZ)
-	var buf, one fr.Element
+	var one fr.Element
 	one.SetOne()
-	for i := 0; i < 4*int(publicData.DomainNum.Cardinality); i++ {
-		buf.Sub(&evalZ[i], &one)
-		res[i].Mul(&buf, &res[i])
-	}
+	utils.Parallelize(len(res), func(start, end int) {
+		var buf fr.Element
+		for i := start; i < end; i++ {
+			buf.Sub(&evalZ[i], &one)
+			res[i].Mul(&buf, &res[i])
+		}
+	})
 
 	return res
 }
@@ -325,21 +356,70 @@ func evaluateCosets(poly, res []fr.Element, domain *fft.Domain) {
 	copy(evaluations[2], poly)
 	copy(evaluations[3], poly)
 
-	domain.FFT(evaluations[0], fft.DIF, 1)
-	domain.FFT(evaluations[1], fft.DIF, 3)
-	domain.FFT(evaluations[2], fft.DIF, 5)
-	domain.FFT(evaluations[3], fft.DIF, 7)
-	fft.BitReverse(evaluations[0])
-	fft.BitReverse(evaluations[1])
-	fft.BitReverse(evaluations[2])
-	fft.BitReverse(evaluations[3])
-
-	for i := uint64(0); i < domain.Cardinality; i++ {
-		res[4*i].Set(&evaluations[0][i])
-		res[4*i+1].Set(&evaluations[1][i])
-		res[4*i+2].Set(&evaluations[2][i])
-		res[4*i+3].Set(&evaluations[3][i])
-	}
+	// the 4 cosets are independent, the FFTs run concurrently
+	utils.Parallelize(4, func(start, end int) {
+		for i := start; i < end; i++ {
+			domain.FFT(evaluations[i], fft.DIF, uint64(2*i+1))
+			fft.BitReverse(evaluations[i])
+		}
+	}, 4)
+
+	utils.Parallelize(int(domain.Cardinality), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[4*i].Set(&evaluations[0][i])
+			res[4*i+1].Set(&evaluations[1][i])
+			res[4*i+2].Set(&evaluations[2][i])
+			res[4*i+3].Set(&evaluations[3][i])
+		}
+	})
+}
+
+// evaluateCosetsConcurrently evaluates each of polys (canonical form) on the odd cosets
+// of (Z/8mZ)/(Z/mZ), see evaluateCosets. The evaluations run concurrently.
+func evaluateCosetsConcurrently(domain *fft.Domain, polys ...bw6761.Polynomial) []bw6761.Polynomial {
+	res := make([]bw6761.Polynomial, len(polys))
+	utils.Parallelize(len(polys), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i] = make(bw6761.Polynomial, 4*domain.Cardinality)
+			evaluateCosets(polys[i], res[i], domain)
+		}
+	}, len(polys))
+	return res
+}
+
+// toCanonical puts each of polys (Lagrange basis) in canonical basis, in place. The FFTs
+// run concurrently.
+func toCanonical(domain *fft.Domain, polys ...bw6761.Polynomial) {
+	utils.Parallelize(len(polys), func(start, end int) {
+		for i := start; i < end; i++ {
+			domain.FFTInverse(polys[i], fft.DIF, 0)
+			fft.BitReverse(polys[i])
+		}
+	}, len(polys))
+}
+
+// commitConcurrently commits to each of polys, the commitments run concurrently.
+func commitConcurrently(publicData *PublicRaw, polys ...bw6761.Polynomial) []polynomial.Digest {
+	res := make([]polynomial.Digest, len(polys))
+	utils.Parallelize(len(polys), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i] = publicData.CommitmentScheme.Commit(polys[i])
+		}
+	}, len(polys))
+	return res
+}
+
+// evalConcurrently evaluates each of polys (canonical form) at point, the evaluations
+// run concurrently.
+func evalConcurrently(point fr.Element, polys ...bw6761.Polynomial) []fr.Element {
+	res := make([]fr.Element, len(polys))
+	utils.Parallelize(len(polys), func(start, end int) {
+		for i := start; i < end; i++ {
+			tmp := polys[i].Eval(&point)
+			res[i].Set(tmp.(*fr.Element))
+		}
+	}, len(polys))
+	return res
 }
 
 // shiftZ turns z to z(uX) (both in Lagrange basis)
@@ -360,12 +440,13 @@ func shiftZ(z bw6761.Polynomial) bw6761.Polynomial {
 
 // computeH computes h in canonical form, split as h1+X^mh2+X^2mh3 such that
 //
-// qlL+qrR+qmL.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l) + alpha**2*L1*(z-1)= h.Z
-// \------------------/         \------------------------/             \-----/
-//    constraintsInd			    constraintOrdering					startsAtOne
+// qlL+qrR+qmL.R+qcL.L.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l) + alpha**2*L1*(z-1) + alpha**3*lookup = h.Z
+// \---------------------------/         \------------------------/             \-----/           \----/
+//    constraintsInd			    constraintOrdering					startsAtOne        lookup (see evalLookup)
 //
-// constraintInd, constraintOrdering are evaluated on the odd cosets of (Z/8mZ)/(Z/mZ)
-func computeH(publicData *PublicRaw, constraintsInd, constraintOrdering, startsAtOne bw6761.Polynomial, alpha fr.Element) (bw6761.Polynomial, bw6761.Polynomial, bw6761.Polynomial) {
+// constraintInd, constraintOrdering, startsAtOne, lookup are evaluated on the odd cosets of (Z/8mZ)/(Z/mZ),
+// lookup is nil if the circuit doesn't use lookup tables.
+func computeH(publicData *PublicRaw, constraintsInd, constraintOrdering, startsAtOne, lookup bw6761.Polynomial, alpha fr.Element) (bw6761.Polynomial, bw6761.Polynomial, bw6761.Polynomial) {
 
 	h := make(bw6761.Polynomial, publicData.DomainH.Cardinality)
 
@@ -387,22 +468,22 @@ func computeH(publicData *PublicRaw, constraintsInd, constraintOrdering, startsA
 	u[3].Exp(u[3], &bExpo).Sub(&u[3], &one).Inverse(&u[3]) // (X**m-1)**-1 at u**7
 
 	// evaluate qlL+qrR+qmL.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l) + alpha**2*L1(X)(Z(X)-1)
-	// on the odd cosets of (Z/8mZ)/(Z/mZ)
-	for i := 0; i < 4*int(publicData.DomainNum.Cardinality); i++ {
-		h[i].Mul(&startsAtOne[i], &alpha).
-			Add(&h[i], &constraintOrdering[i]).
-			Mul(&h[i], &alpha).
-			Add(&h[i], &constraintsInd[i])
-	}
-
-	// evaluate qlL+qrR+qmL.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l)/Z
-	// on the odd cosets of (Z/8mZ)/(Z/mZ)
-	for i := 0; i < int(publicData.DomainNum.Cardinality); i++ {
-		h[4*i].Mul(&h[4*i], &u[0])
-		h[4*i+1].Mul(&h[4*i+1], &u[1])
-		h[4*i+2].Mul(&h[4*i+2], &u[2])
-		h[4*i+3].Mul(&h[4*i+3], &u[3])
-	}
+	// on the odd cosets of (Z/8mZ)/(Z/mZ), and divide by Z
+	utils.Parallelize(int(publicData.DomainNum.Cardinality), func(start, end int) {
+		var buf fr.Element
+		for i := 4 * start; i < 4*end; i++ {
+			h[i].Set(&startsAtOne[i])
+			if lookup != nil {
+				buf.Mul(&lookup[i], &alpha)
+				h[i].Add(&h[i], &buf)
+			}
+			h[i].Mul(&h[i], &alpha).
+				Add(&h[i], &constraintOrdering[i]).
+				Mul(&h[i], &alpha).
+				Add(&h[i], &constraintsInd[i]).
+				Mul(&h[i], &u[i%4])
+		}
+	})
 
 	// put h in canonical form
 	publicData.DomainH.FFTInverse(h, fft.DIF, 1)
@@ -419,18 +500,35 @@ func computeH(publicData *PublicRaw, constraintsInd, constraintOrdering, startsA
 
 }
 
+// bindDigests binds the digests to challenge in the transcript fs
+func bindDigests(fs *fiatshamir.Transcript, challenge string, digests ...polynomial.Digest) error {
+	for i := 0; i < len(digests); i++ {
+		if err := fs.Bind(challenge, digests[i].Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ProveRaw from the public data
-// TODO add a parameter to force the resolution of the system even if a constraint does not hold
-func ProveRaw(spr *cs.SparseR1CS, publicData *PublicRaw, fullWitness bw6_761witness.Witness) *ProofRaw {
+// if force flag is set, ProveRaw ignores the solving error (ie invalid witness) and executes
+// the FFTs and commitments to compute an (invalid) proof
+func ProveRaw(spr *cs.SparseR1CS, publicData *PublicRaw, fullWitness bw6_761witness.Witness, force bool) (*ProofRaw, error) {
+	if len(fullWitness) != spr.NbPublicVariables+spr.NbSecretVariables {
+		return nil, fmt.Errorf("invalid witness size, got %d, expected %d = %d (public) + %d (secret)", len(fullWitness), spr.NbPublicVariables+spr.NbSecretVariables, spr.NbPublicVariables, spr.NbSecretVariables)
+	}
 
 	// create a transcript manager to apply Fiat Shamir
-	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "gamma", "alpha", "zeta")
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "eta", "gamma", "beta", "alpha", "zeta")
 
 	// result
 	proof := &ProofRaw{}
 
 	// compute the solution
-	solution, _ := spr.Solve(fullWitness)
+	solution, err := spr.Solve(fullWitness)
+	if err != nil && !force {
+		return nil, err
+	}
 
 	// query l, r, o in Lagrange basis
 	ll, lr, lo, partialL := ComputeLRO(spr, publicData, solution)
@@ -443,25 +541,63 @@ func ProveRaw(spr *cs.SparseR1CS, publicData *PublicRaw, fullWitness bw6_761witn
 	copy(cl, ll)
 	copy(cr, lr)
 	copy(co, lo)
-	publicData.DomainNum.FFTInverse(cl, fft.DIF, 0)
-	publicData.DomainNum.FFTInverse(cr, fft.DIF, 0)
-	publicData.DomainNum.FFTInverse(co, fft.DIF, 0)
-	publicData.DomainNum.FFTInverse(partialL, fft.DIF, 0)
-	fft.BitReverse(cl)
-	fft.BitReverse(cr)
-	fft.BitReverse(co)
-	fft.BitReverse(partialL)
-
-	// derive gamma from the Comm(l), Comm(r), Comm(o)
-	proof.CommitmentsLROZH[0] = publicData.CommitmentScheme.Commit(cl)
-	proof.CommitmentsLROZH[1] = publicData.CommitmentScheme.Commit(cr)
-	proof.CommitmentsLROZH[2] = publicData.CommitmentScheme.Commit(co)
-	fs.Bind("gamma", proof.CommitmentsLROZH[0].Bytes())
-	fs.Bind("gamma", proof.CommitmentsLROZH[1].Bytes())
-	fs.Bind("gamma", proof.CommitmentsLROZH[2].Bytes())
-	bgamma, _ := fs.ComputeChallenge("gamma")
+	toCanonical(publicData.DomainNum, cl, cr, co, partialL)
+
+	// compute the evaluations of l, r, o on odd cosets of (Z/8mZ)/(Z/mZ), and the
+	// evaluation of qlL+qrR+qmL.R+qcL.L.R+qoO+k, while the rest of the proof is computed
+	var evalL, evalR, evalO, constraintsInd bw6761.Polynomial
+	chConstraintsIndDone := make(chan struct{}, 1)
+	go func() {
+		evals := evaluateCosetsConcurrently(publicData.DomainNum, cl, cr, co)
+		evalL, evalR, evalO = evals[0], evals[1], evals[2]
+		constraintsInd = evalConstraints(publicData, evalL, evalR, evalO)
+		close(chConstraintsIndDone)
+	}()
+
+	// derive eta from the Comm(l), Comm(r), Comm(o)
+	commitmentsLRO := commitConcurrently(publicData, cl, cr, co)
+	copy(proof.CommitmentsLROZH[:3], commitmentsLRO)
+	if err := bindDigests(&fs, "eta", proof.CommitmentsLROZH[:3]...); err != nil {
+		return nil, err
+	}
+	bEta, err := fs.ComputeChallenge("eta")
+	if err != nil {
+		return nil, err
+	}
+	var eta fr.Element
+	eta.SetBytes(bEta)
+
+	// compute the sorted lookups h1, h2 and the compressed table t in Lagrange basis,
+	// and derive gamma from Comm(h1), Comm(h2)
+	var lt, lf, lh1, lh2, ct, ch1, ch2 bw6761.Polynomial
+	if publicData.hasLookups() {
+		lt = compressTable(publicData.LTable, eta)
+		ct = compressTable(publicData.CTable, eta)
+		lf = computeLookupF(spr, publicData, ll, lr, lo, lt, eta)
+		lh1, lh2 = computeSortedLookup(lf, lt)
+		ch1 = make(bw6761.Polynomial, len(lh1))
+		ch2 = make(bw6761.Polynomial, len(lh2))
+		copy(ch1, lh1)
+		copy(ch2, lh2)
+		toCanonical(publicData.DomainNum, ch1, ch2)
+		commitmentsH := commitConcurrently(publicData, ch1, ch2)
+		copy(proof.CommitmentsLookup[:2], commitmentsH)
+		if err := bindDigests(&fs, "gamma", proof.CommitmentsLookup[:2]...); err != nil {
+			return nil, err
+		}
+	}
+	bgamma, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return nil, err
+	}
 	var gamma fr.Element
 	gamma.SetBytes(bgamma)
+	bbeta, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		return nil, err
+	}
+	var beta fr.Element
+	beta.SetBytes(bbeta)
 
 	// compute Z, the permutation accumulator polynomial, in Lagrange basis
 	z := ComputeZ(ll, lr, lo, publicData, gamma)
@@ -469,90 +605,123 @@ func ProveRaw(spr *cs.SparseR1CS, publicData *PublicRaw, fullWitness bw6_761witn
 	// compute Z(uX), in Lagrange basis
 	zu := shiftZ(z)
 
-	// compute the evaluations of l, r, o on odd cosets of (Z/8mZ)/(Z/mZ)
-	evalL := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalR := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalO := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evaluateCosets(cl, evalL, publicData.DomainNum)
-	evaluateCosets(cr, evalR, publicData.DomainNum)
-	evaluateCosets(co, evalO, publicData.DomainNum)
+	// compute Zl, the lookup accumulator polynomial, in Lagrange basis
+	var czl bw6761.Polynomial
+	if publicData.hasLookups() {
+		czl = ComputeZLookup(lf, lt, lh1, lh2, beta, gamma)
+	}
 
-	// compute the evaluation of qlL+qrR+qmL.R+qoO+k on the odd cosets of (Z/8mZ)/(Z/mZ)
-	constraintsInd := evalConstraints(publicData, evalL, evalR, evalO)
+	// put back z, zu, zl in canonical basis, and commit to z and zl
+	if publicData.hasLookups() {
+		toCanonical(publicData.DomainNum, z, zu, czl)
+		commitmentsZ := commitConcurrently(publicData, z, czl)
+		proof.CommitmentsLROZH[3] = commitmentsZ[0]
+		proof.CommitmentsLookup[2] = commitmentsZ[1]
+	} else {
+		toCanonical(publicData.DomainNum, z, zu)
+		proof.CommitmentsLROZH[3] = publicData.CommitmentScheme.Commit(z)
+	}
 
-	// put back z, zu in canonical basis
-	publicData.DomainNum.FFTInverse(z, fft.DIF, 0)
-	publicData.DomainNum.FFTInverse(zu, fft.DIF, 0)
-	fft.BitReverse(z)
-	fft.BitReverse(zu)
+	// derive alpha from the Comm(l), Comm(r), Comm(o), Com(Z), Com(Zl)
+	if err := bindDigests(&fs, "alpha", proof.CommitmentsLROZH[3]); err != nil {
+		return nil, err
+	}
+	if publicData.hasLookups() {
+		if err := bindDigests(&fs, "alpha", proof.CommitmentsLookup[2]); err != nil {
+			return nil, err
+		}
+	}
+	balpha, err := fs.ComputeChallenge("alpha")
+	if err != nil {
+		return nil, err
+	}
+	var alpha fr.Element
+	alpha.SetBytes(balpha)
 
 	// evaluate z, zu on the odd cosets of (Z/8mZ)/(Z/mZ)
-	evalZ := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evalZu := make([]fr.Element, 4*publicData.DomainNum.Cardinality)
-	evaluateCosets(z, evalZ, publicData.DomainNum)
-	evaluateCosets(zu, evalZu, publicData.DomainNum)
-
-	// compute zu*g1*g2*g3-z*f1*f2*f3 on the odd cosets of (Z/8mZ)/(Z/mZ)
-	constraintsOrdering := evalConstraintOrdering(publicData, evalZ, evalZu, evalL, evalR, evalO, gamma)
+	evals := evaluateCosetsConcurrently(publicData.DomainNum, z, zu)
+	evalZ, evalZu := evals[0], evals[1]
 
 	// compute L1*(z-1) on the odd cosets of (Z/8mZ)/(Z/mZ)
-	startsAtOne := evalStartsAtOne(publicData, evalZ)
+	var startsAtOne bw6761.Polynomial
+	chStartsAtOneDone := make(chan struct{}, 1)
+	go func() {
+		startsAtOne = evalStartsAtOne(publicData, evalZ)
+		close(chStartsAtOneDone)
+	}()
+
+	// the remaining constraints need the evaluations of l, r, o
+	<-chConstraintsIndDone
+
+	// compute the lookup constraints on the odd cosets of (Z/8mZ)/(Z/mZ)
+	var lookup bw6761.Polynomial
+	chLookupDone := make(chan struct{}, 1)
+	go func() {
+		if publicData.hasLookups() {
+			lookup = evalLookup(publicData, evalL, evalR, evalO, ct, ch1, ch2, czl, eta, beta, gamma, alpha)
+		}
+		close(chLookupDone)
+	}()
 
-	// commit to Z
-	proof.CommitmentsLROZH[3] = publicData.CommitmentScheme.Commit(z)
+	// compute zu*g1*g2*g3-z*f1*f2*f3 on the odd cosets of (Z/8mZ)/(Z/mZ)
+	constraintsOrdering := evalConstraintOrdering(publicData, evalZ, evalZu, evalL, evalR, evalO, gamma)
 
-	// derive alpha from the Comm(l), Comm(r), Comm(o), Com(Z)
-	fs.Bind("alpha", proof.CommitmentsLROZH[3].Bytes())
-	balpha, _ := fs.ComputeChallenge("alpha")
-	var alpha fr.Element
-	alpha.SetBytes(balpha)
+	<-chStartsAtOneDone
+	<-chLookupDone
 
 	// compute h in canonical form
-	h1, h2, h3 := computeH(publicData, constraintsInd, constraintsOrdering, startsAtOne, alpha)
+	h1, h2, h3 := computeH(publicData, constraintsInd, constraintsOrdering, startsAtOne, lookup, alpha)
 
 	// commit to h (3 commitments h1 + x*h2 + x**2*h3)
-	proof.CommitmentsLROZH[4] = publicData.CommitmentScheme.Commit(h1)
-	proof.CommitmentsLROZH[5] = publicData.CommitmentScheme.Commit(h2)
-	proof.CommitmentsLROZH[6] = publicData.CommitmentScheme.Commit(h3)
+	commitmentsH := commitConcurrently(publicData, h1, h2, h3)
+	copy(proof.CommitmentsLROZH[4:], commitmentsH)
 
 	// derive zeta, the point of evaluation
-	fs.Bind("zeta", proof.CommitmentsLROZH[4].Bytes())
-	fs.Bind("zeta", proof.CommitmentsLROZH[5].Bytes())
-	fs.Bind("zeta", proof.CommitmentsLROZH[6].Bytes())
-	bzeta, _ := fs.ComputeChallenge("zeta")
+	if err := bindDigests(&fs, "zeta", proof.CommitmentsLROZH[4:]...); err != nil {
+		return nil, err
+	}
+	bzeta, err := fs.ComputeChallenge("zeta")
+	if err != nil {
+		return nil, err
+	}
 	var zeta fr.Element
 	zeta.SetBytes(bzeta)
 
-	// compute evaluations of l, r, o, z at zeta
-	tmp := partialL.Eval(&zeta)
-	proof.LROZH[0].Set(tmp.(*fr.Element))
-	tmp = cr.Eval(&zeta)
-	proof.LROZH[1].Set(tmp.(*fr.Element))
-	tmp = co.Eval(&zeta)
-	proof.LROZH[2].Set(tmp.(*fr.Element))
-	tmp = z.Eval(&zeta)
-	proof.LROZH[3].Set(tmp.(*fr.Element))
-
-	// compute evaluations of h1, h2, h3 at zeta (so h(zeta)=h1(zeta)+zeta^m*h2(zeta)+zeta^2m*h3(zeta))
-	tmp = h1.Eval(&zeta)
-	proof.LROZH[4].Set(tmp.(*fr.Element))
-	tmp = h2.Eval(&zeta)
-	proof.LROZH[5].Set(tmp.(*fr.Element))
-	tmp = h3.Eval(&zeta)
-	proof.LROZH[6].Set(tmp.(*fr.Element))
+	// compute evaluations of l, r, o, z at zeta, and of h1, h2, h3 at zeta
+	// (so h(zeta)=h1(zeta)+zeta^m*h2(zeta)+zeta^2m*h3(zeta))
+	evalsAtZeta := evalConcurrently(zeta, partialL, cr, co, z, h1, h2, h3)
+	copy(proof.LROZH[:], evalsAtZeta)
 
 	// compute evaluation of z at z*zeta
 	var zzeta fr.Element
 	zzeta.Mul(&zeta, &publicData.DomainNum.Generator)
-	tmp = z.Eval(&zzeta)
+	tmp := z.Eval(&zzeta)
 	proof.ZShift.Set(tmp.(*fr.Element))
 
-	// compute batch opening proof for l, r, o, h, z at zeta
+	// compute batch opening proof for l, r, o, h, z at zeta, and in the meantime the
+	// opening proof for z at z*zeta
+	chOpeningZShiftDone := make(chan struct{}, 1)
+	go func() {
+		proof.OpeningZShift = publicData.CommitmentScheme.Open(&zzeta, z)
+		close(chOpeningZShiftDone)
+	}()
 	polynomialsToOpenAtZeta := []bw6761.Polynomial{cl, cr, co, z, h1, h2, h3}
 	proof.BatchOpenings = publicData.CommitmentScheme.BatchOpenSinglePoint(&zeta, polynomialsToOpenAtZeta)
+	<-chOpeningZShiftDone
+
+	// compute evaluations and batch opening proofs of h1, h2, zl (lookup) at zeta and z*zeta
+	if publicData.hasLookups() {
+		lookupPolynomials := []bw6761.Polynomial{ch1, ch2, czl}
+		copy(proof.Lookup[:], evalConcurrently(zeta, lookupPolynomials...))
+		copy(proof.LookupShift[:], evalConcurrently(zzeta, lookupPolynomials...))
+		chOpeningsLookupShiftDone := make(chan struct{}, 1)
+		go func() {
+			proof.BatchOpeningsLookupShift = publicData.CommitmentScheme.BatchOpenSinglePoint(&zzeta, lookupPolynomials)
+			close(chOpeningsLookupShiftDone)
+		}()
+		proof.BatchOpeningsLookup = publicData.CommitmentScheme.BatchOpenSinglePoint(&zeta, lookupPolynomials)
+		<-chOpeningsLookupShiftDone
+	}
 
-	// compute opening proof for z at z*zeta
-	proof.OpeningZShift = publicData.CommitmentScheme.Open(&zzeta, z)
-
-	return proof
+	return proof, nil
 }
@@ -17,6 +17,9 @@
 package plonk
 
 import (
+	"errors"
+	"fmt"
+
 	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/polynomial"
 	"github.com/consensys/gnark-crypto/polynomial"
 
@@ -30,15 +33,15 @@ import (
 )
 
 // PublicRaw represents the raw public data corresponding to a circuit,
-// which consists of the evaluations of the LDE of qr,ql,qm,qo,k. The compact
-// version of public data consists of commitments of qr,ql,qm,qo,k.
+// which consists of the evaluations of the LDE of qr,ql,qm,qc,qo,k. The compact
+// version of public data consists of commitments of qr,ql,qm,qc,qo,k.
 type PublicRaw struct {
 
 	// Commitment scheme that is used for an instantiation of PLONK
 	CommitmentScheme polynomial.CommitmentScheme
 
-	// qr,ql,qm,qo,k (in canonical basis)
-	Ql, Qr, Qm, Qo, Qk bw6761.Polynomial
+	// qr,ql,qm,qc,qo,k (in canonical basis), qc being the selector of the cubic gate qc.l.l.r
+	Ql, Qr, Qm, Qc, Qo, Qk bw6761.Polynomial
 
 	// Domains used for the FFTs
 	DomainNum, DomainH *fft.Domain
@@ -53,15 +56,33 @@ type PublicRaw struct {
 
 	// position -> permuted position (position in [0,3*sizeSystem-1])
 	Permutation []int
+
+	// lookup selectors (canonical basis): qlookup is 1 on the lookup constraints,
+	// qtable is the ID+1 of their table. They are nil if there are no lookup tables.
+	Qlookup, Qtable bw6761.Polynomial
+
+	// columns of the concatenated lookup tables, the 4th column being the ID+1 of
+	// the table of each entry (L=Lagrange basis, C=canonical basis)
+	LTable, CTable [4]bw6761.Polynomial
 }
 
 // SetupRaw from a sparseR1CS
 // * sets LDE+canonical basis representations of the permutations
-// * sets the canonical basis of ql, qr, qm, qo, qk extended (i.e. containing also placeholders constraints -PUB_INPUT_i + qk_i=0)
+// * sets the canonical basis of ql, qr, qm, qc, qo, qk extended (i.e. containing also placeholders constraints -PUB_INPUT_i + qk_i=0)
+// * sets the lookup selectors and tables, if the circuit uses lookup tables
 // * sets the fft domains that will be needed for handling polynomials
 // The publicWitness params is here to build the placeholder constraints (used in the verifier to complete the proof)
-// TODO in many places this function should handle raising errors
-func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentScheme, publicWitness bw6_761witness.Witness) *PublicRaw {
+func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentScheme, publicWitness bw6_761witness.Witness) (*PublicRaw, error) {
+	if polynomialCommitment == nil {
+		return nil, errors.New("polynomial commitment scheme is nil")
+	}
+	if len(publicWitness) != spr.NbPublicVariables {
+		return nil, fmt.Errorf("invalid public witness size, got %d, expected %d", len(publicWitness), spr.NbPublicVariables)
+	}
+	if spr.NbPublicVariables+spr.NbSecretVariables+spr.NbInternalVariables == 0 {
+		// the unused wires of l, r, o point to the first variable
+		return nil, errors.New("circuit has no variables")
+	}
 
 	nbConstraints := len(spr.Constraints)
 	nbAssertions := len(spr.Assertions)
@@ -70,6 +91,17 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 
 	// fft domains
 	sizeSystem := uint64(nbConstraints + nbAssertions + spr.NbPublicVariables) // spr.NbPublicVariables is for the placeholder constraints
+	if len(spr.Tables) != 0 {
+		// the last row is never a lookup, and the concatenated lookup tables fit in the domain
+		sizeSystem++
+		if tableSize := uint64(lookupTableSize(spr)); tableSize > sizeSystem {
+			sizeSystem = tableSize
+		}
+	}
+	if sizeSystem < 2 {
+		// the fft domains need at least 2 elements (empty circuits)
+		sizeSystem = 2
+	}
 	res.DomainNum = fft.NewDomain(sizeSystem, 3, false)
 	res.DomainH = fft.NewDomain(4*sizeSystem, 1, false)
 
@@ -84,13 +116,15 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 	res.Ql = make([]fr.Element, res.DomainNum.Cardinality)
 	res.Qr = make([]fr.Element, res.DomainNum.Cardinality)
 	res.Qm = make([]fr.Element, res.DomainNum.Cardinality)
+	res.Qc = make([]fr.Element, res.DomainNum.Cardinality)
 	res.Qo = make([]fr.Element, res.DomainNum.Cardinality)
 	res.Qk = make([]fr.Element, res.DomainNum.Cardinality)
 
-	for i := 0; i < spr.NbPublicVariables; i++ { // placeholders (-PUB_INPUT_i + qk_i = 0) TODO should return error is size is inconsistant
+	for i := 0; i < spr.NbPublicVariables; i++ { // placeholders (-PUB_INPUT_i + qk_i = 0)
 		res.Ql[i].SetOne().Neg(&res.Ql[i])
 		res.Qr[i].SetZero()
 		res.Qm[i].SetZero()
+		res.Qc[i].SetZero()
 		res.Qo[i].SetZero()
 		res.Qk[i].Set(&publicWitness[i])
 	}
@@ -101,6 +135,9 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 		res.Qr[offset+i].Set(&spr.Coefficients[spr.Constraints[i].R.CoeffID()])
 		res.Qm[offset+i].Set(&spr.Coefficients[spr.Constraints[i].M[0].CoeffID()]).
 			Mul(&res.Qm[offset+i], &spr.Coefficients[spr.Constraints[i].M[1].CoeffID()])
+		res.Qc[offset+i].Set(&spr.Coefficients[spr.Constraints[i].C[0].CoeffID()]).
+			Mul(&res.Qc[offset+i], &spr.Coefficients[spr.Constraints[i].C[1].CoeffID()]).
+			Mul(&res.Qc[offset+i], &spr.Coefficients[spr.Constraints[i].C[2].CoeffID()])
 		res.Qo[offset+i].Set(&spr.Coefficients[spr.Constraints[i].O.CoeffID()])
 		res.Qk[offset+i].Set(&spr.Coefficients[spr.Constraints[i].K])
 	}
@@ -111,6 +148,9 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 		res.Qr[offset+i].Set(&spr.Coefficients[spr.Assertions[i].R.CoeffID()])
 		res.Qm[offset+i].Set(&spr.Coefficients[spr.Assertions[i].M[0].CoeffID()]).
 			Mul(&res.Qm[offset+i], &spr.Coefficients[spr.Assertions[i].M[1].CoeffID()])
+		res.Qc[offset+i].Set(&spr.Coefficients[spr.Assertions[i].C[0].CoeffID()]).
+			Mul(&res.Qc[offset+i], &spr.Coefficients[spr.Assertions[i].C[1].CoeffID()]).
+			Mul(&res.Qc[offset+i], &spr.Coefficients[spr.Assertions[i].C[2].CoeffID()])
 		res.Qo[offset+i].Set(&spr.Coefficients[spr.Assertions[i].O.CoeffID()])
 		res.Qk[offset+i].Set(&spr.Coefficients[spr.Assertions[i].K])
 	}
@@ -118,21 +158,28 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 	res.DomainNum.FFTInverse(res.Ql, fft.DIF, 0)
 	res.DomainNum.FFTInverse(res.Qr, fft.DIF, 0)
 	res.DomainNum.FFTInverse(res.Qm, fft.DIF, 0)
+	res.DomainNum.FFTInverse(res.Qc, fft.DIF, 0)
 	res.DomainNum.FFTInverse(res.Qo, fft.DIF, 0)
 	res.DomainNum.FFTInverse(res.Qk, fft.DIF, 0)
 	fft.BitReverse(res.Ql)
 	fft.BitReverse(res.Qr)
 	fft.BitReverse(res.Qm)
+	fft.BitReverse(res.Qc)
 	fft.BitReverse(res.Qo)
 	fft.BitReverse(res.Qk)
 
+	// lookup selectors and tables
+	if len(spr.Tables) != 0 {
+		setupLookup(spr, &res)
+	}
+
 	// build permutation. Note: at this stage, the permutation takes in account the placeholders
 	buildPermutation(spr, &res)
 
 	// set s1, s2, s3
 	ComputeS(&res)
 
-	return &res
+	return &res, nil
 }
 
 // buildPermutation builds the Permutation associated with a circuit.
@@ -147,6 +194,8 @@ func SetupRaw(spr *cs.SparseR1CS, polynomialCommitment polynomial.CommitmentSche
 // The permutation is encoded as a slice s of size 3*size(l), where the
 // i-th entry of l||r||o is sent to the s[i]-th entry, so it acts on a tab
 // like this: for i in tab: tab[i] = tab[permutation[i]]
+//
+// The variables which don't appear in l||r||o (unused secret inputs) are ignored.
 func buildPermutation(spr *cs.SparseR1CS, publicData *PublicRaw) {
 
 	sizeSolution := int(publicData.DomainNum.Cardinality)
@@ -211,11 +260,9 @@ func buildPermutation(spr *cs.SparseR1CS, publicData *PublicRaw) {
 	}
 
 	// complete the Permutation by filling the first IDs encountered
-	counter := nbVariables
-	for iter := 0; counter > 0; iter++ {
+	for iter := 0; iter < len(lro); iter++ {
 		if publicData.Permutation[iter] == -1 {
 			publicData.Permutation[iter] = cycle[lro[iter]]
-			counter--
 		}
 	}
 
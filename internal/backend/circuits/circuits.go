@@ -24,3 +24,17 @@ func addEntry(name string, circuit, proverGood, proverBad, publicData frontend.C
 
 	Circuits[name] = TestCircuit{circuit, proverGood, proverBad, publicData}
 }
+
+// PlonkCircuits are used for test purposes, like Circuits, but are only supported by the PLONK backend (lookup tables)
+var PlonkCircuits map[string]TestCircuit
+
+func addPlonkEntry(name string, circuit, proverGood, proverBad, publicData frontend.Circuit) {
+	if PlonkCircuits == nil {
+		PlonkCircuits = make(map[string]TestCircuit)
+	}
+	if _, ok := PlonkCircuits[name]; ok {
+		panic("name " + name + "already taken by another test circuit ")
+	}
+
+	PlonkCircuits[name] = TestCircuit{circuit, proverGood, proverBad, publicData}
+}
@@ -0,0 +1,54 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// lookupCircuit uses a xor table on 2 bits and a range table on 3 bits
+type lookupCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *lookupCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+
+	var xorEntries, rangeEntries [][]interface{}
+	for a := 0; a < 4; a++ {
+		for b := 0; b < 4; b++ {
+			xorEntries = append(xorEntries, []interface{}{a, b, a ^ b})
+		}
+	}
+	for v := 0; v < 8; v++ {
+		rangeEntries = append(rangeEntries, []interface{}{v})
+	}
+	xor := cs.NewLookupTable(xorEntries...)
+	rng := cs.NewLookupTable(rangeEntries...)
+
+	// a+4b < 8
+	cs.AssertInTable(rng, cs.Add(circuit.A, cs.Mul(circuit.B, 4)))
+
+	// c = (a^b) + (a^b^3)**2
+	c := cs.Lookup(xor, circuit.A, circuit.B)
+	d := cs.Lookup(xor, c, 3)
+	cs.AssertIsEqual(circuit.C, cs.Add(c, cs.Mul(d, d)))
+
+	return nil
+}
+
+func init() {
+	var circuit, good, bad, public lookupCircuit
+
+	good.A.Assign(1)
+	good.B.Assign(1)
+	good.C.Assign(9)
+
+	// all the constraints hold, but the range check
+	bad.A.Assign(1)
+	bad.B.Assign(2)
+	bad.C.Assign(3)
+
+	public.C.Assign(9)
+
+	addPlonkEntry("lookup", &circuit, &good, &bad, &public)
+}
@@ -0,0 +1,45 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// pow5Circuit uses square-and-multiply patterns which are compiled
+// to cubic gates in a SparseR1CS
+type pow5Circuit struct {
+	X, Z frontend.Variable
+	Y    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *pow5Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+
+	// x**5
+	x2 := cs.Mul(circuit.X, circuit.X)
+	x4 := cs.Mul(x2, x2)
+	x5 := cs.Mul(x4, circuit.X)
+
+	// (3x)**2 * 2z
+	x3 := cs.Mul(circuit.X, 3)
+	t := cs.Mul(x3, x3)
+	u := cs.Mul(t, cs.Mul(circuit.Z, 2))
+
+	cs.AssertIsEqual(circuit.Y, cs.Add(x5, u))
+	return nil
+}
+
+func init() {
+	var circuit, good, bad, public pow5Circuit
+
+	good.X.Assign(2)
+	good.Z.Assign(3)
+	good.Y.Assign(248)
+
+	bad.X.Assign(2)
+	bad.Z.Assign(3)
+	bad.Y.Assign(247)
+
+	public.Y.Assign(248)
+
+	addEntry("pow5", &circuit, &good, &bad, &public)
+}
@@ -14,6 +14,8 @@
 
 package compiled
 
+import "sort"
+
 // LinearExpression represent a linear expression of variables
 type LinearExpression []Term
 
@@ -56,7 +58,34 @@ const (
 type SolvingMethod uint8
 
 // SingleOuput and BinaryDec are types of solving method for rank-1 constraints
+// TableLookup solves the O wire of a lookup constraint by reading it from its table
 const (
 	SingleOutput SolvingMethod = iota
 	BinaryDec
+	TableLookup
 )
+
+// WireIDs returns the sorted IDs of the wires of the constraint, without the ONE_WIRE
+func (r1c *R1C) WireIDs() []int {
+	var ids []int
+	for _, l := range []LinearExpression{r1c.L, r1c.R, r1c.O} {
+		for _, t := range l {
+			if t.VariableID() != 0 || t.VariableVisibility() != Public {
+				ids = append(ids, t.VariableID())
+			}
+		}
+	}
+	return sortUnique(ids)
+}
+
+// sortUnique sorts ids and removes the duplicates
+func sortUnique(ids []int) []int {
+	sort.Ints(ids)
+	res := ids[:0]
+	for i, id := range ids {
+		if i == 0 || id != ids[i-1] {
+			res = append(res, id)
+		}
+	}
+	return res
+}
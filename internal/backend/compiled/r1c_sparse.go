@@ -15,11 +15,33 @@
 package compiled
 
 // SparseR1C used to compute the wires
-// L+R+M[0]M[1]+O+k=0
+// L+R+M[0]M[1]+C[0]C[1]C[2]+O+k=0
 // if a Term is zero, it means the field doesn't exist (ex M=[0,0] means there is no multiplicative term)
+//
+// C is a custom cubic gate: C[0] and C[1] are on the same wire as L, and C[2] is on the
+// same wire as R, so the PLONK row is qlL+qrR+qmLR+qcLLR+qoO+qk=0.
+// When C is set, the wire to solve is always O.
+//
+// If Table is not zero, the constraint is a lookup: (L, R, O) must be an entry of the
+// lookup table Tables[Table-1] of the SparseR1CS, and all the coefficients are zero.
 type SparseR1C struct {
 	L, R, O Term
 	M       [2]Term
+	C       [3]Term
 	K       int // stores only the ID of the constant term that is used
 	Solver  SolvingMethod
+	Table   int // ID+1 of the lookup table (L, R, O) belongs to, 0 if it's not a lookup
+}
+
+// WireIDs returns the sorted IDs of the wires of the constraint
+// the terms with a zero coefficient are skipped, except for lookups.
+func (c *SparseR1C) WireIDs() []int {
+	var ids []int
+	terms := []Term{c.L, c.R, c.O, c.M[0], c.M[1], c.C[0], c.C[1], c.C[2]}
+	for i, t := range terms {
+		if t.CoeffID() != 0 || (c.Table != 0 && i < 3) {
+			ids = append(ids, t.VariableID())
+		}
+	}
+	return sortUnique(ids)
 }
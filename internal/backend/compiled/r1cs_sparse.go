@@ -33,6 +33,9 @@ type SparseR1CS struct {
 	Constraints []SparseR1C // list of PLONK constraints that yield an output (for example v3 == v1 * v2, return v3)
 	Assertions  []SparseR1C // list of PLONK constraints that yield no output (for example ensuring v1 == v2)
 
+	// Lookup tables, each entry is a triplet of IDs of Coeffs
+	Tables [][][3]int
+
 	// Logs (e.g. variables that have been printed using cs.Println)
 	Logs []LogEntry
 
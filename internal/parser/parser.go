@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/consensys/gnark/internal/backend/compiled"
 )
@@ -45,8 +46,40 @@ func appendName(baseName, name string) string {
 // LeafHandler is the handler function that will be called when Visit reaches leafs of the struct
 type LeafHandler func(visibility compiled.Visibility, name string, tValue reflect.Value) error
 
+// PathHandler is the handler function that will be called when VisitPaths reaches leafs of the struct
+//
+// path lists the names of the fields leading to the leaf (embedded structs add no name), and "[i]" for the i-th
+// element of a slice or array. e.g. ["MerkleProofs", "[0]", "[3]"]. It is only valid during the call.
+type PathHandler func(visibility compiled.Visibility, path []string, tValue reflect.Value) error
+
 // Visit using reflect, browse through exposed addressable fields from input, and calls handler() if leaf.type == target
 func Visit(input interface{}, baseName string, parentVisibility compiled.Visibility, handler LeafHandler, target reflect.Type) error {
+	return visit(input, baseName, nil, parentVisibility, func(visibility compiled.Visibility, name string, _ []string, tValue reflect.Value) error {
+		return handler(visibility, name, tValue)
+	}, target)
+}
+
+// VisitPaths browses input as Visit does, and calls handler() with the path of each leaf of type target
+func VisitPaths(input interface{}, handler PathHandler, target reflect.Type) error {
+	return visit(input, "", nil, compiled.Unset, func(visibility compiled.Visibility, _ string, path []string, tValue reflect.Value) error {
+		return handler(visibility, path, tValue)
+	}, target)
+}
+
+// PathName returns the name of the leaf at path, the names of the fields joined with ".", e.g. Path[0].Sibling
+// it names the variables of the JSON witnesses (see backend/witness) and of the gnarkd witness schemas
+func PathName(path []string) string {
+	var sb strings.Builder
+	for i, p := range path {
+		if i > 0 && !strings.HasPrefix(p, "[") {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(p)
+	}
+	return sb.String()
+}
+
+func visit(input interface{}, baseName string, path []string, parentVisibility compiled.Visibility, handler func(compiled.Visibility, string, []string, reflect.Value) error, target reflect.Type) error {
 
 	// types we are lOoutputoking for
 	// tVariable := reflect.TypeOf(frontend.Variable{})
@@ -63,7 +96,7 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 	case reflect.Struct:
 		switch tValue.Type() {
 		case target:
-			return handler(parentVisibility, baseName, tValue)
+			return handler(parentVisibility, baseName, path, tValue)
 		default:
 			for i := 0; i < tValue.NumField(); i++ {
 				field := tValue.Type().Field((i))
@@ -98,11 +131,15 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 				}
 
 				fullName := appendName(baseName, name)
+				fieldPath := path[:len(path):len(path)]
+				if name != "" {
+					fieldPath = append(fieldPath, name)
+				}
 
 				f := tValue.FieldByName(field.Name)
 				if f.CanAddr() && f.Addr().CanInterface() {
 					value := f.Addr().Interface()
-					if err := Visit(value, fullName, visibility, handler, target); err != nil {
+					if err := visit(value, fullName, fieldPath, visibility, handler, target); err != nil {
 						return err
 					}
 				} else {
@@ -125,7 +162,8 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 
 			val := tValue.Index(j)
 			if val.CanAddr() && val.Addr().CanInterface() {
-				if err := Visit(val.Addr().Interface(), appendName(baseName, strconv.Itoa(j)), parentVisibility, handler, target); err != nil {
+				elemPath := append(path[:len(path):len(path)], "["+strconv.Itoa(j)+"]")
+				if err := visit(val.Addr().Interface(), appendName(baseName, strconv.Itoa(j)), elemPath, parentVisibility, handler, target); err != nil {
 					return err
 				}
 			}